@@ -0,0 +1,122 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "sync"
+
+// batchState is an implementation of [State] that is passed to a
+// single call to [PageGetter.GetPage] when the [WithBatchedRequests]
+// option is used.  Updates and page requests are accumulated, in
+// order, and submitted as a single [bundle] once the call returns;
+// this considerably reduces the traffic on the update channel when a
+// page getter requests many pages at once, such as when paging ahead.
+// Marking the last page is always submitted immediately, so that
+// pages beyond it are canceled promptly.
+type batchState[T any] struct {
+	sync.Mutex
+
+	dp  *Depaginator[T] // The depaginator to submit updates to
+	ups bundle[T]       // Pending updates
+}
+
+// add adds updates to the pending bundle.
+func (bs *batchState[T]) add(ups ...update[T]) {
+	bs.Lock()
+	defer bs.Unlock()
+
+	bs.ups = append(bs.ups, ups...)
+}
+
+// flush submits the pending updates to the [Depaginator] as a single
+// update.
+func (bs *batchState[T]) flush() {
+	bs.Lock()
+	ups := bs.ups
+	bs.ups = nil
+	bs.Unlock()
+
+	if len(ups) > 0 {
+		bs.dp.update(ups)
+	}
+}
+
+// Update allows updating the total number of items, total number of
+// pages, or the items per page.  The arguments passed to Update
+// should be [TotalItems], [TotalPages], or [PerPage]; any other
+// argument types will be ignored.
+func (bs *batchState[T]) Update(updates ...any) {
+	for _, u := range updates {
+		switch update := u.(type) {
+		case TotalItems:
+			bs.add(totalItems[T](int(update)))
+		case TotalPages:
+			bs.add(totalPages[T](int(update)))
+		case PerPage:
+			bs.add(perPage[T](int(update)))
+		}
+	}
+}
+
+// Request requests the [Depaginator] retrieve a page.
+func (bs *batchState[T]) Request(idx int, req any) {
+	bs.add(pageRequest[T]{
+		idx: idx,
+		req: req,
+	})
+}
+
+// RequestRange requests the [Depaginator] retrieve the pages with
+// indexes from start up to, but not including, end.
+func (bs *batchState[T]) RequestRange(start, end int, reqFn func(idx int) any) {
+	var ups []update[T]
+	for idx := start; idx < end; idx++ {
+		req := pageRequest[T]{
+			idx: idx,
+		}
+		if reqFn != nil {
+			req.req = reqFn(idx)
+		}
+		ups = append(ups, req)
+	}
+
+	if len(ups) > 0 {
+		bs.add(ups...)
+	}
+}
+
+// RequestNext requests the [Depaginator] retrieve the page following
+// the highest page requested so far.
+func (bs *batchState[T]) RequestNext(req any) {
+	bs.add(nextRequest[T]{
+		req: req,
+	})
+}
+
+// MarkLast declares that the page with the specified index is the
+// final page.
+func (bs *batchState[T]) MarkLast(idx int) {
+	bs.add(lastPage[T](idx))
+	bs.flush()
+}
+
+// PerPage retrieves the current "per page" value for [Depaginator].
+// The pending updates are submitted first, so that any change to the
+// number of items per page made during the call is reflected.
+func (bs *batchState[T]) PerPage() int {
+	bs.flush()
+	return bs.dp.PerPage()
+}
@@ -0,0 +1,200 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drain collects the updates sent by a [Depaginator] constructed
+// for testing.
+func drain[T any](dp *Depaginator[T]) func() []update[T] {
+	done := make(chan []update[T])
+	go func() {
+		var ups []update[T]
+		for u := range dp.updates {
+			ups = append(ups, u)
+		}
+		done <- ups
+	}()
+
+	return func() []update[T] {
+		close(dp.updates)
+		return <-done
+	}
+}
+
+func TestBatchStateImplementsState(t *testing.T) {
+	assert.Implements(t, (*State)(nil), &batchState[string]{})
+}
+
+func TestBatchStateUpdatesCoalesced(t *testing.T) {
+	dp := &Depaginator[string]{
+		updates: make(chan update[string]),
+	}
+	result := drain(dp)
+	obj := &batchState[string]{
+		dp: dp,
+	}
+
+	obj.Update(TotalItems(10))
+	obj.Update(PerPage(3))
+	obj.flush()
+
+	assert.Equal(t, []update[string]{
+		bundle[string]{
+			totalItems[string](10),
+			perPage[string](3),
+		},
+	}, result())
+}
+
+func TestBatchStateRequestsBatched(t *testing.T) {
+	dp := &Depaginator[string]{
+		updates: make(chan update[string]),
+	}
+	result := drain(dp)
+	obj := &batchState[string]{
+		dp: dp,
+	}
+
+	obj.Update(TotalItems(10), "ignored", TotalPages(4), PerPage(3))
+	obj.Request(1, "one")
+	obj.RequestRange(2, 4, func(idx int) any { return idx })
+	obj.RequestNext("next")
+	obj.flush()
+
+	assert.Equal(t, []update[string]{
+		bundle[string]{
+			totalItems[string](10),
+			totalPages[string](4),
+			perPage[string](3),
+			pageRequest[string]{idx: 1, req: "one"},
+			pageRequest[string]{idx: 2, req: 2},
+			pageRequest[string]{idx: 3, req: 3},
+			nextRequest[string]{req: "next"},
+		},
+	}, result())
+	assert.Nil(t, obj.ups)
+}
+
+func TestBatchStateMarkLastBatched(t *testing.T) {
+	dp := &Depaginator[string]{
+		updates: make(chan update[string]),
+	}
+	result := drain(dp)
+	obj := &batchState[string]{
+		dp: dp,
+	}
+
+	obj.Request(1, "one")
+	obj.MarkLast(1)
+	obj.Request(2, "two")
+
+	assert.Equal(t, []update[string]{
+		bundle[string]{
+			pageRequest[string]{idx: 1, req: "one"},
+			lastPage[string](1),
+		},
+	}, result())
+	assert.Equal(t, bundle[string]{
+		pageRequest[string]{idx: 2, req: "two"},
+	}, obj.ups)
+}
+
+func TestBatchStateFlushEmpty(t *testing.T) {
+	dp := &Depaginator[string]{
+		updates: make(chan update[string]),
+	}
+	result := drain(dp)
+	obj := &batchState[string]{
+		dp: dp,
+	}
+
+	obj.RequestRange(3, 3, nil)
+	obj.flush()
+
+	assert.Empty(t, result())
+}
+
+func TestBatchStatePerPage(t *testing.T) {
+	dp := &Depaginator[string]{
+		perPage: 5,
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for u := range dp.updates {
+			u.applyUpdate(dp)
+		}
+	}()
+	obj := &batchState[string]{
+		dp: dp,
+	}
+
+	obj.Update(PerPage(10))
+	result := obj.PerPage()
+
+	close(dp.updates)
+	assert.Equal(t, 10, result)
+	assert.Nil(t, obj.ups)
+}
+
+func benchmarkRequests(b *testing.B, batch bool) {
+	const pageAhead = 100
+	dp := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	sends := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range dp.updates {
+			sends++
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var state State = dp
+		if batch {
+			state = &batchState[string]{
+				dp: dp,
+			}
+		}
+		for idx := 1; idx <= pageAhead; idx++ {
+			state.Request(idx, nil)
+		}
+		if bs, ok := state.(*batchState[string]); ok {
+			bs.flush()
+		}
+	}
+	close(dp.updates)
+	<-done
+
+	b.ReportMetric(float64(sends)/float64(b.N), "sends/op")
+}
+
+func BenchmarkRequestsUnbatched(b *testing.B) {
+	benchmarkRequests(b, false)
+}
+
+func BenchmarkRequestsBatched(b *testing.B) {
+	benchmarkRequests(b, true)
+}
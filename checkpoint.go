@@ -0,0 +1,99 @@
+// Copyright 2021 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// requestCheckpointVersion is the version of the checkpoint format
+// produced by [Depaginator.Checkpoint] when a request codec has been
+// set using the [WithRequestCodec] option.  The checkpoint consists of
+// the version byte, the length of the embedded page map checkpoint
+// as a varint, the page map checkpoint, the number of requests as a
+// varint, and then the page index and the length of the encoded
+// request as varints followed by the encoded request, for each
+// request in ascending order of page index.
+const requestCheckpointVersion = 2
+
+// encodeCheckpoint constructs a checkpoint including the encoded
+// requests of the pages not yet completed.
+func encodeCheckpoint(completed []byte, requests map[int][]byte) []byte {
+	data := []byte{requestCheckpointVersion}
+	data = binary.AppendUvarint(data, uint64(len(completed)))
+	data = append(data, completed...)
+
+	idxs := make([]int, 0, len(requests))
+	for idx := range requests {
+		idxs = append(idxs, idx)
+	}
+	sort.Ints(idxs)
+	data = binary.AppendUvarint(data, uint64(len(idxs)))
+	for _, idx := range idxs {
+		data = binary.AppendUvarint(data, uint64(idx))
+		data = binary.AppendUvarint(data, uint64(len(requests[idx])))
+		data = append(data, requests[idx]...)
+	}
+
+	return data
+}
+
+// decodeCheckpoint splits a checkpoint into the page map checkpoint
+// and the encoded requests.  A checkpoint produced without a request
+// codec is the page map checkpoint alone, which is returned
+// unchanged.  It returns an error wrapping [ErrInvalidCheckpoint] if
+// the checkpoint cannot be decoded.
+func decodeCheckpoint(data []byte) ([]byte, map[int][]byte, error) {
+	if len(data) == 0 || data[0] != requestCheckpointVersion {
+		return data, nil, nil
+	}
+	data = data[1:]
+
+	// next reads a varint, then the number of bytes it specifies if
+	// withData is set
+	bad := false
+	next := func(withData bool) (uint64, []byte) {
+		v, n := binary.Uvarint(data)
+		if bad || n <= 0 || withData && v > uint64(len(data)-n) {
+			bad = true
+			return 0, nil
+		}
+		data = data[n:]
+		if !withData {
+			return v, nil
+		}
+		chunk := data[:v]
+		data = data[v:]
+		return v, chunk
+	}
+
+	_, completed := next(true)
+	count, _ := next(false)
+	requests := map[int][]byte{}
+	for i := uint64(0); i < count && !bad; i++ {
+		idx, _ := next(false)
+		_, req := next(true)
+		requests[int(idx)] = req
+	}
+	if bad || len(data) != 0 {
+		return nil, nil, fmt.Errorf("%w: malformed requests", ErrInvalidCheckpoint)
+	}
+
+	return completed, requests, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeCheckpoint(t *testing.T) {
+	result := encodeCheckpoint([]byte{1, 2}, map[int][]byte{
+		300: []byte("b"),
+		3:   []byte("a"),
+	})
+
+	assert.Equal(t, []byte{
+		requestCheckpointVersion,
+		2, 1, 2,
+		2,
+		3, 1, 'a',
+		0xac, 0x02, 1, 'b',
+	}, result)
+}
+
+func TestDecodeCheckpointRoundTrip(t *testing.T) {
+	requests := map[int][]byte{
+		3:   []byte("a"),
+		300: []byte("bcd"),
+		7:   {},
+	}
+
+	completed, result, err := decodeCheckpoint(encodeCheckpoint([]byte{1, 2}, requests))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1, 2}, completed)
+	assert.Equal(t, requests, result)
+}
+
+func TestDecodeCheckpointPageMapOnly(t *testing.T) {
+	data := []byte{checkpointVersion, 64, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	completed, requests, err := decodeCheckpoint(data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, data, completed)
+	assert.Nil(t, requests)
+}
+
+func TestDecodeCheckpointMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{
+			name: "truncated page map",
+			data: []byte{requestCheckpointVersion, 5, 1, 2},
+		},
+		{
+			name: "missing count",
+			data: []byte{requestCheckpointVersion, 2, 1, 2},
+		},
+		{
+			name: "missing request",
+			data: []byte{requestCheckpointVersion, 2, 1, 2, 1, 3},
+		},
+		{
+			name: "truncated request",
+			data: []byte{requestCheckpointVersion, 2, 1, 2, 1, 3, 4, 'a'},
+		},
+		{
+			name: "trailing data",
+			data: []byte{requestCheckpointVersion, 2, 1, 2, 0, 9},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			completed, requests, err := decodeCheckpoint(test.data)
+
+			assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+			assert.Nil(t, completed)
+			assert.Nil(t, requests)
+		})
+	}
+}
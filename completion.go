@@ -0,0 +1,60 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "sync"
+
+// completionOrder reports the completion of item handling in item
+// index order.  Items may be handled concurrently and in any order;
+// the completion of each item is buffered until every item with a
+// lower index has also completed, at which point the whole contiguous
+// run is reported.
+type completionOrder struct {
+	sync.Mutex
+
+	fn   func(idx int) // Function to call with each completed item
+	next int           // Index of the next item to report
+	done map[int]bool  // Items completed out of order
+}
+
+// newCompletionOrder constructs a new completionOrder which reports
+// completions to the specified function.
+func newCompletionOrder(fn func(idx int)) *completionOrder {
+	return &completionOrder{
+		fn:   fn,
+		done: map[int]bool{},
+	}
+}
+
+// Complete records that the item with the specified index has been
+// handled, then reports it and any buffered items that follow it, if
+// every earlier item has been reported.  The reports are made while
+// holding the lock, so they are never concurrent.
+func (co *completionOrder) Complete(idx int) {
+	co.Lock()
+	defer co.Unlock()
+
+	if idx < co.next {
+		return
+	}
+	co.done[idx] = true
+	for co.done[co.next] {
+		delete(co.done, co.next)
+		co.fn(co.next)
+		co.next++
+	}
+}
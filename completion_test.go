@@ -0,0 +1,90 @@
+// Copyright 2021 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCompletionOrder(t *testing.T) {
+	result := newCompletionOrder(func(idx int) {})
+
+	assert.NotNil(t, result.fn)
+	assert.Equal(t, 0, result.next)
+	assert.Equal(t, map[int]bool{}, result.done)
+}
+
+func TestCompletionOrderCompleteInOrder(t *testing.T) {
+	var reported []int
+	obj := newCompletionOrder(func(idx int) {
+		reported = append(reported, idx)
+	})
+
+	obj.Complete(0)
+	obj.Complete(1)
+
+	assert.Equal(t, []int{0, 1}, reported)
+	assert.Equal(t, 2, obj.next)
+	assert.Empty(t, obj.done)
+}
+
+func TestCompletionOrderCompleteOutOfOrder(t *testing.T) {
+	var reported []int
+	obj := newCompletionOrder(func(idx int) {
+		reported = append(reported, idx)
+	})
+
+	obj.Complete(2)
+	obj.Complete(1)
+
+	assert.Empty(t, reported)
+	assert.Equal(t, map[int]bool{1: true, 2: true}, obj.done)
+
+	obj.Complete(0)
+
+	assert.Equal(t, []int{0, 1, 2}, reported)
+	assert.Equal(t, 3, obj.next)
+	assert.Empty(t, obj.done)
+}
+
+func TestCompletionOrderCompleteGap(t *testing.T) {
+	var reported []int
+	obj := newCompletionOrder(func(idx int) {
+		reported = append(reported, idx)
+	})
+
+	obj.Complete(0)
+	obj.Complete(2)
+
+	assert.Equal(t, []int{0}, reported)
+	assert.Equal(t, map[int]bool{2: true}, obj.done)
+}
+
+func TestCompletionOrderCompleteDuplicate(t *testing.T) {
+	var reported []int
+	obj := newCompletionOrder(func(idx int) {
+		reported = append(reported, idx)
+	})
+
+	obj.Complete(0)
+	obj.Complete(0)
+
+	assert.Equal(t, []int{0}, reported)
+	assert.Empty(t, obj.done)
+}
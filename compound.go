@@ -0,0 +1,110 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "context"
+
+// CompoundPage describes the result of a single fetch that returns
+// several logical pages at once.  The Items field contains all the
+// items, in order, and the Bounds field contains the offsets within
+// Items at which each logical page after the first begins.  For
+// instance, a CompoundPage with 5 items and Bounds of []int{3}
+// contains two logical pages, the first with 3 items and the second
+// with 2 items.
+type CompoundPage[T any] struct {
+	Items  []T   // All the items returned by the fetch
+	Bounds []int // Offsets at which subsequent logical pages begin
+}
+
+// split splits the compound page into its logical pages, returning
+// an [update] that handles each logical page.  The first logical page
+// has the index of the request; subsequent logical pages have
+// consecutive indexes.  Totals are not inferred from a short logical
+// page followed by others, as the logical pages need not be full.
+func (cp CompoundPage[T]) split(req PageRequest) (update[T], error) {
+	// Handle the simple case
+	if len(cp.Bounds) == 0 {
+		return itemHandler[T]{
+			idx:  req.PageIndex,
+			req:  req.Request,
+			page: cp.Items,
+		}, nil
+	}
+
+	// Construct a bundle
+	ups := bundle[T]{}
+	start := 0
+	for i := 0; i <= len(cp.Bounds); i++ {
+		end := len(cp.Items)
+		if i < len(cp.Bounds) {
+			end = cp.Bounds[i]
+		}
+		if end < start || end > len(cp.Items) {
+			return nil, ErrInvalidBounds
+		}
+
+		handler := itemHandler[T]{
+			idx:  req.PageIndex + i,
+			req:  req.Request,
+			page: cp.Items[start:end],
+			more: i < len(cp.Bounds),
+		}
+		if i > 0 {
+			ups = append(ups, logicalPage[T]{handler})
+		} else {
+			ups = append(ups, handler)
+		}
+		start = end
+	}
+
+	return ups, nil
+}
+
+// CompoundPageGetter is an interface that can be additionally
+// implemented by [PageGetter] implementations for APIs which may
+// return several logical pages in a single response.  If the
+// [PageGetter] passed to [Depaginate] implements CompoundPageGetter,
+// the GetCompoundPage method will be called instead of
+// [PageGetter.GetPage].  The logical pages after the first are
+// assigned consecutive page indexes, and will not subsequently be
+// requested.
+type CompoundPageGetter[T any] interface {
+	// GetCompoundPage is a page retriever function.  It is passed
+	// the [Depaginator] object and a [PageRequest] object describing
+	// the page to request, and returns a [CompoundPage] containing
+	// one or more logical pages, or an error.
+	GetCompoundPage(ctx context.Context, depag State, req PageRequest) (CompoundPage[T], error)
+}
+
+// logicalPage is an [update] that handles a logical page after the
+// first returned by a [CompoundPageGetter].  The page is marked as
+// having been requested, without actually requesting it, so it is
+// not subsequently retrieved; if it has already been requested, such
+// as by the [CompoundPageGetter] itself, its items are left to be
+// handled by that request, so they are not handled twice.
+type logicalPage[T any] struct {
+	itemHandler[T]
+}
+
+// applyUpdate applies an update.
+func (u logicalPage[T]) applyUpdate(depag *Depaginator[T]) {
+	if depag.pages.CheckAndSet(u.idx) {
+		return
+	}
+
+	u.itemHandler.applyUpdate(depag)
+}
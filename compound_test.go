@@ -0,0 +1,271 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compoundPager struct {
+	sync.Mutex
+
+	fetched []int // Pages fetched
+}
+
+func (cp *compoundPager) GetPage(_ context.Context, _ State, _ PageRequest) ([]string, error) {
+	panic("GetPage called on compound pager")
+}
+
+func (cp *compoundPager) GetCompoundPage(_ context.Context, depag State, req PageRequest) (CompoundPage[string], error) {
+	cp.Lock()
+	defer cp.Unlock()
+	cp.fetched = append(cp.fetched, req.PageIndex)
+
+	depag.Update(PerPage(3))
+	switch req.PageIndex {
+	case 0:
+		depag.Request(2, nil)
+		return CompoundPage[string]{
+			Items:  []string{"0", "1", "2", "3", "4", "5"},
+			Bounds: []int{3},
+		}, nil
+
+	case 2:
+		return CompoundPage[string]{
+			Items: []string{"6", "7"},
+		}, nil
+	}
+
+	return CompoundPage[string]{}, assert.AnError
+}
+
+// compoundPagerFunc is a [CompoundPageGetter] implemented by a
+// function.
+type compoundPagerFunc func(ctx context.Context, depag State, req PageRequest) (CompoundPage[string], error)
+
+func (f compoundPagerFunc) GetPage(_ context.Context, _ State, _ PageRequest) ([]string, error) {
+	panic("GetPage called on compound pager")
+}
+
+func (f compoundPagerFunc) GetCompoundPage(ctx context.Context, depag State, req PageRequest) (CompoundPage[string], error) {
+	return f(ctx, depag, req)
+}
+
+func TestCompoundPageSplitSimple(t *testing.T) {
+	obj := CompoundPage[string]{
+		Items: []string{"foo", "bar"},
+	}
+
+	result, err := obj.split(PageRequest{
+		PageIndex: 3,
+		Request:   "three",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, itemHandler[string]{
+		idx:  3,
+		req:  "three",
+		page: []string{"foo", "bar"},
+	}, result)
+}
+
+func TestCompoundPageSplitBounds(t *testing.T) {
+	obj := CompoundPage[string]{
+		Items:  []string{"foo", "bar", "baz", "bink", "qux"},
+		Bounds: []int{2, 4},
+	}
+
+	result, err := obj.split(PageRequest{
+		PageIndex: 3,
+		Request:   "three",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, bundle[string]{
+		itemHandler[string]{
+			idx:  3,
+			req:  "three",
+			page: []string{"foo", "bar"},
+			more: true,
+		},
+		logicalPage[string]{itemHandler[string]{
+			idx:  4,
+			req:  "three",
+			page: []string{"baz", "bink"},
+			more: true,
+		}},
+		logicalPage[string]{itemHandler[string]{
+			idx:  5,
+			req:  "three",
+			page: []string{"qux"},
+		}},
+	}, result)
+}
+
+func TestCompoundPageSplitOutOfOrder(t *testing.T) {
+	obj := CompoundPage[string]{
+		Items:  []string{"foo", "bar", "baz", "bink", "qux"},
+		Bounds: []int{4, 2},
+	}
+
+	result, err := obj.split(PageRequest{})
+
+	assert.Same(t, ErrInvalidBounds, err)
+	assert.Nil(t, result)
+}
+
+func TestCompoundPageSplitOutOfRange(t *testing.T) {
+	obj := CompoundPage[string]{
+		Items:  []string{"foo", "bar", "baz", "bink", "qux"},
+		Bounds: []int{6},
+	}
+
+	result, err := obj.split(PageRequest{})
+
+	assert.Same(t, ErrInvalidBounds, err)
+	assert.Nil(t, result)
+}
+
+func TestLogicalPageImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), logicalPage[string]{})
+}
+
+func TestLogicalPageApplyUpdate(t *testing.T) {
+	obj := logicalPage[string]{itemHandler[string]{
+		idx:  3,
+		page: []string{"foo"},
+	}}
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		perPage:   1,
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) {}),
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.True(t, depag.pages.CheckAndSet(3))
+	assert.Equal(t, 1, depag.buffered)
+}
+
+func TestLogicalPageApplyUpdateRequested(t *testing.T) {
+	obj := logicalPage[string]{itemHandler[string]{
+		idx:  3,
+		page: []string{"foo"},
+	}}
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		perPage:   1,
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) {}),
+	}
+	depag.pages.CheckAndSet(3)
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 0, depag.buffered)
+	assert.Empty(t, depag.history)
+}
+
+func TestDepaginateCompound(t *testing.T) {
+	ctx := context.Background()
+	pager := &compoundPager{}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7"}, result.Items)
+	assert.ElementsMatch(t, []int{0, 2}, pager.fetched)
+}
+
+func TestDepaginateCompoundRequested(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	fetched := map[int]int{}
+	pager := compoundPagerFunc(func(_ context.Context, depag State, req PageRequest) (CompoundPage[string], error) {
+		mu.Lock()
+		fetched[req.PageIndex]++
+		mu.Unlock()
+		depag.Update(PerPage(2))
+		switch req.PageIndex {
+		case 0:
+			// Request the page that is also returned as a logical
+			// page, as an API returning extra pages opportunistically
+			// might
+			depag.Request(1, nil)
+			return CompoundPage[string]{
+				Items:  []string{"0", "1", "2", "3"},
+				Bounds: []int{2},
+			}, nil
+
+		case 1:
+			depag.MarkLast(1)
+			return CompoundPage[string]{
+				Items: []string{"2", "3"},
+			}, nil
+		}
+		return CompoundPage[string]{}, assert.AnError
+	})
+	var count atomic.Int32
+	result := &ListHandler[string]{}
+	handler := HandlerFunc[string](func(ctx context.Context, idx int, item string) {
+		count.Add(1)
+		result.Handle(ctx, idx, item)
+	})
+	result.Start(ctx, 0, 0, 0)
+
+	d := Depaginate[string](ctx, pager, handler)
+	err := d.Wait()
+	result.Done(ctx, d.TotalItems(), d.TotalPages(), d.PerPage())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), count.Load())
+	assert.Equal(t, []string{"0", "1", "2", "3"}, result.Items)
+}
+
+func TestDepaginateCompoundShortLogicalPage(t *testing.T) {
+	ctx := context.Background()
+	pager := compoundPagerFunc(func(_ context.Context, depag State, req PageRequest) (CompoundPage[string], error) {
+		depag.Update(PerPage(3))
+		if req.PageIndex != 0 {
+			return CompoundPage[string]{}, assert.AnError
+		}
+
+		// The first logical page is short, but is followed by a
+		// full one; only the last is taken to end the iteration
+		return CompoundPage[string]{
+			Items:  []string{"0", "1", "3", "4", "5", "6", "7"},
+			Bounds: []int{2, 5},
+		}, nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.TotalPages())
+	assert.Equal(t, 8, d.TotalItems())
+	assert.Equal(t, []string{"0", "1", "", "3", "4", "5", "6", "7"}, result.Items)
+}
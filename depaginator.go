@@ -30,7 +30,14 @@ package depaginator
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // PageRequest describes a request for a specific page.  Most of the
@@ -43,6 +50,56 @@ type PageRequest struct {
 	Request   any // The actual data needed to request the page
 }
 
+// RunResult summarizes a completed iteration.  It is returned by
+// [Depaginator.Result], and passed to the function set by the
+// [WithSummary] option.
+type RunResult struct {
+	TotalItems   int           // Total number of items
+	TotalPages   int           // Total number of pages
+	PerPage      int           // Items per page
+	PagesFetched int           // Number of page retrievals completed
+	ItemsHandled int           // Number of items handled
+	Errors       []PageError   // Errors encountered
+	Duration     time.Duration // Time taken by the iteration
+}
+
+// Stats is the summary of a completed iteration returned by
+// [Depaginator.WaitStats].  It is identical to [RunResult].
+type Stats = RunResult
+
+// Config describes the effective configuration of an iteration,
+// after the options passed to [Depaginate] have been applied.  It is
+// returned by [Depaginator.Config], and is intended for logging.
+type Config struct {
+	TotalItems        int  // Total number of items (hint)
+	TotalPages        int  // Total number of pages (hint)
+	PerPage           int  // Items per page (hint)
+	Capacity          int  // Capacity of the update queue
+	MaxConcurrency    int  // Maximum concurrent page retrievals; 0 or less if unlimited
+	HandleConcurrency int  // Number of item handling workers; 0 or less if unlimited
+	RetryAttempts     int  // Maximum attempts to retrieve a page
+	RetryBudget       int  // Maximum retries across all pages; 0 or less if unlimited
+	Limit             int  // Maximum number of items to handle; 0 or less if unlimited
+	AutoStrategy      bool // Automatic fetch strategy enabled
+	FailFast          bool // Iteration stops on the first error
+}
+
+// String returns a description of the configuration, suitable for
+// logging.
+func (c Config) String() string {
+	return fmt.Sprintf("totalItems=%d totalPages=%d perPage=%d capacity=%d maxConcurrency=%d handleConcurrency=%d retryAttempts=%d retryBudget=%d limit=%d autoStrategy=%t failFast=%t",
+		c.TotalItems, c.TotalPages, c.PerPage, c.Capacity, c.MaxConcurrency, c.HandleConcurrency, c.RetryAttempts, c.RetryBudget, c.Limit, c.AutoStrategy, c.FailFast)
+}
+
+// Totals describes the totals known to an iteration at a particular
+// moment.  It is passed to the function set by the [WithHandleGate]
+// option.
+type Totals struct {
+	TotalItems int // Total number of items; 0 if not known
+	TotalPages int // Total number of pages; 0 if not known
+	PerPage    int // Items per page; 0 if not known
+}
+
 // Depaginator is returned by the [Depaginate] function to allow the
 // caller to wait for the iteration to complete.  This object is also
 // passed to [PageGetter.GetPage], and may be used to call
@@ -50,30 +107,115 @@ type PageRequest struct {
 // of items/pages or to request fetching additional pages,
 // respectively.
 type Depaginator[T any] struct {
-	ctx        context.Context // A context for calls
-	errors     []error         // Errors encountered
-	totalItems int             // Total number of items
-	totalPages int             // Total number of pages
-	perPage    int             // Items per page
-	pager      PageGetter[T]   // Object to retrieve pages with
-	handler    Handler[T]      // Object to use to handle items
-	starter    Starter         // Optional object to start iteration
-	updater    Updater         // Optional object to notify updates to items/pages
-	doner      Doner           // Optional object to notify end iteration
-
-	cancelers map[int]context.CancelFunc // Mapping of page index to cancel function
-	pages     *pageMap                   // Bitmap of requested pages
-	wg        *sync.WaitGroup            // A wait group for Wait to wait upon
-	updates   chan update[T]             // Updates to process
-	done      chan struct{}              // Used to signal the daemon has exited
+	ctx        context.Context       // A context for calls
+	runCtx     context.Context       // A context for page retrievals
+	abort      context.CancelFunc    // Cancels the page retrievals
+	errors     []error               // Errors encountered
+	totalItems int                   // Total number of items
+	totalPages int                   // Total number of pages
+	perPage    int                   // Items per page
+	pager      PageGetter[T]         // Object to retrieve pages with
+	compound   CompoundPageGetter[T] // Optional object to retrieve compound pages
+	handler    Handler[T]            // Object to use to handle items
+	stateful   StatefulHandler[T]    // Optional object to handle items with the state
+	starter    Starter               // Optional object to start iteration
+	updater    Updater               // Optional object to notify updates to items/pages
+	doner      Doner                 // Optional object to notify end iteration
+	committer  Committer             // Optional object to commit or roll back
+	results    *resultHandler[T]     // Optional handler to collect all items
+	scheduler  Scheduler             // Optional object to run tasks
+
+	auto      bool                                             // Use the automatic fetch strategy
+	sparse    bool                                             // Empty pages do not end the iteration
+	compact   bool                                             // Compact the map of requested pages
+	batch     bool                                             // Bundle updates and requests until GetPage returns
+	partial   bool                                             // Report partial results on cancellation
+	failFast  bool                                             // Stop the iteration on the first error
+	maxErrors int                                              // Maximum page errors tolerated
+	dryRun    bool                                             // Plan the pages without retrieving them
+	inference int                                              // Inference mode
+	budget    int64                                            // Maximum bytes to fetch
+	limit     int                                              // Maximum number of items to handle
+	maxPages  int                                              // Maximum number of pages to retrieve
+	attempts  int                                              // Maximum attempts to retrieve a page
+	backoff   func(attempt int) time.Duration                  // Optional function to compute retry delays
+	retries   int                                              // Maximum retries across all pages
+	order     func(pageLen int) []int                          // Optional function to order items within a page
+	sizeOf    func(items []T) int64                            // Optional function to compute page size
+	postPage  func(state State, req PageRequest, items []T)    // Optional function to call after each page
+	summary   func(RunResult)                                  // Optional function to call with the summary
+	activity  time.Duration                                    // Maximum time between retrieved pages
+	timeout   time.Duration                                    // Maximum time to handle an item
+	decorator func(req PageRequest) PageRequest                // Optional function to decorate requests
+	overlap   func(existing, incoming PageRequest) PageRequest // Optional function to resolve overlapping requests
+	healthy   func() bool                                      // Optional function to check downstream health
+	recorder  func(updateType string)                          // Optional function to record updates
+	gate      func(idx int, totals Totals) bool                // Optional function to gate item handling
+	onError   errorHook                                        // Optional function to call with page errors
+	ctxErrors bool                                             // Call onError for context errors too
+	limiter   *rate.Limiter                                    // Optional rate limiter for page retrievals
+	tracer    Tracer                                           // Optional tracer for page retrievals
+	log       Logger                                           // Optional logger for the iteration lifecycle
+	metrics   Metrics                                          // Optional collector of metrics
+	encodeReq func(req any) ([]byte, error)                    // Optional function to encode requests in checkpoints
+
+	marked   bool            // Last page was explicitly marked
+	inferred bool            // Totals have been inferred from a short page
+	failed   bool            // An error stopped the iteration
+	failures int             // Number of page errors recorded
+	frontier int             // Highest page requested so far
+	fanout   int             // Total pages when automatic fan-out last ran
+	received map[int]int     // Item counts of received pages
+	spent    int64           // Bytes fetched so far
+	fetched  int             // Number of page retrievals completed
+	buffered int             // Number of pages fetched but not yet handled
+	admitted int             // Number of retrievals admitted by the pacer
+	waiting  []chan struct{} // Retrievals waiting to be admitted by the pacer
+	handled  atomic.Int64    // Number of items handled
+	claimed  atomic.Int64    // Number of items dispatched under the limit
+	active   atomic.Int64    // Number of page retrievals in progress
+	retried  atomic.Int64    // Number of retries performed under the budget
+	history  []PageMeta      // Metadata observed for each page
+	planned  []int           // Pages planned by a dry run
+	start    time.Time       // Time the iteration started
+	elapsed  time.Duration   // Time taken by the iteration
+	config   Config          // Effective configuration
+	waited   sync.Once       // Ensures the iteration is only finished once
+	final    sync.Mutex      // Guards the fields set after the daemon exits
+	result   error           // Errors returned by Wait
+
+	idle   timer                   // Optional timer to cancel a stalled iteration
+	resets atomic.Int64            // Number of times the timer was reset
+	cancel context.CancelCauseFunc // Cancels the iteration when stalled
+
+	cancelers  map[int]context.CancelFunc // Mapping of page index to cancel function
+	overlaps   map[int]*overlapSlot       // Mapping of page index to pending request
+	pages      *pageMap                   // Bitmap of requested pages
+	completed  *pageMap                   // Bitmap of pages whose items have been handled
+	retrieved  pageMap                    // Bitmap of pages successfully retrieved
+	pageErrs   map[int]error              // Errors of pages whose retrieval failed
+	requests   map[int]any                // Requests of pages not yet handled, for checkpoints
+	slots      chan struct{}              // Optional semaphore limiting page retrievals
+	ahead      int                        // Optional limit on pages retrieved ahead of handling
+	totals     atomic.Pointer[Totals]     // Totals published for the handle gate
+	wg         *sync.WaitGroup            // A wait group for Wait to wait upon
+	workers    *taskQueue                 // Optional queue of item handling tasks
+	inOrder    *pageOrder                 // Optional queue of pages to handle in order
+	completion *completionOrder           // Optional reporter of completed items in order
+	stream     chan PageResult[T]         // Optional channel of retrieved pages
+	sampler    chan struct{}              // Closed to stop the concurrency sampler
+	sampled    chan struct{}              // Closed when the concurrency sampler exits
+	updates    chan update[T]             // Updates to process
+	done       chan struct{}              // Used to signal the daemon has exited
 }
 
 // Depaginate is a tool for iterating over all items in a paginated
 // response.  It uses goroutines to perform its work, and is capable
 // of issuing requests for every available page simultaneously, so
-// callers should ensure the [PageGetter.GetPage] routine passed to
-// Depaginate incorporates some sort of limiter to ensure they don't
-// overwhelm any rate limits that may be set on the target API.  The
+// callers should pass the [MaxConcurrency] option, or ensure the
+// [PageGetter.GetPage] routine passed to Depaginate incorporates some
+// sort of limiter, to ensure they don't overwhelm any rate limits
+// that may be set on the target API.  The
 // [Handler.Handle] method will be called for each item.  Note that
 // Depaginate returns a [Depaginator], and the calling application is
 // expected to call [Depaginator.Wait].
@@ -82,54 +224,230 @@ func Depaginate[T any](ctx context.Context, pager PageGetter[T], handler Handler
 	o := options{
 		capacity: DefaultCapacity,
 	}
-	if tmp, ok := handler.(Starter); ok {
+	var base any = handler
+	stateful, isStateful := handler.(statefulHandler[T])
+	if isStateful {
+		base = stateful.handler
+	}
+	if tmp, ok := base.(Starter); ok {
 		o.starter = tmp
 	}
-	if tmp, ok := handler.(Updater); ok {
+	if tmp, ok := base.(Updater); ok {
 		o.updater = tmp
 	}
-	if tmp, ok := handler.(Doner); ok {
+	if tmp, ok := base.(Doner); ok {
 		o.doner = tmp
 	}
+	if tmp, ok := base.(Committer); ok {
+		o.committer = tmp
+	}
+
+	// Check if the pager can return compound pages
+	compound, _ := pager.(CompoundPageGetter[T])
 
 	// Parse the provided options
 	for _, opt := range opts {
 		opt.apply(&o)
 	}
+	err := o.validate()
+
+	// Set up a cancelable context for the activity timeout
+	var cancel context.CancelCauseFunc
+	if o.activity > 0 {
+		ctx, cancel = context.WithCancelCause(ctx)
+	}
+
+	// Set up a cancelable context for the page retrievals
+	runCtx, abort := context.WithCancel(ctx)
 
 	// Construct the depaginator
 	dp := &Depaginator[T]{
 		ctx:        ctx,
+		runCtx:     runCtx,
+		abort:      abort,
 		pager:      pager,
+		compound:   compound,
 		totalItems: o.totalItems,
 		totalPages: o.totalPages,
 		perPage:    o.perPage,
 		handler:    handler,
+		stateful:   stateful.handler,
 		starter:    o.starter,
 		updater:    o.updater,
 		doner:      o.doner,
-		cancelers:  map[int]context.CancelFunc{},
+		committer:  o.committer,
+		scheduler:  o.scheduler,
+		auto:       o.auto,
+		sparse:     o.allowEmpty,
+		compact:    o.compact,
+		batch:      o.batch,
+		partial:    o.partial,
+		failFast:   o.failFast,
+		maxErrors:  o.maxErrors,
+		dryRun:     o.dryRun,
+		inference:  o.inference,
+		received:   map[int]int{},
+		summary:    o.summary,
+		start:      time.Now(),
+		budget:     o.budget,
+		limit:      o.limit,
+		maxPages:   o.maxPages,
+		config:     o.config(),
+		attempts:   o.attempts,
+		backoff:    o.backoff,
+		retries:    o.retries,
+		order:      o.order,
+		activity:   o.activity,
+		timeout:    o.timeout,
+		decorator:  o.decorator,
+		overlap:    o.overlap,
+		healthy:    o.healthy,
+		recorder:   o.recorder,
+		gate:       o.gate,
+		onError:    o.onError,
+		ctxErrors:  o.ctxErrors,
+		limiter:    o.limiter,
+		tracer:     o.tracer,
+		log:        o.lifecycle,
+		metrics:    o.metrics,
+		encodeReq:  o.encodeReq,
+		cancel:     cancel,
+		cancelers:  newCancelers(o.totalPages),
 		pages:      &pageMap{},
 		wg:         &sync.WaitGroup{},
 		updates:    make(chan update[T], o.capacity),
 		done:       make(chan struct{}),
 	}
 
-	// Initialize the handler if required
+	// Set up the concurrency limit
+	if o.maxActive > 0 {
+		dp.slots = make(chan struct{}, o.maxActive)
+	}
+
+	// Pace the page retrievals for handlers which require it
+	if tmp, ok := base.(pacer); ok {
+		dp.ahead = tmp.ahead()
+	}
+
+	// Record the pages handled if checkpoints are in use, restoring
+	// those handled by a previous iteration
+	var resumed resume[T]
+	if o.recordCP {
+		dp.completed = &pageMap{}
+	}
+	if err == nil && o.checkpoint != nil {
+		resumed, err = dp.restore(o.checkpoint, o.decodeReq)
+	}
+
+	// Set up in-order handling
+	if o.inOrder {
+		dp.inOrder = newPageOrder()
+	}
+
+	// Set up ordered completion reporting
+	if o.completion != nil {
+		dp.completion = newCompletionOrder(o.completion)
+	}
+
+	// Set up page streaming
+	if o.stream {
+		dp.stream = make(chan PageResult[T])
+	}
+
+	// Set up the byte budget
+	if sizeOf, ok := o.sizeOf.(func(items []T) int64); ok {
+		dp.sizeOf = sizeOf
+	} else if o.sizeOf != nil && err == nil {
+		err = mismatch[T]("WithByteBudget", o.sizeOf)
+	}
+
+	// Set up the post-page hook
+	if postPage, ok := o.postPage.(func(state State, req PageRequest, items []T)); ok {
+		dp.postPage = postPage
+	} else if o.postPage != nil && err == nil {
+		err = mismatch[T]("WithPostPageHook", o.postPage)
+	}
+
+	// Set up the result callback
+	if callback, ok := o.result.(func(items []T)); ok {
+		dp.results = &resultHandler[T]{
+			callback: callback,
+		}
+	}
+
+	// Publish the initial totals for the handle gate
+	if dp.gate != nil {
+		dp.publish()
+	}
+
+	// Initialize the handler if required, reconciling inconsistent
+	// hints so the handler does not over-allocate
+	startItems, startPages := o.reconcile()
 	if dp.starter != nil {
-		dp.starter.Start(ctx, dp.totalItems, dp.totalPages, dp.perPage)
+		dp.starter.Start(ctx, startItems, startPages, dp.perPage)
+	}
+	if dp.results != nil {
+		dp.results.Start(ctx, startItems, startPages, dp.perPage)
+	}
+
+	// Start the item handling workers; with a scheduler, they are
+	// started as the pages are queued
+	if o.workers > 0 {
+		dp.workers = newTaskQueue(o.workers)
+		for i := 0; i < o.workers && dp.scheduler == nil; i++ {
+			go dp.worker()
+		}
+	}
+
+	// Start the concurrency sampler
+	if o.sampling > 0 && o.sampler != nil {
+		dp.sampler = make(chan struct{})
+		dp.sampled = make(chan struct{})
+		go dp.sample(o.sampling, o.sampler)
+	}
+
+	// Start the activity timer
+	if dp.cancel != nil {
+		dp.idle = afterFunc(dp.activity, dp.stall)
+	}
+
+	// Report conflicting options; no pages will be requested
+	if err != nil {
+		dp.errors = append(dp.errors, err)
 	}
 
+	// Start the daemon; this must be done before issuing the first
+	// request, so that the updates sent by the first page retrieval
+	// can be drained even if they exceed the capacity of the queue
+	go dp.daemon()
+
 	// Issue the first request; can't use Depaginator.Request because
 	// of a race: the update could be sitting in the queue, not yet
 	// processed by the daemon, and Depaginator.Wait could be called.
-	pageRequest[T]{
-		idx: 0,
-		req: o.initReq,
-	}.applyUpdate(dp)
+	// This is safe to do while the daemon is running, as the daemon
+	// does not touch the state until it receives an update, and no
+	// updates can be sent until the page retrieval is started.  Note
+	// that this marks page 0 as requested before any updates are
+	// processed, so any concurrent request for page 0 will be ignored
+	// as a duplicate.
+	if err == nil {
+		pageRequest[T]{
+			idx: 0,
+			req: o.initReq,
+		}.applyUpdate(dp)
+	}
 
-	// Start the daemon
-	go dp.daemon()
+	// Request the pages recorded in the checkpoint; this must be done
+	// by the daemon, as the first page retrieval is now running
+	if len(resumed) > 0 {
+		dp.wg.Add(1)
+		dp.update(resumed)
+	}
+
+	// Signal that the iteration is running
+	if o.ready != nil {
+		close(o.ready)
+	}
 
 	return dp
 }
@@ -139,53 +457,518 @@ func Depaginate[T any](ctx context.Context, pager PageGetter[T], handler Handler
 func (dp *Depaginator[T]) daemon() {
 	defer close(dp.done)
 	for u := range dp.updates {
-		// Save original metadata
-		origItems, origPages, origPer := dp.totalItems, dp.totalPages, dp.perPage
+		// Are we done?
+		if _, ok := u.(stop[T]); ok {
+			dp.settle()
+			return
+		}
+
+		dp.process(u)
+	}
+}
+
+// process processes a single update on the daemon goroutine.  If the
+// update panics, the panic is recorded as an error wrapping
+// [ErrPanic] and the page retrievals are canceled, so that the
+// iteration winds down rather than leaving [Depaginator.Wait]
+// waiting on a dead daemon.
+func (dp *Depaginator[T]) process(u update[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			dp.errors = append(dp.errors, fmt.Errorf("%w: %s: %v", ErrPanic, updateName(u), r))
+			if dp.abort != nil {
+				dp.abort()
+			}
+		}
+	}()
+
+	// Record the update
+	if dp.recorder != nil {
+		dp.recorder(updateName(u))
+	}
 
-		// Apply the update
-		u.applyUpdate(dp)
+	// Save original metadata
+	origItems, origPages, origPer := dp.totalItems, dp.totalPages, dp.perPage
 
-		// If there were any changes, call the updater
-		if dp.updater != nil && (origItems != dp.totalItems || origPages != dp.totalPages || origPer != dp.perPage) {
+	// Apply the update
+	u.applyUpdate(dp)
+
+	// If there were any changes, call the updaters and publish the
+	// totals for the handle gate
+	if origItems != dp.totalItems || origPages != dp.totalPages || origPer != dp.perPage {
+		if dp.updater != nil {
 			dp.updater.Update(dp.ctx, dp.totalItems, dp.totalPages, dp.perPage)
 		}
+		if dp.results != nil {
+			dp.results.Update(dp.ctx, dp.totalItems, dp.totalPages, dp.perPage)
+		}
+		if dp.gate != nil {
+			dp.publish()
+		}
+	}
+}
+
+// publish publishes the current totals, allowing them to be read by
+// the goroutines handling items without involving the daemon.
+func (dp *Depaginator[T]) publish() {
+	dp.totals.Store(&Totals{
+		TotalItems: dp.totalItems,
+		TotalPages: dp.totalPages,
+		PerPage:    dp.perPage,
+	})
+}
+
+// published returns the totals most recently published by
+// [Depaginator.publish].
+func (dp *Depaginator[T]) published() Totals {
+	if totals := dp.totals.Load(); totals != nil {
+		return *totals
+	}
+
+	return Totals{}
+}
+
+// worker is a goroutine that runs item handling tasks.  It is used
+// when the [WithHandleConcurrency] option is set without a
+// [Scheduler].
+func (dp *Depaginator[T]) worker() {
+	for {
+		task, ok := dp.workers.Get()
+		if !ok {
+			return
+		}
+		task()
+	}
+}
+
+// sample is a goroutine that periodically reports the number of page
+// retrievals in progress.  It is used when the
+// [WithConcurrencySampler] option is set.
+func (dp *Depaginator[T]) sample(interval time.Duration, fn func(active int)) {
+	defer close(dp.sampled)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-dp.sampler:
+			return
+
+		case <-ticker.C:
+			fn(int(dp.active.Load()))
+		}
 	}
 }
 
 // Wait waits for the iteration to complete.  It returns the errors
 // encountered during the iteration, wrapped by [errors.Join].  Each
 // error in the list is a [PageError], which bundles together the
-// error and the corresponding page request.
+// error and the corresponding page request, except for [ErrStalled],
+// which is reported if the iteration was canceled by the
+// [WithActivityTimeout] option, [ErrConflictingOptions], which is
+// reported if options that may not be combined were passed to
+// [Depaginate], errors wrapping [ErrInvalidCheckpoint], which are
+// reported if the checkpoint passed to [WithCheckpoint] could not be
+// decoded, errors wrapping [ErrPanic], which are reported if
+// processing an update panicked, [ItemError], which is reported if
+// handling an item exceeded the timeout set by [WithHandleTimeout],
+// and any error returned by [Committer.Commit].  Wait may be called
+// more than once, including concurrently; every call returns once the
+// iteration is complete, with the same result.
 func (dp *Depaginator[T]) Wait() error {
+	_, err := dp.WaitStats()
+	return err
+}
+
+// WaitFunc returns a function that waits for the iteration to
+// complete, returning the errors encountered, as for
+// [Depaginator.Wait].  The function is suitable for passing to the Go
+// method of a [Group], allowing several iterations to be composed
+// under a single group.
+func (dp *Depaginator[T]) WaitFunc() func() error {
+	return dp.Wait
+}
+
+// Go waits for the iteration to complete in a goroutine of the
+// specified [Group], which collects the errors encountered.  Go does
+// not block; the group's own wait method must be called to wait for
+// the iteration.
+func (dp *Depaginator[T]) Go(g Group) {
+	g.Go(dp.WaitFunc())
+}
+
+// WaitStats waits for the iteration to complete, as for
+// [Depaginator.Wait], returning the [Stats] summarizing the iteration
+// along with the errors encountered.  This provides structured
+// results without requiring a [Doner].  As with [Depaginator.Wait],
+// it may be called more than once.
+func (dp *Depaginator[T]) WaitStats() (Stats, error) {
+	dp.waited.Do(dp.finish)
+
+	return dp.Result(), dp.result
+}
+
+// finish waits for the iteration to complete, then stops the daemon
+// and reports the results.  It is called only once, by
+// [Depaginator.WaitStats], which caches the errors it saves.
+func (dp *Depaginator[T]) finish() {
 	// Wait for the pages and items
 	dp.wg.Wait()
 
+	// Stop the item handling workers
+	if dp.workers != nil {
+		dp.workers.Close()
+	}
+
+	// Close the page stream
+	if dp.stream != nil {
+		close(dp.stream)
+	}
+
+	// Stop the concurrency sampler
+	if dp.sampler != nil {
+		close(dp.sampler)
+		<-dp.sampled
+	}
+
 	// Signal the daemon to finish up
-	close(dp.updates)
+	dp.update(stop[T]{})
 	<-dp.done
 
+	// Release the context for the page retrievals once done
+	if dp.abort != nil {
+		defer dp.abort()
+	}
+
+	// Cancel the activity timer's context once done
+	if dp.idle != nil {
+		defer dp.cancel(nil)
+	}
+
 	// Call the doner
 	if dp.doner != nil {
 		dp.doner.Done(dp.ctx, dp.totalItems, dp.totalPages, dp.perPage)
 	}
+	if dp.results != nil {
+		dp.results.Done(dp.ctx, dp.totalItems, dp.totalPages, dp.perPage)
+	}
+
+	// Commit the results if successful, otherwise roll them back
+	if dp.committer != nil {
+		if len(dp.errors) == 0 && !dp.canceled() {
+			if err := dp.committer.Commit(dp.ctx); err != nil {
+				dp.final.Lock()
+				dp.errors = append(dp.errors, err)
+				dp.final.Unlock()
+			}
+		} else {
+			dp.committer.Rollback(dp.ctx)
+		}
+	}
+
+	// Report the summary
+	dp.final.Lock()
+	dp.elapsed = time.Since(dp.start)
+	dp.final.Unlock()
+	dp.debugf("iteration complete: %d items, %d pages, %d per page, %d errors", dp.totalItems, dp.totalPages, dp.perPage, len(dp.errors))
+	if dp.metrics != nil {
+		dp.metrics.Completed(dp.totalItems, dp.totalPages)
+	}
+	if dp.summary != nil {
+		dp.summary(dp.Result())
+	}
+
+	dp.result = errors.Join(dp.errors...)
+}
+
+// settle computes the final totals and records any final error.  It
+// is called by the daemon as it exits, so that the state it owns is
+// complete before it may be read directly by [Depaginator.snapshot].
+func (dp *Depaginator[T]) settle() {
+	// Stop the activity timer and report if the iteration stalled
+	if dp.idle != nil {
+		dp.idle.Stop()
+		if errors.Is(context.Cause(dp.ctx), ErrStalled) {
+			dp.errors = append(dp.errors, ErrStalled)
+		}
+	}
+
+	// Report only the contiguous items if the iteration was canceled
+	if dp.partial && dp.canceled() {
+		dp.totalItems = dp.contiguous()
+	}
+
+	// Report no more pages than the page limit; if the last page was
+	// not reached, every page retrieved was full
+	if dp.maxPages > 0 && (dp.totalPages > dp.maxPages || (dp.totalPages == 0 && !dp.inferred)) {
+		dp.totalPages = dp.maxPages
+		if dp.perPage > 0 && (dp.totalItems == 0 || dp.totalItems > dp.maxPages*dp.perPage) {
+			dp.totalItems = dp.maxPages * dp.perPage
+		}
+	}
+
+	// Report no more items than the limit
+	if dp.limit > 0 && (dp.totalItems > dp.limit || (dp.totalItems == 0 && !dp.inferred)) {
+		dp.totalItems = dp.limit
+	}
+}
+
+// Result returns a summary of the iteration.  This method must only
+// be called after [Depaginator.Wait] has returned.
+func (dp *Depaginator[T]) Result() RunResult {
+	result := RunResult{
+		TotalItems:   dp.totalItems,
+		TotalPages:   dp.totalPages,
+		PerPage:      dp.perPage,
+		PagesFetched: dp.fetched,
+		ItemsHandled: int(dp.handled.Load()),
+		Duration:     dp.elapsed,
+	}
+	for _, err := range dp.errors {
+		if pe, ok := err.(PageError); ok {
+			result.Errors = append(result.Errors, pe)
+		}
+	}
+
+	return result
+}
+
+// debugf logs a routine event, if a [Logger] has been set by
+// [WithLifecycleLogger].
+func (dp *Depaginator[T]) debugf(format string, args ...any) {
+	if dp.log != nil {
+		dp.log.Debugf(format, args...)
+	}
+}
+
+// warnf logs a warning, if a [Logger] has been set by
+// [WithLifecycleLogger].
+func (dp *Depaginator[T]) warnf(format string, args ...any) {
+	if dp.log != nil {
+		dp.log.Warnf(format, args...)
+	}
+}
+
+// updateName returns the name of the type of an update, without the
+// package name or type parameters.
+func updateName(u any) string {
+	name := fmt.Sprintf("%T", u)
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+// timer describes a timer that can be reset or stopped.  It is
+// implemented by [time.Timer].
+type timer interface {
+	// Reset changes the timer to expire after the duration d.
+	Reset(d time.Duration) bool
+
+	// Stop prevents the timer from firing.
+	Stop() bool
+}
+
+// afterFunc starts a timer that calls f in its own goroutine after
+// the duration d.  It is a variable to allow tests to substitute a
+// fake clock.
+var afterFunc = func(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
+
+// newCancelers constructs the map of page index to cancel function.
+// If the total number of pages is known, the map is sized to hold a
+// canceler for every page, so that it need not grow as the pages are
+// requested.
+func newCancelers(totalPages int) map[int]context.CancelFunc {
+	if totalPages < 0 {
+		totalPages = 0
+	}
+
+	return make(map[int]context.CancelFunc, totalPages)
+}
+
+// spawn runs a task using the [Scheduler], or in a new goroutine if
+// no [Scheduler] has been set.
+func (dp *Depaginator[T]) spawn(task func()) {
+	if dp.scheduler != nil {
+		dp.scheduler.Go(task)
+		return
+	}
+
+	go task()
+}
+
+// dispatch runs a task handling the items of a page, using the item
+// handling workers if the [WithHandleConcurrency] option is set.
+func (dp *Depaginator[T]) dispatch(task func()) {
+	switch {
+	case dp.workers == nil:
+		dp.spawn(task)
+
+	case dp.scheduler == nil:
+		dp.workers.Put(task)
+
+	case dp.workers.Offer(task):
+		dp.scheduler.Go(dp.drain)
+	}
+}
+
+// drain is an item handling worker run as a task of the [Scheduler].
+// It runs the queued item handling tasks, exiting once the queue is
+// empty.
+func (dp *Depaginator[T]) drain() {
+	for {
+		task, ok := dp.workers.Next()
+		if !ok {
+			return
+		}
+		task()
+	}
+}
+
+// stall is called when the activity timer expires, and cancels the
+// iteration.  If a [Scheduler] is set, the iteration is canceled by
+// one of its tasks instead, unless the timer was reset, because a page
+// was retrieved, before the task ran.
+func (dp *Depaginator[T]) stall() {
+	if dp.scheduler == nil {
+		dp.cancel(ErrStalled)
+		return
+	}
+
+	resets := dp.resets.Load()
+	dp.scheduler.Go(func() {
+		if dp.resets.Load() == resets {
+			dp.cancel(ErrStalled)
+		}
+	})
+}
+
+// handleInOrder dispatches the task handling the next page, if the
+// [InOrder] option is set and that page may be released.
+func (dp *Depaginator[T]) handleInOrder() {
+	if task := dp.inOrder.Next(); task != nil {
+		dp.dispatch(task)
+	}
+}
+
+// infer determines whether a total inferred from a short page should
+// replace the current total.
+func (dp *Depaginator[T]) infer(current, inferred int) bool {
+	switch {
+	case current == 0:
+		return true
+
+	case dp.inference == inferAlways:
+		return !dp.inferred || current > inferred
+
+	case dp.inference == inferNever:
+		return false
+	}
+
+	return current > inferred
+}
+
+// exhausted determines if the byte budget has been exhausted.
+func (dp *Depaginator[T]) exhausted() bool {
+	return dp.sizeOf != nil && dp.spent >= dp.budget
+}
+
+// canceled determines if the iteration has been canceled, either
+// through the context passed to [Depaginate] or by
+// [Depaginator.Cancel].
+func (dp *Depaginator[T]) canceled() bool {
+	if dp.runCtx != nil {
+		return dp.runCtx.Err() != nil
+	}
 
-	return errors.Join(dp.errors...)
+	return dp.ctx.Err() != nil
 }
 
-// update sends an update to the daemon.
-func (dp *Depaginator[T]) update(update update[T]) {
-	dp.updates <- update
+// contiguous computes the number of items in the contiguous run of
+// pages, starting with the first page, that were received.
+func (dp *Depaginator[T]) contiguous() int {
+	total := 0
+	for i := 0; ; i++ {
+		count, ok := dp.received[i]
+		if !ok {
+			break
+		}
+		total += count
+
+		// A short page ends the run
+		if count < dp.perPage {
+			break
+		}
+	}
+
+	return total
+}
+
+// update sends an update to the daemon.  It returns false if the
+// daemon has already exited, in which case the update is discarded.
+func (dp *Depaginator[T]) update(update update[T]) bool {
+	select {
+	case dp.updates <- update:
+		return true
+	case <-dp.done:
+		return false
+	}
+}
+
+// snapshot runs a function on the daemon goroutine, allowing it to
+// safely read the state owned by the daemon.  If the daemon has
+// already exited, the function is called directly, holding the lock
+// that guards the few fields [Depaginator.Wait] still sets once the
+// daemon has exited.
+func (dp *Depaginator[T]) snapshot(fn func(depag *Depaginator[T])) {
+	reply := make(chan struct{})
+	if dp.update(snapshot[T]{
+		fn:    fn,
+		reply: reply,
+	}) {
+		select {
+		case <-reply:
+			return
+		case <-dp.done:
+		}
+
+		// The daemon may have run the function before exiting
+		select {
+		case <-reply:
+			return
+		default:
+		}
+	}
+
+	dp.final.Lock()
+	defer dp.final.Unlock()
+	fn(dp)
 }
 
 // getPage is a wrapper around [PageGetter.GetPage] that implements
-// the processing required to perform the depagination.
-func (dp *Depaginator[T]) getPage(req PageRequest) {
+// the processing required to perform the depagination.  If slot is
+// not nil, the page is retrieved using the request it holds once
+// retrieval is about to begin, which may have been replaced by the
+// overlap handler.
+func (dp *Depaginator[T]) getPage(req PageRequest, slot *overlapSlot) {
 	// Note: getPage is not complete until all its updates are
 	// complete, so we use an update object to update the wait group
 	defer dp.update(pageDone[T]{})
 
+	// Only plan the page in a dry run
+	if dp.dryRun {
+		dp.update(plannedPage[T](req.PageIndex))
+		return
+	}
+
 	// First, construct the child context
-	childCtx, cancelFn := context.WithCancel(dp.ctx)
+	childCtx, cancelFn := context.WithCancel(dp.runCtx)
 	defer cancelFn()
 
 	// Register the canceler
@@ -194,12 +977,43 @@ func (dp *Depaginator[T]) getPage(req PageRequest) {
 		cancelFn: cancelFn,
 	})
 
-	// Get the page
-	page, err := dp.pager.GetPage(childCtx, dp, req)
+	// Wait for the handler to catch up, for a slot to be available,
+	// for the downstream to be healthy, and for the rate limiter,
+	// then get the page, decorating the request if required
+	var page CompoundPage[T]
+	ticket, err := dp.pace(childCtx)
+	defer dp.withdraw(ticket)
+	if err == nil {
+		err = dp.acquire(childCtx)
+	}
+	if err == nil {
+		defer dp.release()
+		err = dp.awaitHealthy(childCtx)
+	}
+	if err == nil {
+		err = dp.throttle(childCtx)
+	}
+
+	// Take the request that won any overlapping requests
+	req = slot.Take(req)
+	if err == nil {
+		fetchReq := req
+		if dp.decorator != nil {
+			fetchReq = dp.decorator(req)
+		}
+		page, err = dp.retrieve(childCtx, fetchReq)
+	}
 
 	// Withdraw the canceler
 	dp.update(withdrawCanceler[T](req.PageIndex))
 
+	// Split the page into its logical pages
+	var handler update[T]
+	if err == nil {
+		dp.debugf("retrieved page %d: %d items", req.PageIndex, len(page.Items))
+		handler, err = page.split(req)
+	}
+
 	// If there was an error, save it
 	if err != nil {
 		dp.update(errorSaver[T]{
@@ -209,11 +1023,212 @@ func (dp *Depaginator[T]) getPage(req PageRequest) {
 		return
 	}
 
-	// Handle the items
-	dp.update(itemHandler[T]{
-		idx:  req.PageIndex,
-		page: page,
-	})
+	// Call the post-page hook
+	if dp.postPage != nil {
+		dp.postPage(dp, req, page.Items)
+	}
+
+	// Wait for the item handling workers to catch up, then handle
+	// the items
+	if dp.workers != nil {
+		dp.workers.Reserve()
+		handler = reserved[T]{handler}
+	}
+	dp.update(handler)
+}
+
+// pace waits for the retrieval of a page to be admitted, if a pacing
+// handler such as [SinkHandler] limits the number of pages retrieved
+// ahead of the handling of their items.  It returns the ticket to
+// pass to [Depaginator.withdraw], which is nil if the retrievals are
+// not paced, and an error if the context is canceled while waiting.
+func (dp *Depaginator[T]) pace(ctx context.Context) (chan struct{}, error) {
+	if dp.ahead <= 0 {
+		return nil, nil
+	}
+
+	ticket := make(chan struct{})
+	dp.update(admission[T](ticket))
+
+	select {
+	case <-ticket:
+		return ticket, nil
+
+	case <-ctx.Done():
+		return ticket, ctx.Err()
+	}
+}
+
+// withdraw withdraws the admission, or the request for admission,
+// identified by a ticket returned by [Depaginator.pace].  It must be
+// called once the items of the page have been submitted for handling,
+// or once the retrieval has failed.
+func (dp *Depaginator[T]) withdraw(ticket chan struct{}) {
+	if ticket != nil {
+		dp.update(withdrawal[T](ticket))
+	}
+}
+
+// admit admits waiting page retrievals while the number of pages
+// being retrieved or waiting to be handled is below the limit set by
+// a pacing handler.  Pages held back to be handled in order are not
+// counted, as they may be waiting for a page yet to be admitted.
+func (dp *Depaginator[T]) admit() {
+	for len(dp.waiting) > 0 {
+		pending := dp.admitted + dp.buffered
+		if dp.inOrder != nil {
+			pending -= dp.inOrder.Queued()
+		}
+		if pending >= dp.ahead {
+			return
+		}
+
+		close(dp.waiting[0])
+		dp.waiting = dp.waiting[1:]
+		dp.admitted++
+	}
+}
+
+// acquire waits for a page retrieval slot to become available, if
+// the number of concurrent page retrievals is limited by the
+// [MaxConcurrency] option.  It returns an error if the context is
+// canceled while waiting.
+func (dp *Depaginator[T]) acquire(ctx context.Context) error {
+	if dp.slots == nil {
+		return nil
+	}
+
+	select {
+	case dp.slots <- struct{}{}:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release releases a page retrieval slot acquired by
+// [Depaginator.acquire].
+func (dp *Depaginator[T]) release() {
+	if dp.slots != nil {
+		<-dp.slots
+	}
+}
+
+// awaitHealthy waits until the health gate set by [WithHealthGate],
+// if any, reports that the downstream is healthy, polling it with
+// exponential backoff.  It returns an error if the context is canceled
+// while waiting.
+func (dp *Depaginator[T]) awaitHealthy(ctx context.Context) error {
+	delay := HealthPollMin
+	for dp.healthy != nil && !dp.healthy() {
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+
+		case <-t.C:
+		}
+
+		if delay *= 2; delay > HealthPollMax {
+			delay = HealthPollMax
+		}
+	}
+
+	return nil
+}
+
+// throttle waits on the rate limiter set by [WithRateLimiter], if
+// any.  If the context is canceled while waiting, the context error
+// is returned, so that the page is treated as canceled.
+func (dp *Depaginator[T]) throttle(ctx context.Context) error {
+	if dp.limiter == nil {
+		return nil
+	}
+
+	if err := dp.limiter.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// retrieve retrieves a page, retrying failed retrievals as permitted
+// by the [WithRetry] and [WithRetryBudget] options.  Context errors,
+// and errors reporting themselves as permanent through
+// [RetryableError], are never retried, and if the context is canceled
+// while waiting to retry, the context error is returned, so that the
+// page is treated as canceled.  Only the error from the final attempt
+// is returned.
+func (dp *Depaginator[T]) retrieve(ctx context.Context, req PageRequest) (CompoundPage[T], error) {
+	for attempt := 1; ; attempt++ {
+		page, err := dp.fetch(ctx, req)
+		if err == nil || attempt >= dp.attempts || ctx.Err() != nil ||
+			errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || !retryable(err) {
+			return page, err
+		}
+
+		// Claim a retry from the budget
+		if dp.retries > 0 && dp.retried.Add(1) > int64(dp.retries) {
+			return page, err
+		}
+
+		// Wait before the next attempt
+		if dp.backoff != nil {
+			t := time.NewTimer(dp.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return CompoundPage[T]{}, ctx.Err()
+
+			case <-t.C:
+			}
+		}
+	}
+}
+
+// fetch retrieves a page, using the [CompoundPageGetter] if one is
+// available.  With [WithBatchedRequests], the updates and page
+// requests submitted during the call are coalesced and submitted
+// together once it returns.  If a [Tracer] is set, the call is traced
+// by a span, and if [Metrics] are set, the call is timed.
+func (dp *Depaginator[T]) fetch(ctx context.Context, req PageRequest) (page CompoundPage[T], err error) {
+	dp.active.Add(1)
+	defer dp.active.Add(-1)
+
+	if dp.tracer != nil {
+		var span Span
+		ctx, span = dp.tracer.Start(ctx, SpanName, req.PageIndex)
+		defer func() {
+			span.End(err)
+		}()
+	}
+	if dp.metrics != nil {
+		start := time.Now()
+		defer func() {
+			dp.metrics.PageFetched(time.Since(start), err)
+		}()
+	}
+
+	var state State = dp
+	if dp.batch {
+		bs := &batchState[T]{
+			dp: dp,
+		}
+		defer bs.flush()
+		state = bs
+	}
+
+	if dp.compound != nil {
+		return dp.compound.GetCompoundPage(ctx, state, req)
+	}
+
+	items, err := dp.pager.GetPage(ctx, state, req)
+	return CompoundPage[T]{Items: items}, err
 }
 
 // Update allows updating the total number of items, total number of
@@ -243,7 +1258,9 @@ func (dp *Depaginator[T]) Update(updates ...any) {
 // request is optional, and can contain any page-specific data, such
 // as a page link.  Duplicate page requests are ignored, as is any
 // request with an index greater than the total number of pages (if
-// known).
+// known).  Requests are processed one at a time by the daemon, so
+// concurrent requests for the same page result in exactly one call to
+// [PageGetter.GetPage]; the first request processed wins.
 func (dp *Depaginator[T]) Request(idx int, req any) {
 	dp.update(pageRequest[T]{
 		idx: idx,
@@ -251,15 +1268,305 @@ func (dp *Depaginator[T]) Request(idx int, req any) {
 	})
 }
 
-// PerPage retrieves the configured "per page" value for
-// [Depaginator].  This allows a consumer to set the number of items
-// per page when calling [Depaginate] (using the [PerPage] option).
-// Applications should be careful to not mix this functionality with
-// dynamic collection of the "per page" value, as the value is not
-// protected by any mutex; if using this method, avoid passing
-// [PerPage] to [Depaginator.Update] and arrange for a reasonable
-// default if [PerPage] is not passed to [Depaginate] (in which case,
-// this method will return 0).
+// RequestRange requests the [Depaginator] retrieve the pages with
+// indexes from start up to, but not including, end.  If reqFn is not
+// nil, it is called with each page index to obtain the request for
+// that page.  This is equivalent to calling [Depaginator.Request] for
+// each page, but submits all the requests as a single update, so a
+// large range does not fill the update queue; as with
+// [Depaginator.Request], duplicate requests and requests for pages
+// beyond the total number of pages are ignored.
+func (dp *Depaginator[T]) RequestRange(start, end int, reqFn func(idx int) any) {
+	ups := bundle[T]{}
+	for idx := start; idx < end; idx++ {
+		req := pageRequest[T]{
+			idx: idx,
+		}
+		if reqFn != nil {
+			req.req = reqFn(idx)
+		}
+		ups = append(ups, req)
+	}
+
+	if len(ups) > 0 {
+		dp.update(ups)
+	}
+}
+
+// RequestNext requests the [Depaginator] retrieve the page following
+// the highest page requested so far.  The index is assigned when the
+// request is processed, so concurrent calls each receive a distinct
+// index, in the order in which they are processed; the request is
+// ignored if the assigned index is beyond the total number of pages.
+func (dp *Depaginator[T]) RequestNext(req any) {
+	dp.update(nextRequest[T]{
+		req: req,
+	})
+}
+
+// PageHistory returns the metadata observed for each page, in the
+// order in which the pages completed.  This includes pages that
+// failed to be retrieved, for which the Err field of [PageMeta] will
+// be set, but excludes pages that were canceled.  This method must
+// only be called after [Depaginator.Wait] has returned.
+func (dp *Depaginator[T]) PageHistory() []PageMeta {
+	return dp.history
+}
+
+// PlannedPages returns the indexes of the pages that would have been
+// retrieved, in ascending order, if the iteration was a dry run
+// requested with the [WithDryRun] option.  This method must only be
+// called after [Depaginator.Wait] has returned.
+func (dp *Depaginator[T]) PlannedPages() []int {
+	planned := append([]int(nil), dp.planned...)
+	sort.Ints(planned)
+	return planned
+}
+
+// Context returns the context used by the iteration.  This is the
+// context passed to [Depaginate], unless an option such as
+// [WithActivityTimeout] required it to be wrapped, in which case the
+// wrapping context is returned.  This allows callers to derive their
+// own contexts from that of the iteration, or to determine why the
+// iteration was canceled using [context.Cause].
+func (dp *Depaginator[T]) Context() context.Context {
+	return dp.ctx
+}
+
+// Pages returns the channel on which retrieved pages are delivered
+// when the [WithPageStream] option is used; otherwise, it returns nil.
+// Each page is delivered once its items have been handled, and the
+// channel is closed once all pages have been retrieved.  Note that
+// [Depaginator.Wait] does not return until all pages have been
+// received from the channel, so the channel must be consumed in a
+// different goroutine from the one calling [Depaginator.Wait].
+func (dp *Depaginator[T]) Pages() <-chan PageResult[T] {
+	return dp.stream
+}
+
+// PageStatus reports the outcome of the page with the specified
+// index.  If the page was successfully retrieved, fetched will be
+// true; if the retrieval failed, err will contain the error returned
+// by the [PageGetter].  Pages that were never requested, or that were
+// canceled, report neither.  This method must only be called after
+// [Depaginator.Wait] has returned.
+func (dp *Depaginator[T]) PageStatus(idx int) (fetched bool, err error) {
+	if dp.retrieved.IsSet(idx) {
+		return true, nil
+	}
+
+	return false, dp.pageErrs[idx]
+}
+
+// Progress returns the progress of the iteration, computed from the
+// number of items handled so far and the total number of items, if
+// known.  It may be called at any time from any goroutine.
+func (dp *Depaginator[T]) Progress() Progress {
+	var total int
+	dp.snapshot(func(depag *Depaginator[T]) {
+		total = depag.totalItems
+	})
+
+	return Progress{
+		Handled: int(dp.handled.Load()),
+		Total:   total,
+	}
+}
+
+// ErrorsSoFar returns a copy of the errors encountered so far.  It
+// may be called at any time from any goroutine, allowing the errors
+// to be monitored while the iteration is in progress.
+func (dp *Depaginator[T]) ErrorsSoFar() []PageError {
+	var errs []PageError
+	dp.snapshot(func(depag *Depaginator[T]) {
+		for _, err := range depag.errors {
+			if pe, ok := err.(PageError); ok {
+				errs = append(errs, pe)
+			}
+		}
+	})
+
+	return errs
+}
+
+// Cancel stops the iteration.  All in-flight page retrievals are
+// canceled, so the contexts passed to [PageGetter.GetPage] report
+// cancellation, and no further pages are requested; the items of
+// pages already retrieved are still handled.  [Depaginator.Wait]
+// then returns the errors encountered so far, excluding the context
+// errors caused by the cancellation, and the iteration is treated as
+// canceled by options such as [WithPartialResults].  Note that the
+// context passed to the [Handler] is not canceled.  Cancel may be
+// called at any time from any goroutine, and calling it more than
+// once has no further effect.
+func (dp *Depaginator[T]) Cancel() {
+	dp.abort()
+}
+
+// Err returns the errors encountered so far, wrapped by
+// [errors.Join], or nil if there have been none.  Unlike
+// [Depaginator.Wait], it does not block until the iteration is
+// complete, so it may be used to poll for errors while the iteration
+// is in progress; it may be called at any time from any goroutine.
+func (dp *Depaginator[T]) Err() error {
+	var errs []error
+	dp.snapshot(func(depag *Depaginator[T]) {
+		errs = append(errs, depag.errors...)
+	})
+
+	return errors.Join(errs...)
+}
+
+// MarkLast declares that the page with the specified index is the
+// final page.  This sets the total number of pages authoritatively;
+// subsequent attempts to update the total number of pages are
+// ignored, and any in-flight requests for pages beyond the final page
+// are canceled.  This is intended for APIs that signal the last page
+// explicitly, rather than through item counts.
+func (dp *Depaginator[T]) MarkLast(idx int) {
+	dp.update(lastPage[T](idx))
+}
+
+// Config returns the effective configuration of the iteration, after
+// the options passed to [Depaginate] have been applied.  It may be
+// called at any time from any goroutine.
+func (dp *Depaginator[T]) Config() Config {
+	return dp.config
+}
+
+// TotalItems returns the total number of items.  This is only
+// known once reported by the [PageGetter] or inferred from a short
+// page; until then, it is 0.  It may be called at any time from any
+// goroutine; once [Depaginator.Wait] has returned, it reports the
+// final total, as passed to the [Doner].
+func (dp *Depaginator[T]) TotalItems() int {
+	var total int
+	dp.snapshot(func(depag *Depaginator[T]) {
+		total = depag.totalItems
+	})
+
+	return total
+}
+
+// TotalPages returns the total number of pages.  This is only known
+// once reported by the [PageGetter], inferred, or set by
+// [Depaginator.MarkLast]; until then, it is 0.  It may be called at
+// any time from any goroutine; once [Depaginator.Wait] has returned,
+// it reports the final total, as passed to the [Doner].
+func (dp *Depaginator[T]) TotalPages() int {
+	var total int
+	dp.snapshot(func(depag *Depaginator[T]) {
+		total = depag.totalPages
+	})
+
+	return total
+}
+
+// Checkpoint returns a checkpoint recording the pages whose items
+// have been handled, including any handled by the iteration resumed
+// with the [WithCheckpoint] option.  Passing the checkpoint to
+// [WithCheckpoint] allows an interrupted iteration to be resumed
+// without handling those pages again.  It may be called at any time
+// from any goroutine, such as periodically while the iteration is in
+// progress, or after [Depaginator.Wait] has returned.  The pages
+// handled are only recorded if the [WithCheckpoint] or
+// [WithRequestCodec] option was passed to [Depaginate]; otherwise, it
+// returns nil.
+func (dp *Depaginator[T]) Checkpoint() []byte {
+	var checkpoint []byte
+	dp.snapshot(func(depag *Depaginator[T]) {
+		if depag.completed == nil {
+			return
+		}
+		checkpoint = depag.completed.Checkpoint()
+		if depag.encodeReq == nil {
+			return
+		}
+
+		// Record the requests of the pages not yet handled
+		requests := map[int][]byte{}
+		for idx, req := range depag.requests {
+			if data, err := depag.encodeReq(req); err == nil {
+				requests[idx] = data
+			}
+		}
+		checkpoint = encodeCheckpoint(checkpoint, requests)
+	})
+
+	return checkpoint
+}
+
+// handledBefore determines whether the items of the page have already
+// been handled, either by this iteration or by the iteration resumed
+// from a checkpoint.  Pages dropped from the map of pages handled by
+// compaction were all handled.
+func (dp *Depaginator[T]) handledBefore(idx int) bool {
+	return dp.completed != nil && (dp.completed.IsSet(idx) || dp.completed.Compacted(idx))
+}
+
+// restore restores the state recorded in the checkpoint of a previous
+// iteration, returning the requests of the pages that were not
+// handled, other than the first page, which is always requested.
+func (dp *Depaginator[T]) restore(checkpoint []byte, decode func(data []byte) (any, error)) (resume[T], error) {
+	completed, requests, err := decodeCheckpoint(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := dp.completed.Restore(completed); err != nil {
+		return nil, err
+	}
+	if len(requests) > 0 && decode == nil {
+		return nil, fmt.Errorf("%w: requests recorded but no request codec set", ErrInvalidCheckpoint)
+	}
+
+	var resumed resume[T]
+	for idx, data := range requests {
+		if idx == 0 {
+			continue
+		}
+		req, err := decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: page %d: %s", ErrInvalidCheckpoint, idx, err)
+		}
+		resumed = append(resumed, PageRequest{
+			PageIndex: idx,
+			Request:   req,
+		})
+	}
+	sort.Slice(resumed, func(i, j int) bool {
+		return resumed[i].PageIndex < resumed[j].PageIndex
+	})
+
+	return resumed, nil
+}
+
+// BufferedPages returns the number of pages that have been fetched
+// but whose items have not yet finished being handled.  A count that
+// keeps growing indicates that handling, rather than fetching, is the
+// bottleneck.  It may be called at any time from any goroutine; once
+// [Depaginator.Wait] has returned, it reports 0.
+func (dp *Depaginator[T]) BufferedPages() int {
+	var buffered int
+	dp.snapshot(func(depag *Depaginator[T]) {
+		buffered = depag.buffered
+	})
+
+	return buffered
+}
+
+// PerPage retrieves the current "per page" value for [Depaginator].
+// This allows a consumer to set the number of items per page when
+// calling [Depaginate] (using the [PerPage] option) and read it back
+// when retrieving pages.  The value is read on the daemon goroutine,
+// so it is consistent with updates made through
+// [Depaginator.Update], and it may be called at any time from any
+// goroutine; if the number of items per page was neither passed to
+// [Depaginate] nor reported, this method will return 0.
 func (dp *Depaginator[T]) PerPage() int {
-	return dp.perPage
+	var perPage int
+	dp.snapshot(func(depag *Depaginator[T]) {
+		perPage = depag.perPage
+	})
+
+	return perPage
 }
@@ -20,10 +20,12 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 type mockCancelFn struct {
@@ -181,6 +183,29 @@ func TestDepaginatorDaemonBase(t *testing.T) {
 	u5.AssertExpectations(t)
 }
 
+func TestDepaginatorDaemonPerPageOnly(t *testing.T) {
+	ctx := context.Background()
+	updater := &mockUpdater{}
+	updater.On("Update", ctx, 20, 4, 5).Once()
+	obj := &Depaginator[string]{
+		ctx:        ctx,
+		totalItems: 20,
+		totalPages: 4,
+		updater:    updater,
+		updates:    make(chan update[string], DefaultCapacity),
+		done:       make(chan struct{}),
+	}
+	obj.updates <- bundle[string]{perPage[string](5)}
+	obj.updates <- bundle[string]{perPage[string](5)}
+	close(obj.updates)
+
+	obj.daemon()
+
+	assert.Equal(t, 5, obj.perPage)
+	updater.AssertExpectations(t)
+	updater.AssertNumberOfCalls(t, "Update", 1)
+}
+
 func TestDepaginatorDaemonWithUpdater(t *testing.T) {
 	ctx := context.Background()
 	updater := &mockUpdater{}
@@ -233,6 +258,62 @@ func TestDepaginatorDaemonWithUpdater(t *testing.T) {
 	u5.AssertExpectations(t)
 }
 
+func TestDepaginatorDaemonStop(t *testing.T) {
+	ctx := context.Background()
+	obj := &Depaginator[string]{
+		ctx:     ctx,
+		updates: make(chan update[string], DefaultCapacity),
+		done:    make(chan struct{}),
+	}
+	u1 := &mockUpdate{}
+	u1.On("applyUpdate", obj)
+	obj.updates <- u1
+	obj.updates <- stop[string]{}
+	u2 := &mockUpdate{}
+	obj.updates <- u2
+
+	obj.daemon()
+
+	select {
+	case <-obj.done:
+	default:
+		assert.Fail(t, "daemon failed to close channel")
+	}
+	u1.AssertExpectations(t)
+	u2.AssertExpectations(t)
+	assert.Len(t, obj.updates, 1)
+}
+
+func TestDepaginatorDaemonPanic(t *testing.T) {
+	ctx := context.Background()
+	aborter := &mockCancelFn{}
+	aborter.On("Cancel")
+	obj := &Depaginator[string]{
+		ctx:     ctx,
+		abort:   aborter.Cancel,
+		updates: make(chan update[string], DefaultCapacity),
+		done:    make(chan struct{}),
+	}
+	u1 := &mockUpdate{}
+	u1.On("applyUpdate", obj).Run(func(args mock.Arguments) {
+		panic("oops")
+	})
+	obj.updates <- u1
+	u2 := &mockUpdate{}
+	u2.On("applyUpdate", obj)
+	obj.updates <- u2
+	close(obj.updates)
+
+	obj.daemon()
+
+	require.Len(t, obj.errors, 1)
+	assert.ErrorIs(t, obj.errors[0], ErrPanic)
+	assert.ErrorContains(t, obj.errors[0], "mockUpdate: oops")
+	aborter.AssertExpectations(t)
+	u1.AssertExpectations(t)
+	u2.AssertExpectations(t)
+}
+
 func TestDepaginatorWaitBase(t *testing.T) {
 	obj := &Depaginator[string]{
 		totalItems: 20,
@@ -242,16 +323,140 @@ func TestDepaginatorWaitBase(t *testing.T) {
 		updates:    make(chan update[string]),
 		done:       make(chan struct{}),
 	}
-	close(obj.done)
+	var u update[string]
+	go func() {
+		defer close(obj.done)
+		u = <-obj.updates
+	}()
 
 	err := obj.Wait()
 
 	assert.NoError(t, err)
-	select {
-	case <-obj.updates:
-	default:
-		assert.Fail(t, "Wait failed to close updates channel")
+	assert.Equal(t, stop[string]{}, u)
+}
+
+func TestDepaginatorWaitTwice(t *testing.T) {
+	doner := &mockDoner{}
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		totalPages: 4,
+		perPage:    5,
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		doner:   doner,
+		wg:      &sync.WaitGroup{},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	doner.On("Done", mock.Anything, 20, 4, 5).Once()
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
+
+	err1 := obj.Wait()
+	stats, err2 := obj.WaitStats()
+
+	assert.ErrorIs(t, err1, assert.AnError)
+	assert.Same(t, err1, err2)
+	assert.Equal(t, 20, stats.TotalItems)
+	doner.AssertExpectations(t)
+}
+
+func TestDepaginatorWaitStats(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		totalPages: 4,
+		perPage:    5,
+		fetched:    4,
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		wg:      &sync.WaitGroup{},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	obj.handled.Store(15)
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
+
+	stats, err := obj.WaitStats()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 20, stats.TotalItems)
+	assert.Equal(t, 4, stats.TotalPages)
+	assert.Equal(t, 5, stats.PerPage)
+	assert.Equal(t, 4, stats.PagesFetched)
+	assert.Equal(t, 15, stats.ItemsHandled)
+	assert.Equal(t, []PageError{
+		{
+			PageRequest: PageRequest{PageIndex: 2},
+			Err:         assert.AnError,
+		},
+	}, stats.Errors)
+}
+
+type fakeGroup struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+func (g *fakeGroup) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		err := f()
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.errs = append(g.errs, err)
+	}()
+}
+
+func TestDepaginatorWaitFunc(t *testing.T) {
+	obj := &Depaginator[string]{
+		errors:  []error{assert.AnError},
+		wg:      &sync.WaitGroup{},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
+
+	result := obj.WaitFunc()
+
+	assert.ErrorIs(t, result(), assert.AnError)
+}
+
+func TestDepaginatorGo(t *testing.T) {
+	obj := &Depaginator[string]{
+		errors:  []error{assert.AnError},
+		wg:      &sync.WaitGroup{},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
 	}
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
+	g := &fakeGroup{}
+
+	obj.Go(g)
+
+	g.wg.Wait()
+	require.Len(t, g.errs, 1)
+	assert.ErrorIs(t, g.errs[0], assert.AnError)
 }
 
 func TestDepaginatorWaitWithDoner(t *testing.T) {
@@ -268,156 +473,1791 @@ func TestDepaginatorWaitWithDoner(t *testing.T) {
 		updates:    make(chan update[string]),
 		done:       make(chan struct{}),
 	}
-	close(obj.done)
+	var u update[string]
+	go func() {
+		defer close(obj.done)
+		u = <-obj.updates
+	}()
 
 	err := obj.Wait()
 
 	assert.NoError(t, err)
-	select {
-	case <-obj.updates:
-	default:
-		assert.Fail(t, "Wait failed to close updates channel")
-	}
+	assert.Equal(t, stop[string]{}, u)
 	doner.AssertExpectations(t)
 }
 
-func TestDepaginatorUpdateInternal(t *testing.T) {
+func TestDepaginatorWaitCommit(t *testing.T) {
+	ctx := context.Background()
+	committer := &mockCommitter{}
+	committer.On("Commit", ctx).Return(nil)
 	obj := &Depaginator[string]{
-		updates: make(chan update[string], DefaultCapacity),
+		ctx:       ctx,
+		committer: committer,
+		wg:        &sync.WaitGroup{},
+		updates:   make(chan update[string]),
+		done:      make(chan struct{}),
 	}
-	u := &mockUpdate{}
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
 
-	obj.update(u)
+	err := obj.Wait()
 
-	close(obj.updates)
-	assert.Len(t, obj.updates, 1)
-	assert.Same(t, u, <-obj.updates)
+	assert.NoError(t, err)
+	committer.AssertExpectations(t)
 }
 
-func TestDepaginatorGetPageBase(t *testing.T) {
+func TestDepaginatorWaitCommitError(t *testing.T) {
 	ctx := context.Background()
-	pager := &mockPageGetter{}
+	committer := &mockCommitter{}
+	committer.On("Commit", ctx).Return(assert.AnError)
 	obj := &Depaginator[string]{
-		ctx:     ctx,
-		pager:   pager,
-		updates: make(chan update[string], DefaultCapacity),
+		ctx:       ctx,
+		committer: committer,
+		wg:        &sync.WaitGroup{},
+		updates:   make(chan update[string]),
+		done:      make(chan struct{}),
 	}
-	req := PageRequest{
-		PageIndex: 5,
-		Request:   "five",
-	}
-	pager.On("GetPage", mock.Anything, obj, req).Return([]string{"one", "two", "three"}, nil)
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
 
-	obj.getPage(req)
+	err := obj.Wait()
 
-	close(obj.updates)
-	updates := []update[string]{}
-	for u := range obj.updates {
-		updates = append(updates, u)
-	}
-	assert.Len(t, updates, 4)
-	require.IsType(t, cancelerFor[string]{}, updates[0])
-	assert.Equal(t, 5, updates[0].(cancelerFor[string]).page)
-	assert.Equal(t, withdrawCanceler[string](5), updates[1])
-	assert.Equal(t, itemHandler[string]{
-		idx:  5,
-		page: []string{"one", "two", "three"},
-	}, updates[2])
-	assert.Equal(t, pageDone[string]{}, updates[3])
-	pager.AssertExpectations(t)
+	assert.ErrorIs(t, err, assert.AnError)
+	committer.AssertExpectations(t)
 }
 
-func TestDepaginatorGetPageError(t *testing.T) {
+func TestDepaginatorWaitRollback(t *testing.T) {
 	ctx := context.Background()
-	pager := &mockPageGetter{}
+	committer := &mockCommitter{}
+	committer.On("Rollback", ctx)
 	obj := &Depaginator[string]{
-		ctx:     ctx,
-		pager:   pager,
-		updates: make(chan update[string], DefaultCapacity),
-	}
-	req := PageRequest{
-		PageIndex: 5,
-		Request:   "five",
+		ctx: ctx,
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		committer: committer,
+		wg:        &sync.WaitGroup{},
+		updates:   make(chan update[string]),
+		done:      make(chan struct{}),
 	}
-	pager.On("GetPage", mock.Anything, obj, req).Return(nil, assert.AnError)
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
 
-	obj.getPage(req)
+	err := obj.Wait()
 
-	close(obj.updates)
-	updates := []update[string]{}
-	for u := range obj.updates {
-		updates = append(updates, u)
-	}
-	assert.Len(t, updates, 4)
-	require.IsType(t, cancelerFor[string]{}, updates[0])
-	assert.Equal(t, 5, updates[0].(cancelerFor[string]).page)
-	assert.Equal(t, withdrawCanceler[string](5), updates[1])
-	assert.Equal(t, errorSaver[string]{
-		req: req,
-		err: assert.AnError,
-	}, updates[2])
-	assert.Equal(t, pageDone[string]{}, updates[3])
-	pager.AssertExpectations(t)
+	assert.ErrorIs(t, err, assert.AnError)
+	committer.AssertExpectations(t)
 }
 
-func TestDepaginatorUpdateBase(t *testing.T) {
+func TestDepaginatorWaitRollbackCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	committer := &mockCommitter{}
+	committer.On("Rollback", ctx)
 	obj := &Depaginator[string]{
-		updates: make(chan update[string], DefaultCapacity),
+		ctx:       ctx,
+		committer: committer,
+		wg:        &sync.WaitGroup{},
+		updates:   make(chan update[string]),
+		done:      make(chan struct{}),
 	}
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
 
-	obj.Update(TotalItems(20), TotalPages(4), PerPage(5))
+	err := obj.Wait()
 
-	select {
-	case update := <-obj.updates:
-		assert.Equal(t, bundle[string]{
-			totalItems[string](20),
-			totalPages[string](4),
-			perPage[string](5),
-		}, update)
-	default:
-		assert.Fail(t, "Update failed to send update on channel")
+	assert.NoError(t, err)
+	committer.AssertExpectations(t)
+}
+
+func TestDepaginatorWaitPartial(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	doner := &mockDoner{}
+	doner.On("Done", ctx, 10, 4, 5)
+	obj := &Depaginator[string]{
+		ctx:        ctx,
+		totalItems: 20,
+		totalPages: 4,
+		perPage:    5,
+		partial:    true,
+		received:   map[int]int{0: 5, 1: 5, 3: 5},
+		doner:      doner,
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string], 1),
+		done:       make(chan struct{}),
 	}
-	close(obj.updates)
+	go obj.daemon()
+
+	err := obj.Wait()
+
+	assert.NoError(t, err)
+	doner.AssertExpectations(t)
 }
 
-func TestDepaginatorUpdateNoUpdates(t *testing.T) {
+func TestDepaginatorWaitMetrics(t *testing.T) {
+	metrics := &fakeMetrics{}
 	obj := &Depaginator[string]{
-		updates: make(chan update[string], DefaultCapacity),
+		totalItems: 20,
+		totalPages: 4,
+		perPage:    5,
+		metrics:    metrics,
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
 	}
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
 
-	obj.Update(20, 4, 5)
+	err := obj.Wait()
 
-	select {
-	case <-obj.updates:
-		assert.Fail(t, "Update sent unexpected update on channel")
-	default:
+	assert.NoError(t, err)
+	assert.Equal(t, 1, metrics.completed)
+	assert.Equal(t, 20, metrics.totalItems)
+	assert.Equal(t, 4, metrics.totalPages)
+}
+
+func TestDepaginatorWaitSummary(t *testing.T) {
+	var summary []RunResult
+	obj := &Depaginator[string]{
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		totalItems: 20,
+		totalPages: 4,
+		perPage:    5,
+		fetched:    4,
+		summary: func(result RunResult) {
+			summary = append(summary, result)
+		},
+		start:   time.Now(),
+		wg:      &sync.WaitGroup{},
+		updates: make(chan update[string], 1),
+		done:    make(chan struct{}),
 	}
-	close(obj.updates)
+	obj.handled.Store(15)
+	go func() {
+		defer close(obj.done)
+		<-obj.updates
+	}()
+
+	err := obj.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	require.Len(t, summary, 1)
+	assert.Equal(t, 20, summary[0].TotalItems)
+	assert.Equal(t, 4, summary[0].TotalPages)
+	assert.Equal(t, 5, summary[0].PerPage)
+	assert.Equal(t, 4, summary[0].PagesFetched)
+	assert.Equal(t, 15, summary[0].ItemsHandled)
+	assert.Equal(t, []PageError{
+		{
+			PageRequest: PageRequest{PageIndex: 2},
+			Err:         assert.AnError,
+		},
+	}, summary[0].Errors)
+	assert.Equal(t, obj.elapsed, summary[0].Duration)
 }
 
-func TestDepaginatorRequest(t *testing.T) {
+func TestDepaginatorResult(t *testing.T) {
 	obj := &Depaginator[string]{
-		updates: make(chan update[string], DefaultCapacity),
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		totalItems: 20,
+		totalPages: 4,
+		perPage:    5,
+		fetched:    4,
+		elapsed:    time.Second,
 	}
+	obj.handled.Store(15)
 
-	obj.Request(3, "three")
+	result := obj.Result()
 
-	select {
-	case update := <-obj.updates:
-		assert.Equal(t, pageRequest[string]{
-			idx: 3,
-			req: "three",
-		}, update)
-	default:
-		assert.Fail(t, "Request failed to send update on channel")
+	assert.Equal(t, RunResult{
+		TotalItems:   20,
+		TotalPages:   4,
+		PerPage:      5,
+		PagesFetched: 4,
+		ItemsHandled: 15,
+		Errors: []PageError{
+			{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		Duration: time.Second,
+	}, result)
+}
+
+func TestDepaginatorSpawnBase(t *testing.T) {
+	obj := &Depaginator[string]{}
+	called := make(chan struct{})
+
+	obj.spawn(func() {
+		close(called)
+	})
+
+	<-called
+}
+
+func TestDepaginatorSpawnScheduler(t *testing.T) {
+	scheduler := &stepScheduler{
+		tasks: make(chan func(), 1),
 	}
-	close(obj.updates)
+	obj := &Depaginator[string]{
+		scheduler: scheduler,
+	}
+	called := false
+
+	obj.spawn(func() {
+		called = true
+	})
+
+	assert.False(t, called)
+	(<-scheduler.tasks)()
+	assert.True(t, called)
 }
 
-func TestDepaginatorPerPage(t *testing.T) {
+func TestDepaginatorDispatchSchedulerWorkers(t *testing.T) {
+	scheduler := &stepScheduler{
+		tasks: make(chan func(), 2),
+	}
 	obj := &Depaginator[string]{
-		perPage: 50,
+		scheduler: scheduler,
+		workers:   newTaskQueue(1),
 	}
+	var order []int
+
+	obj.dispatch(func() { order = append(order, 1) })
+	obj.dispatch(func() { order = append(order, 2) })
+
+	assert.Len(t, scheduler.tasks, 1)
+	(<-scheduler.tasks)()
+	assert.Equal(t, []int{1, 2}, order)
+	assert.Equal(t, 0, obj.workers.running)
+}
+
+func TestDepaginatorStallBase(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	obj := &Depaginator[string]{
+		cancel: cancel,
+	}
+
+	obj.stall()
+
+	assert.ErrorIs(t, context.Cause(ctx), ErrStalled)
+}
+
+func TestDepaginatorStallScheduler(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	scheduler := &stepScheduler{
+		tasks: make(chan func(), 1),
+	}
+	obj := &Depaginator[string]{
+		scheduler: scheduler,
+		cancel:    cancel,
+	}
+
+	obj.stall()
+
+	assert.NoError(t, ctx.Err())
+	(<-scheduler.tasks)()
+	assert.ErrorIs(t, context.Cause(ctx), ErrStalled)
+}
+
+func TestDepaginatorStallSchedulerReset(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	scheduler := &stepScheduler{
+		tasks: make(chan func(), 1),
+	}
+	obj := &Depaginator[string]{
+		scheduler: scheduler,
+		cancel:    cancel,
+	}
+
+	obj.stall()
+	obj.resets.Add(1)
+	(<-scheduler.tasks)()
+
+	assert.NoError(t, ctx.Err())
+}
+
+func TestDepaginatorInferDefaultUnknown(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferLower,
+	}
+
+	result := obj.infer(0, 5)
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorInferDefaultSmaller(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferLower,
+	}
+
+	result := obj.infer(3, 5)
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorInferDefaultLarger(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferLower,
+	}
+
+	result := obj.infer(8, 5)
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorInferTrustUnknown(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferAlways,
+	}
+
+	result := obj.infer(0, 5)
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorInferTrustSmaller(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferAlways,
+	}
+
+	result := obj.infer(3, 5)
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorInferTrustLarger(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferAlways,
+	}
+
+	result := obj.infer(8, 5)
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorInferDistrustUnknown(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferNever,
+	}
+
+	result := obj.infer(0, 5)
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorInferDistrustSmaller(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferNever,
+	}
+
+	result := obj.infer(3, 5)
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorInferDistrustLarger(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferNever,
+	}
+
+	result := obj.infer(8, 5)
+
+	assert.False(t, result)
+}
+
+func TestNewCancelersBase(t *testing.T) {
+	result := newCancelers(5)
+
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+}
+
+func TestNewCancelersNegative(t *testing.T) {
+	result := newCancelers(-1)
+
+	assert.NotNil(t, result)
+	assert.Empty(t, result)
+}
+
+func benchmarkCancelers(b *testing.B, hint int) {
+	cancelFn := func() {}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		depag := &Depaginator[int]{
+			cancelers: newCancelers(hint),
+		}
+		for page := 0; page < 10000; page++ {
+			cancelerFor[int]{
+				page:     page,
+				cancelFn: cancelFn,
+			}.applyUpdate(depag)
+		}
+	}
+}
+
+func BenchmarkCancelersUnknownPages(b *testing.B) {
+	benchmarkCancelers(b, 0)
+}
+
+func BenchmarkCancelersKnownPages(b *testing.B) {
+	benchmarkCancelers(b, 10000)
+}
+
+func TestDepaginatorInferTrustPreviouslyInferred(t *testing.T) {
+	obj := &Depaginator[string]{
+		inference: inferAlways,
+		inferred:  true,
+	}
+
+	result := obj.infer(3, 5)
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorExhaustedNoBudget(t *testing.T) {
+	obj := &Depaginator[string]{}
+
+	result := obj.exhausted()
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorExhaustedUnder(t *testing.T) {
+	obj := &Depaginator[string]{
+		budget: 20,
+		spent:  19,
+		sizeOf: func([]string) int64 { return 0 },
+	}
+
+	result := obj.exhausted()
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorExhaustedOver(t *testing.T) {
+	obj := &Depaginator[string]{
+		budget: 20,
+		spent:  20,
+		sizeOf: func([]string) int64 { return 0 },
+	}
+
+	result := obj.exhausted()
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorCanceledBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		ctx:    context.Background(),
+		runCtx: context.Background(),
+	}
+
+	result := obj.canceled()
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorCanceledRun(t *testing.T) {
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj := &Depaginator[string]{
+		ctx:    context.Background(),
+		runCtx: runCtx,
+	}
+
+	result := obj.canceled()
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorCanceledNoRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj := &Depaginator[string]{
+		ctx: ctx,
+	}
+
+	result := obj.canceled()
+
+	assert.True(t, result)
+}
+
+func TestDepaginatorContiguousBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		perPage:  5,
+		received: map[int]int{0: 5, 1: 5, 3: 5},
+	}
+
+	result := obj.contiguous()
+
+	assert.Equal(t, 10, result)
+}
+
+func TestDepaginatorContiguousShortPage(t *testing.T) {
+	obj := &Depaginator[string]{
+		perPage:  5,
+		received: map[int]int{0: 5, 1: 3, 2: 5},
+	}
+
+	result := obj.contiguous()
+
+	assert.Equal(t, 8, result)
+}
+
+func TestDepaginatorContiguousNone(t *testing.T) {
+	obj := &Depaginator[string]{
+		perPage:  5,
+		received: map[int]int{1: 5},
+	}
+
+	result := obj.contiguous()
+
+	assert.Equal(t, 0, result)
+}
+
+func TestDepaginatorUpdateInternal(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	u := &mockUpdate{}
+
+	obj.update(u)
+
+	close(obj.updates)
+	assert.Len(t, obj.updates, 1)
+	assert.Same(t, u, <-obj.updates)
+}
+
+func TestDepaginatorUpdateInternalDaemonExited(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	close(obj.done)
+	u := &mockUpdate{}
+
+	result := obj.update(u)
+
+	assert.False(t, result)
+}
+
+func TestDepaginatorSnapshotBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+	var total int
+
+	obj.snapshot(func(depag *Depaginator[string]) {
+		total = depag.totalItems
+	})
+
+	close(obj.updates)
+	assert.Equal(t, 20, total)
+}
+
+func TestDepaginatorSnapshotDaemonExited(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	close(obj.done)
+	var total int
+
+	obj.snapshot(func(depag *Depaginator[string]) {
+		total = depag.totalItems
+	})
+
+	assert.Equal(t, 20, total)
+}
+
+func TestDepaginatorAcquireUnlimited(t *testing.T) {
+	obj := &Depaginator[string]{}
+
+	err := obj.acquire(context.Background())
+	obj.release()
+
+	assert.NoError(t, err)
+}
+
+func TestDepaginatorAcquireBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		slots: make(chan struct{}, 2),
+	}
+
+	err := obj.acquire(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, obj.slots, 1)
+
+	obj.release()
+
+	assert.Len(t, obj.slots, 0)
+}
+
+func TestDepaginatorAcquireCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj := &Depaginator[string]{
+		slots: make(chan struct{}, 1),
+	}
+	obj.slots <- struct{}{}
+
+	err := obj.acquire(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, obj.slots, 1)
+}
+
+func TestDepaginatorThrottleUnset(t *testing.T) {
+	obj := &Depaginator[string]{}
+
+	err := obj.throttle(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestDepaginatorThrottleBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		limiter: rate.NewLimiter(rate.Inf, 1),
+	}
+
+	err := obj.throttle(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestDepaginatorThrottleCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj := &Depaginator[string]{
+		limiter: rate.NewLimiter(1, 1),
+	}
+
+	err := obj.throttle(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDepaginatorThrottleBurst(t *testing.T) {
+	obj := &Depaginator[string]{
+		limiter: rate.NewLimiter(1, 0),
+	}
+
+	err := obj.throttle(context.Background())
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, context.Canceled)
+}
+
+func TestDepaginatorAwaitHealthyUnset(t *testing.T) {
+	obj := &Depaginator[string]{}
+
+	err := obj.awaitHealthy(context.Background())
+
+	assert.NoError(t, err)
+}
+
+func TestDepaginatorAwaitHealthyRecovers(t *testing.T) {
+	calls := 0
+	obj := &Depaginator[string]{
+		healthy: func() bool {
+			calls++
+			return calls > 2
+		},
+	}
+
+	err := obj.awaitHealthy(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDepaginatorAwaitHealthyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj := &Depaginator[string]{
+		healthy: func() bool {
+			return false
+		},
+	}
+
+	err := obj.awaitHealthy(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDepaginatorPublish(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 10,
+		totalPages: 4,
+		perPage:    3,
+	}
+
+	obj.publish()
+
+	assert.Equal(t, Totals{
+		TotalItems: 10,
+		TotalPages: 4,
+		PerPage:    3,
+	}, obj.published())
+}
+
+func TestDepaginatorPublishedUnset(t *testing.T) {
+	obj := &Depaginator[string]{}
+
+	result := obj.published()
+
+	assert.Equal(t, Totals{}, result)
+}
+
+func TestDepaginatorSample(t *testing.T) {
+	obj := &Depaginator[string]{
+		sampler: make(chan struct{}),
+		sampled: make(chan struct{}),
+	}
+	obj.active.Store(2)
+	samples := make(chan int)
+
+	go obj.sample(time.Millisecond, func(active int) {
+		samples <- active
+	})
+
+	assert.Equal(t, 2, <-samples)
+	go func() {
+		for range samples {
+		}
+	}()
+	close(obj.sampler)
+	<-obj.sampled
+	close(samples)
+}
+
+func TestDepaginatorRetrieveNoRetry(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager: pager,
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, assert.AnError).Once()
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveTraced(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	tracer := &fakeTracer{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 2,
+		tracer:   tracer,
+	}
+	req := PageRequest{PageIndex: 5}
+	spanCtx := mock.MatchedBy(func(c context.Context) bool {
+		return c.Value(spanKey{}) != nil
+	})
+	pager.On("GetPage", spanCtx, obj, req).Return(nil, assert.AnError).Once()
+	pager.On("GetPage", spanCtx, obj, req).Return([]string{"one", "two"}, nil).Once()
+
+	result, err := obj.retrieve(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, result.Items)
+	assert.Equal(t, []*fakeSpan{
+		{name: SpanName, pageIndex: 5, ended: true, err: assert.AnError},
+		{name: SpanName, pageIndex: 5, ended: true},
+	}, tracer.spans)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveMetrics(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	metrics := &fakeMetrics{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 2,
+		metrics:  metrics,
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, assert.AnError).Once()
+	pager.On("GetPage", ctx, obj, req).Return([]string{"one", "two"}, nil).Once().Run(func(mock.Arguments) {
+		time.Sleep(time.Millisecond)
+	})
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []error{assert.AnError, nil}, metrics.fetched)
+	require.Len(t, metrics.durations, 2)
+	assert.GreaterOrEqual(t, metrics.durations[1], time.Millisecond)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveRecovers(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	var delays []int
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 3,
+		backoff: func(attempt int) time.Duration {
+			delays = append(delays, attempt)
+			return time.Millisecond
+		},
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, assert.AnError).Twice()
+	pager.On("GetPage", ctx, obj, req).Return([]string{"one", "two"}, nil).Once()
+
+	result, err := obj.retrieve(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, result.Items)
+	assert.Equal(t, []int{1, 2}, delays)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveExhausted(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 3,
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, assert.AnError).Times(3)
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveBudget(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 4,
+		retries:  4,
+	}
+	req1 := PageRequest{PageIndex: 1}
+	req2 := PageRequest{PageIndex: 2}
+	pager.On("GetPage", ctx, obj, req1).Return(nil, assert.AnError).Times(4)
+	pager.On("GetPage", ctx, obj, req2).Return(nil, assert.AnError).Times(2)
+
+	_, err1 := obj.retrieve(ctx, req1)
+	_, err2 := obj.retrieve(ctx, req2)
+
+	assert.ErrorIs(t, err1, assert.AnError)
+	assert.ErrorIs(t, err2, assert.AnError)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveBudgetExhausted(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 4,
+		retries:  4,
+	}
+	obj.retried.Store(4)
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, assert.AnError).Once()
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveContextError(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 3,
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, context.DeadlineExceeded).Once()
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrievePermanentError(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 3,
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, retryableError(false)).Once()
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, retryableError(false))
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveRetryableError(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 3,
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, retryableError(true)).Times(3)
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, retryableError(true))
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorRetrieveCanceledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		pager:    pager,
+		attempts: 3,
+		backoff: func(attempt int) time.Duration {
+			cancel()
+			return time.Hour
+		},
+	}
+	req := PageRequest{PageIndex: 5}
+	pager.On("GetPage", ctx, obj, req).Return(nil, assert.AnError).Once()
+
+	_, err := obj.retrieve(ctx, req)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorGetPageBase(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		ctx:     ctx,
+		runCtx:  ctx,
+		pager:   pager,
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	req := PageRequest{
+		PageIndex: 5,
+		Request:   "five",
+	}
+	pager.On("GetPage", mock.Anything, obj, req).Return([]string{"one", "two", "three"}, nil)
+
+	obj.getPage(req, nil)
+
+	close(obj.updates)
+	updates := []update[string]{}
+	for u := range obj.updates {
+		updates = append(updates, u)
+	}
+	assert.Len(t, updates, 4)
+	require.IsType(t, cancelerFor[string]{}, updates[0])
+	assert.Equal(t, 5, updates[0].(cancelerFor[string]).page)
+	assert.Equal(t, withdrawCanceler[string](5), updates[1])
+	assert.Equal(t, itemHandler[string]{
+		idx:  5,
+		req:  "five",
+		page: []string{"one", "two", "three"},
+	}, updates[2])
+	assert.Equal(t, pageDone[string]{}, updates[3])
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorGetPageDryRun(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		ctx:     ctx,
+		runCtx:  ctx,
+		pager:   pager,
+		dryRun:  true,
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	req := PageRequest{
+		PageIndex: 5,
+		Request:   "five",
+	}
+
+	obj.getPage(req, nil)
+
+	close(obj.updates)
+	updates := []update[string]{}
+	for u := range obj.updates {
+		updates = append(updates, u)
+	}
+	assert.Equal(t, []update[string]{
+		plannedPage[string](5),
+		pageDone[string]{},
+	}, updates)
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorGetPageError(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	obj := &Depaginator[string]{
+		ctx:     ctx,
+		runCtx:  ctx,
+		pager:   pager,
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	req := PageRequest{
+		PageIndex: 5,
+		Request:   "five",
+	}
+	pager.On("GetPage", mock.Anything, obj, req).Return(nil, assert.AnError)
+
+	obj.getPage(req, nil)
+
+	close(obj.updates)
+	updates := []update[string]{}
+	for u := range obj.updates {
+		updates = append(updates, u)
+	}
+	assert.Len(t, updates, 4)
+	require.IsType(t, cancelerFor[string]{}, updates[0])
+	assert.Equal(t, 5, updates[0].(cancelerFor[string]).page)
+	assert.Equal(t, withdrawCanceler[string](5), updates[1])
+	assert.Equal(t, errorSaver[string]{
+		req: req,
+		err: assert.AnError,
+	}, updates[2])
+	assert.Equal(t, pageDone[string]{}, updates[3])
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorGetPagePostPageHook(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	var hooked []string
+	obj := &Depaginator[string]{
+		ctx:     ctx,
+		runCtx:  ctx,
+		pager:   pager,
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	obj.postPage = func(state State, req PageRequest, items []string) {
+		assert.Same(t, obj, state)
+		assert.Equal(t, 5, req.PageIndex)
+		hooked = items
+		state.Update(TotalPages(6))
+	}
+	req := PageRequest{
+		PageIndex: 5,
+		Request:   "five",
+	}
+	pager.On("GetPage", mock.Anything, obj, req).Return([]string{"one", "two", "three"}, nil)
+
+	obj.getPage(req, nil)
+
+	close(obj.updates)
+	updates := []update[string]{}
+	for u := range obj.updates {
+		updates = append(updates, u)
+	}
+	assert.Equal(t, []string{"one", "two", "three"}, hooked)
+	assert.Len(t, updates, 5)
+	assert.Equal(t, bundle[string]{totalPages[string](6)}, updates[2])
+	assert.Equal(t, itemHandler[string]{
+		idx:  5,
+		req:  "five",
+		page: []string{"one", "two", "three"},
+	}, updates[3])
+	pager.AssertExpectations(t)
+}
+
+func TestDepaginatorUpdateBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.Update(TotalItems(20), TotalPages(4), PerPage(5))
+
+	select {
+	case update := <-obj.updates:
+		assert.Equal(t, bundle[string]{
+			totalItems[string](20),
+			totalPages[string](4),
+			perPage[string](5),
+		}, update)
+	default:
+		assert.Fail(t, "Update failed to send update on channel")
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorUpdateNoUpdates(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.Update(20, 4, 5)
+
+	select {
+	case <-obj.updates:
+		assert.Fail(t, "Update sent unexpected update on channel")
+	default:
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorRequest(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.Request(3, "three")
+
+	select {
+	case update := <-obj.updates:
+		assert.Equal(t, pageRequest[string]{
+			idx: 3,
+			req: "three",
+		}, update)
+	default:
+		assert.Fail(t, "Request failed to send update on channel")
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorRequestNext(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.RequestNext("next")
+
+	select {
+	case update := <-obj.updates:
+		assert.Equal(t, nextRequest[string]{
+			req: "next",
+		}, update)
+	default:
+		assert.Fail(t, "RequestNext failed to send update on channel")
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorPlannedPages(t *testing.T) {
+	obj := &Depaginator[string]{
+		planned: []int{0, 3, 1, 2},
+	}
+
+	result := obj.PlannedPages()
+
+	assert.Equal(t, []int{0, 1, 2, 3}, result)
+	assert.Equal(t, []int{0, 3, 1, 2}, obj.planned)
+}
+
+func TestDepaginatorPageHistory(t *testing.T) {
+	history := []PageMeta{
+		{
+			Request: PageRequest{
+				PageIndex: 0,
+			},
+			ItemCount: 5,
+		},
+	}
+	obj := &Depaginator[string]{
+		history: history,
+	}
+
+	result := obj.PageHistory()
+
+	assert.Equal(t, history, result)
+}
+
+func TestUpdateName(t *testing.T) {
+	assert.Equal(t, "pageRequest", updateName(pageRequest[string]{}))
+	assert.Equal(t, "withdrawCanceler", updateName(withdrawCanceler[*PageRequest](0)))
+	assert.Equal(t, "PageRequest", updateName(PageRequest{}))
+}
+
+func TestDepaginatorContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	obj := &Depaginator[string]{
+		ctx: ctx,
+	}
+
+	result := obj.Context()
+
+	assert.Same(t, ctx, result)
+}
+
+func TestDepaginatorPagesBase(t *testing.T) {
+	stream := make(chan PageResult[string])
+	obj := &Depaginator[string]{
+		stream: stream,
+	}
+
+	result := obj.Pages()
+
+	assert.Equal(t, (<-chan PageResult[string])(stream), result)
+}
+
+func TestDepaginatorPagesDisabled(t *testing.T) {
+	obj := &Depaginator[string]{}
+
+	result := obj.Pages()
+
+	assert.Nil(t, result)
+}
+
+func TestDepaginatorPageStatus(t *testing.T) {
+	obj := &Depaginator[string]{
+		pageErrs: map[int]error{
+			2: assert.AnError,
+		},
+	}
+	obj.retrieved.CheckAndSet(0)
+
+	fetched, err := obj.PageStatus(0)
+	assert.True(t, fetched)
+	assert.NoError(t, err)
+
+	fetched, err = obj.PageStatus(2)
+	assert.False(t, fetched)
+	assert.Same(t, assert.AnError, err)
+
+	fetched, err = obj.PageStatus(7)
+	assert.False(t, fetched)
+	assert.NoError(t, err)
+}
+
+func TestDepaginatorProgress(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	obj.handled.Store(5)
+	close(obj.done)
+
+	result := obj.Progress()
+
+	assert.Equal(t, Progress{
+		Handled: 5,
+		Total:   20,
+	}, result)
+}
+
+func TestDepaginatorTotalItemsRunning(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.TotalItems()
+
+	close(obj.updates)
+	assert.Equal(t, 20, result)
+}
+
+func TestDepaginatorTotalItemsDone(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalItems: 20,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	close(obj.done)
+
+	result := obj.TotalItems()
+
+	assert.Equal(t, 20, result)
+}
+
+func TestDepaginatorTotalPagesRunning(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalPages: 4,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.TotalPages()
+
+	close(obj.updates)
+	assert.Equal(t, 4, result)
+}
+
+func TestDepaginatorTotalPagesDone(t *testing.T) {
+	obj := &Depaginator[string]{
+		totalPages: 4,
+		updates:    make(chan update[string]),
+		done:       make(chan struct{}),
+	}
+	close(obj.done)
+
+	result := obj.TotalPages()
+
+	assert.Equal(t, 4, result)
+}
+
+func TestDepaginatorCheckpoint(t *testing.T) {
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+		updates:   make(chan update[string]),
+		done:      make(chan struct{}),
+	}
+	obj.completed.CheckAndSet(1)
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.Checkpoint()
+
+	close(obj.updates)
+	restored := &pageMap{}
+	assert.NoError(t, restored.Restore(result))
+	assert.Equal(t, obj.completed, restored)
+}
+
+func TestDepaginatorCheckpointDisabled(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.Checkpoint()
+
+	close(obj.updates)
+	assert.Nil(t, result)
+}
+
+func TestDepaginatorCheckpointRequests(t *testing.T) {
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+		requests: map[int]any{
+			2: "two",
+			3: "bad",
+		},
+		encodeReq: func(req any) ([]byte, error) {
+			if req == "bad" {
+				return nil, assert.AnError
+			}
+			return []byte(req.(string)), nil
+		},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	obj.completed.CheckAndSet(1)
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.Checkpoint()
+
+	close(obj.updates)
+	completed, requests, err := decodeCheckpoint(result)
+	require.NoError(t, err)
+	assert.Equal(t, map[int][]byte{2: []byte("two")}, requests)
+	restored := &pageMap{}
+	assert.NoError(t, restored.Restore(completed))
+	assert.Equal(t, obj.completed, restored)
+}
+
+func TestDepaginatorRestoreBase(t *testing.T) {
+	completed := &pageMap{}
+	completed.CheckAndSet(1)
+	checkpoint := encodeCheckpoint(completed.Checkpoint(), map[int][]byte{
+		0: []byte("zero"),
+		4: []byte("four"),
+		2: []byte("two"),
+	})
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+	}
+
+	result, err := obj.restore(checkpoint, func(data []byte) (any, error) {
+		return string(data), nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, resume[string]{
+		{PageIndex: 2, Request: "two"},
+		{PageIndex: 4, Request: "four"},
+	}, result)
+	assert.Equal(t, completed, obj.completed)
+}
+
+func TestDepaginatorRestorePageMapOnly(t *testing.T) {
+	completed := &pageMap{}
+	completed.CheckAndSet(1)
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+	}
+
+	result, err := obj.restore(completed.Checkpoint(), nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, completed, obj.completed)
+}
+
+func TestDepaginatorRestoreMalformed(t *testing.T) {
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+	}
+
+	result, err := obj.restore([]byte{requestCheckpointVersion, 5}, nil)
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+	assert.Nil(t, result)
+}
+
+func TestDepaginatorRestoreBadPageMap(t *testing.T) {
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+	}
+
+	result, err := obj.restore(encodeCheckpoint([]byte{0}, nil), nil)
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+	assert.Nil(t, result)
+}
+
+func TestDepaginatorRestoreNoCodec(t *testing.T) {
+	checkpoint := encodeCheckpoint((&pageMap{}).Checkpoint(), map[int][]byte{
+		2: []byte("two"),
+	})
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+	}
+
+	result, err := obj.restore(checkpoint, nil)
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+	assert.Nil(t, result)
+}
+
+func TestDepaginatorRestoreDecodeError(t *testing.T) {
+	checkpoint := encodeCheckpoint((&pageMap{}).Checkpoint(), map[int][]byte{
+		2: []byte("two"),
+	})
+	obj := &Depaginator[string]{
+		completed: &pageMap{},
+	}
+
+	result, err := obj.restore(checkpoint, func(data []byte) (any, error) {
+		return nil, assert.AnError
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+	assert.ErrorContains(t, err, assert.AnError.Error())
+	assert.Nil(t, result)
+}
+
+func TestDepaginatorBufferedPagesRunning(t *testing.T) {
+	obj := &Depaginator[string]{
+		buffered: 2,
+		updates:  make(chan update[string]),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.BufferedPages()
+
+	close(obj.updates)
+	assert.Equal(t, 2, result)
+}
+
+func TestDepaginatorBufferedPagesDone(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	close(obj.done)
+
+	result := obj.BufferedPages()
+
+	assert.Equal(t, 0, result)
+}
+
+func TestDepaginatorErrorsSoFar(t *testing.T) {
+	obj := &Depaginator[string]{
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+		},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.ErrorsSoFar()
+
+	close(obj.updates)
+	assert.Equal(t, []PageError{
+		{
+			PageRequest: PageRequest{PageIndex: 2},
+			Err:         assert.AnError,
+		},
+	}, result)
+}
+
+func TestDepaginatorErrBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{PageIndex: 2},
+				Err:         assert.AnError,
+			},
+			ErrStalled,
+		},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	err := obj.Err()
+
+	close(obj.updates)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorIs(t, err, ErrStalled)
+}
+
+func TestDepaginatorErrNone(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	err := obj.Err()
+
+	close(obj.updates)
+	assert.NoError(t, err)
+}
+
+func TestDepaginatorRequestRangeBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.RequestRange(2, 5, func(idx int) any {
+		return idx * 10
+	})
+
+	select {
+	case update := <-obj.updates:
+		assert.Equal(t, bundle[string]{
+			pageRequest[string]{idx: 2, req: 20},
+			pageRequest[string]{idx: 3, req: 30},
+			pageRequest[string]{idx: 4, req: 40},
+		}, update)
+	default:
+		assert.Fail(t, "RequestRange failed to send update on channel")
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorRequestRangeNilFunc(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.RequestRange(2, 4, nil)
+
+	select {
+	case update := <-obj.updates:
+		assert.Equal(t, bundle[string]{
+			pageRequest[string]{idx: 2},
+			pageRequest[string]{idx: 3},
+		}, update)
+	default:
+		assert.Fail(t, "RequestRange failed to send update on channel")
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorRequestRangeEmpty(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.RequestRange(4, 4, nil)
+
+	assert.Len(t, obj.updates, 0)
+	close(obj.updates)
+}
+
+func TestDepaginatorCancel(t *testing.T) {
+	runCtx, abort := context.WithCancel(context.Background())
+	obj := &Depaginator[string]{
+		runCtx: runCtx,
+		abort:  abort,
+	}
+
+	obj.Cancel()
+
+	assert.ErrorIs(t, runCtx.Err(), context.Canceled)
+}
+
+func TestDepaginatorMarkLast(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string], DefaultCapacity),
+	}
+
+	obj.MarkLast(3)
+
+	select {
+	case update := <-obj.updates:
+		assert.Equal(t, lastPage[string](3), update)
+	default:
+		assert.Fail(t, "MarkLast failed to send update on channel")
+	}
+	close(obj.updates)
+}
+
+func TestDepaginatorConfig(t *testing.T) {
+	obj := &Depaginator[string]{
+		config: Config{
+			Capacity: 50,
+		},
+	}
+
+	result := obj.Config()
+
+	assert.Equal(t, Config{Capacity: 50}, result)
+}
+
+func TestConfigString(t *testing.T) {
+	obj := Config{
+		TotalItems:        100,
+		TotalPages:        10,
+		PerPage:           10,
+		Capacity:          50,
+		MaxConcurrency:    4,
+		HandleConcurrency: 2,
+		RetryAttempts:     3,
+		Limit:             75,
+		AutoStrategy:      true,
+	}
+
+	result := obj.String()
+
+	assert.Equal(t, "totalItems=100 totalPages=10 perPage=10 capacity=50 maxConcurrency=4 handleConcurrency=2 retryAttempts=3 retryBudget=0 limit=75 autoStrategy=true failFast=false", result)
+}
+
+func TestDepaginatorPerPageRunning(t *testing.T) {
+	obj := &Depaginator[string]{
+		perPage: 50,
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		for u := range obj.updates {
+			u.applyUpdate(obj)
+		}
+	}()
+
+	result := obj.PerPage()
+
+	close(obj.updates)
+	assert.Equal(t, 50, result)
+}
+
+func TestDepaginatorPerPageDone(t *testing.T) {
+	obj := &Depaginator[string]{
+		perPage: 50,
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	close(obj.done)
 
 	result := obj.PerPage()
 
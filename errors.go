@@ -16,6 +16,47 @@
 
 package depaginator
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidBounds is the error reported for a page when the Bounds
+// of a [CompoundPage] are out of order or out of range.
+var ErrInvalidBounds = errors.New("invalid compound page bounds")
+
+// ErrStalled is the error reported when an iteration is canceled
+// because no page was retrieved within the timeout set by the
+// [WithActivityTimeout] option.
+var ErrStalled = errors.New("no page retrieved within the activity timeout")
+
+// ErrConflictingOptions is the error reported by [Depaginator.Wait]
+// when options that may not be combined are passed to [Depaginate].
+var ErrConflictingOptions = errors.New("conflicting options")
+
+// ErrNotCaptured is the error returned by [ReplayPager] for a page
+// for which no capture is available.
+var ErrNotCaptured = errors.New("page not captured")
+
+// ErrUnexpectedStatus is the error returned by [LinkPager] when the
+// server responds with a status other than 200 OK.  It is wrapped with
+// the URL and the status received.
+var ErrUnexpectedStatus = errors.New("unexpected HTTP status")
+
+// ErrPanic is the error reported by [Depaginator.Wait] when a panic
+// occurs while the daemon goroutine is processing an update, such as
+// in an [Updater].  The iteration is canceled when this happens.
+var ErrPanic = errors.New("panic while processing update")
+
+// ErrInvalidCheckpoint is the error reported by [Depaginator.Wait]
+// when the checkpoint passed to the [WithCheckpoint] option cannot be
+// decoded.  No pages are retrieved when this happens.
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// ErrInvalidCount is the error reported by [RoundRobinHandler] when
+// its Count field is not positive.  It is wrapped with the count.
+var ErrInvalidCount = errors.New("partition count must be positive")
+
 // PageError contains an error returned by the [PageGetter.GetPage]
 // callback, along with the failing page request.
 type PageError struct {
@@ -32,3 +73,45 @@ func (pe PageError) Error() string {
 func (pe PageError) Unwrap() error {
 	return pe.Err
 }
+
+// ItemError contains an error that occurred while handling an item,
+// such as the expiry of the timeout set by the [WithHandleTimeout]
+// option, along with the index of the item.
+type ItemError struct {
+	Index int   // The index of the item
+	Err   error // The error that occurred
+}
+
+// Error returns the error message.
+func (ie ItemError) Error() string {
+	return fmt.Sprintf("item %d: %s", ie.Index, ie.Err)
+}
+
+// Unwrap retrieves the underlying error.
+func (ie ItemError) Unwrap() error {
+	return ie.Err
+}
+
+// RetryableError may be implemented by errors returned by
+// [PageGetter.GetPage] to control whether the page retrieval is
+// retried when the [WithRetry] option is used.  Errors that do not
+// implement RetryableError, either directly or through an error they
+// wrap, are always retried.
+type RetryableError interface {
+	error
+
+	// Retryable reports whether the page retrieval that returned the
+	// error should be retried.
+	Retryable() bool
+}
+
+// retryable determines whether a page retrieval that failed with the
+// specified error should be retried.
+func retryable(err error) bool {
+	var re RetryableError
+	if errors.As(err, &re) {
+		return re.Retryable()
+	}
+
+	return true
+}
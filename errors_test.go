@@ -17,6 +17,7 @@
 package depaginator
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -41,3 +42,58 @@ func TestPageErrorUnwrap(t *testing.T) {
 
 	assert.Same(t, assert.AnError, result)
 }
+
+func TestItemErrorError(t *testing.T) {
+	obj := ItemError{
+		Index: 5,
+		Err:   assert.AnError,
+	}
+
+	result := obj.Error()
+
+	assert.Equal(t, "item 5: "+assert.AnError.Error(), result)
+}
+
+func TestItemErrorUnwrap(t *testing.T) {
+	obj := ItemError{
+		Err: assert.AnError,
+	}
+
+	result := obj.Unwrap()
+
+	assert.Same(t, assert.AnError, result)
+}
+
+type retryableError bool
+
+func (e retryableError) Error() string {
+	return "retryable error"
+}
+
+func (e retryableError) Retryable() bool {
+	return bool(e)
+}
+
+func TestRetryableBase(t *testing.T) {
+	result := retryable(assert.AnError)
+
+	assert.True(t, result)
+}
+
+func TestRetryableTrue(t *testing.T) {
+	result := retryable(retryableError(true))
+
+	assert.True(t, result)
+}
+
+func TestRetryableFalse(t *testing.T) {
+	result := retryable(retryableError(false))
+
+	assert.False(t, result)
+}
+
+func TestRetryableWrapped(t *testing.T) {
+	result := retryable(fmt.Errorf("wrapped: %w", retryableError(false)))
+
+	assert.False(t, result)
+}
@@ -0,0 +1,99 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// FilterHandler is an implementation of [Handler] that wraps another
+// [Handler], passing on only the items for which a predicate returns
+// true.  Calls to [Starter.Start], [Updater.Update], and [Doner.Done]
+// are forwarded if the wrapped handler implements the corresponding
+// interfaces.  A FilterHandler must be constructed with
+// [NewFilterHandler].
+//
+// By default, the index passed to the wrapped handler is the
+// original, global index of the item, so the indexes seen by the
+// wrapped handler have gaps where items were dropped; a [ListHandler]
+// will contain zero values at those positions, for example.  If the
+// Reindex field is set, items are instead numbered consecutively,
+// from 0, in the order they pass the predicate, and the total number
+// of items passed to [Doner.Done] is the number of items that passed.
+// As pages are handled concurrently, that order is generally not the
+// order of the original indexes.
+type FilterHandler[T any] struct {
+	Reindex bool // Number the items passed on consecutively
+
+	pred    func(item T) bool // Predicate selecting the items to pass on
+	inner   Handler[T]        // Wrapped handler
+	matched atomic.Int64      // Number of items passed on
+}
+
+// NewFilterHandler constructs a [FilterHandler] that passes on to
+// inner only those items for which pred returns true.
+func NewFilterHandler[T any](pred func(item T) bool, inner Handler[T]) *FilterHandler[T] {
+	return &FilterHandler[T]{
+		pred:  pred,
+		inner: inner,
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (fh *FilterHandler[T]) Start(ctx context.Context, totalItems, totalPages, perPage int) {
+	fh.matched.Store(0)
+	if starter, ok := fh.inner.(Starter); ok {
+		starter.Start(ctx, totalItems, totalPages, perPage)
+	}
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (fh *FilterHandler[T]) Handle(ctx context.Context, idx int, item T) {
+	if !fh.pred(item) {
+		return
+	}
+
+	n := fh.matched.Add(1)
+	if fh.Reindex {
+		idx = int(n - 1)
+	}
+	fh.inner.Handle(ctx, idx, item)
+}
+
+// Update is called with the new values of total items, total pages,
+// and items per page.  It should not undertake extensive processing.
+func (fh *FilterHandler[T]) Update(ctx context.Context, totalItems, totalPages, perPage int) {
+	if updater, ok := fh.inner.(Updater); ok {
+		updater.Update(ctx, totalItems, totalPages, perPage)
+	}
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (fh *FilterHandler[T]) Done(ctx context.Context, totalItems, totalPages, perPage int) {
+	if fh.Reindex {
+		totalItems = int(fh.matched.Load())
+	}
+	if doner, ok := fh.inner.(Doner); ok {
+		doner.Done(ctx, totalItems, totalPages, perPage)
+	}
+}
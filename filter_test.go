@@ -0,0 +1,197 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// even is a predicate for testing [FilterHandler].
+func even(item string) bool {
+	n, _ := strconv.Atoi(item)
+	return n%2 == 0
+}
+
+func TestFilterHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &FilterHandler[string]{})
+	assert.Implements(t, (*Starter)(nil), &FilterHandler[string]{})
+	assert.Implements(t, (*Updater)(nil), &FilterHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &FilterHandler[string]{})
+}
+
+func TestNewFilterHandler(t *testing.T) {
+	inner := &mockHandler{}
+
+	result := NewFilterHandler[string](even, inner)
+
+	assert.NotNil(t, result.pred)
+	assert.Same(t, inner, result.inner)
+	assert.False(t, result.Reindex)
+}
+
+func TestFilterHandlerStartBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Start", ctx, 1, 2, 3)
+	obj := NewFilterHandler[string](even, inner)
+	obj.matched.Store(5)
+
+	obj.Start(ctx, 1, 2, 3)
+
+	assert.Equal(t, int64(0), obj.matched.Load())
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerStartNoStarter(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	obj := NewFilterHandler[string](even, inner)
+
+	obj.Start(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerHandleBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	inner.On("Handle", ctx, 4, "4")
+	obj := NewFilterHandler[string](even, inner)
+
+	obj.Handle(ctx, 3, "3")
+	obj.Handle(ctx, 4, "4")
+
+	assert.Equal(t, int64(1), obj.matched.Load())
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerHandleReindex(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	inner.On("Handle", ctx, 0, "4")
+	inner.On("Handle", ctx, 1, "2")
+	obj := NewFilterHandler[string](even, inner)
+	obj.Reindex = true
+
+	obj.Handle(ctx, 4, "4")
+	obj.Handle(ctx, 3, "3")
+	obj.Handle(ctx, 2, "2")
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerUpdateBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Update", ctx, 1, 2, 3)
+	obj := NewFilterHandler[string](even, inner)
+
+	obj.Update(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerUpdateNoUpdater(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	obj := NewFilterHandler[string](even, inner)
+
+	obj.Update(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerDoneBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Done", ctx, 10, 2, 5)
+	obj := NewFilterHandler[string](even, inner)
+	obj.matched.Store(4)
+
+	obj.Done(ctx, 10, 2, 5)
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerDoneReindex(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Done", ctx, 4, 2, 5)
+	obj := NewFilterHandler[string](even, inner)
+	obj.Reindex = true
+	obj.matched.Store(4)
+
+	obj.Done(ctx, 10, 2, 5)
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerDoneNoDoner(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	obj := NewFilterHandler[string](even, inner)
+
+	obj.Done(ctx, 10, 2, 5)
+
+	inner.AssertExpectations(t)
+}
+
+func TestFilterHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, NewFilterHandler[string](even, result), WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "", "2", "", "4", "", "6", "", "8", "", "10"}, result.Items)
+}
+
+func TestFilterHandlerDepaginateReindex(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+	obj := NewFilterHandler[string](even, result)
+	obj.Reindex = true
+
+	d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	sort.Slice(result.Items, func(i, j int) bool {
+		a, _ := strconv.Atoi(result.Items[i])
+		b, _ := strconv.Atoi(result.Items[j])
+		return a < b
+	})
+	assert.Equal(t, []string{"0", "2", "4", "6", "8", "10"}, result.Items)
+}
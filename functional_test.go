@@ -17,11 +17,23 @@
 package depaginator
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 // Number of times to run tests; running the tests multiple times
@@ -126,3 +138,3091 @@ func TestAppendFunction(t *testing.T) {
 		})
 	}
 }
+
+// SelfPagedData is a pager that never requests additional pages
+// itself, relying on the [Depaginator] to do so.
+type SelfPagedData struct {
+	sync.Mutex
+
+	data        []string    // Actual data
+	perPage     int         // Number of results per page
+	reportPages bool        // Report TotalPages on the first page
+	fetched     map[int]int // Count of fetches for each page
+}
+
+func (sp *SelfPagedData) GetPage(_ context.Context, depag State, req PageRequest) ([]string, error) {
+	sp.Lock()
+	defer sp.Unlock()
+	if sp.fetched == nil {
+		sp.fetched = map[int]int{}
+	}
+	sp.fetched[req.PageIndex]++
+
+	// Report the metadata
+	if req.PageIndex == 0 {
+		if sp.reportPages {
+			depag.Update(TotalPages((len(sp.data)+sp.perPage-1)/sp.perPage), PerPage(sp.perPage))
+		} else {
+			depag.Update(PerPage(sp.perPage))
+		}
+	}
+
+	// Now generate and return a page
+	if req.PageIndex*sp.perPage >= len(sp.data) {
+		return nil, nil
+	}
+	subset := sp.data[req.PageIndex*sp.perPage:]
+	if len(subset) > sp.perPage {
+		subset = subset[:sp.perPage]
+	}
+	dest := make([]string, len(subset))
+	copy(dest, subset)
+	return dest, nil
+}
+
+func TestAutoStrategyTotalsKnown(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("auto-known-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage:     3,
+				reportPages: true,
+			}
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}, data.fetched)
+		})
+	}
+}
+
+func TestAutoStrategyTotalsUnknown(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("auto-unknown-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage: 3,
+			}
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}, data.fetched)
+		})
+	}
+}
+
+func TestAutoStrategyDisabled(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 1}, data.fetched)
+}
+
+func TestMarkLast(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("mark-last-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+				depag.Update(PerPage(2))
+				if req.PageIndex == 0 {
+					for i := 1; i < 10; i++ {
+						depag.Request(i, nil)
+					}
+				}
+				if req.PageIndex == 2 {
+					depag.MarkLast(2)
+				}
+				return []string{
+					fmt.Sprintf("%d", req.PageIndex*2),
+					fmt.Sprintf("%d", req.PageIndex*2+1),
+				}, nil
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, data, result)
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0", "1", "2", "3", "4", "5"}, result.Items)
+		})
+	}
+}
+
+func TestWaitTwice(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data:      []string{"0", "1", "2", "3", "4"},
+		perPage:   2,
+		pageAhead: 2,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result)
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			errs <- d.Wait()
+		}()
+	}
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.NoError(t, <-errs)
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, result.Items)
+	assert.Equal(t, 5, d.Result().TotalItems)
+}
+
+func TestRequestNext(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("request-next-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			cursors := map[string]string{
+				"":   "c1",
+				"c1": "c2",
+				"c2": "c3",
+			}
+			data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+				depag.Update(PerPage(1))
+				cursor, _ := req.Request.(string)
+				if next, ok := cursors[cursor]; ok {
+					depag.RequestNext(next)
+				} else {
+					depag.MarkLast(req.PageIndex)
+				}
+				return []string{fmt.Sprintf("%d:%s", req.PageIndex, cursor)}, nil
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, data, result)
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0:", "1:c1", "2:c2", "3:c3"}, result.Items)
+		})
+	}
+}
+
+func TestPageHistory(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	history := d.PageHistory()
+	assert.Len(t, history, 4)
+	counts := map[int]int{}
+	for _, meta := range history {
+		counts[meta.Request.PageIndex] = meta.ItemCount
+		assert.Equal(t, 3, meta.PerPage)
+		assert.NoError(t, meta.Err)
+	}
+	assert.Equal(t, map[int]int{0: 3, 1: 3, 2: 3, 3: 2}, counts)
+	last := history[len(history)-1]
+	assert.Equal(t, 3, last.Request.PageIndex)
+	assert.Equal(t, 11, last.TotalItems)
+	assert.Equal(t, 4, last.TotalPages)
+}
+
+func TestPageStatus(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(3), PerPage(2))
+			depag.Request(1, nil)
+		}
+		if req.PageIndex == 1 {
+			return nil, assert.AnError
+		}
+		return []string{"a", "b"}, nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result)
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	fetched, err := d.PageStatus(0)
+	assert.True(t, fetched)
+	assert.NoError(t, err)
+	fetched, err = d.PageStatus(1)
+	assert.False(t, fetched)
+	assert.ErrorIs(t, err, assert.AnError)
+	fetched, err = d.PageStatus(2)
+	assert.False(t, fetched)
+	assert.NoError(t, err)
+}
+
+func TestProgressPolling(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result)
+	stopPoll := make(chan struct{})
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for {
+			select {
+			case <-stopPoll:
+				return
+			default:
+				_ = d.Progress()
+			}
+		}
+	}()
+	err := d.Wait()
+	close(stopPoll)
+	<-polled
+
+	assert.NoError(t, err)
+	assert.Equal(t, Progress{
+		Handled: 11,
+		Total:   11,
+	}, d.Progress())
+}
+
+func TestPollingWhileFinishing(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("polling-while-finishing-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+				if req.PageIndex < 3 {
+					depag.Request(req.PageIndex+1, nil)
+				}
+				return []string{"a", "b", "c"}, nil
+			})
+			handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {})
+
+			d := Depaginate[string](ctx, data, handler, WithLimit(5))
+			stopPoll := make(chan struct{})
+			polled := make(chan struct{})
+			go func() {
+				defer close(polled)
+				for {
+					select {
+					case <-stopPoll:
+						return
+					default:
+						_ = d.TotalItems()
+						_ = d.TotalPages()
+						_ = d.Err()
+						_ = d.Progress()
+					}
+				}
+			}()
+			err := d.Wait()
+			close(stopPoll)
+			<-polled
+
+			assert.NoError(t, err)
+			assert.Equal(t, 5, d.TotalItems())
+		})
+	}
+}
+
+func TestPartialResults(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("partial-%d", i), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			page1 := make(chan struct{})
+			data := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				depag.Update(PerPage(2))
+				switch req.PageIndex {
+				case 0:
+					for i := 1; i < 5; i++ {
+						depag.Request(i, nil)
+					}
+				case 1:
+					defer close(page1)
+				case 2:
+					<-page1
+					cancel()
+					return nil, ctx.Err()
+				}
+				return []string{
+					fmt.Sprintf("%d", req.PageIndex*2),
+					fmt.Sprintf("%d", req.PageIndex*2+1),
+				}, nil
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, data, result, WithPartialResults())
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0", "1", "2", "3"}, result.Items)
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.Update(PerPage(2))
+		if req.PageIndex == 0 {
+			depag.Request(1, nil)
+			depag.Request(2, nil)
+		}
+		if req.PageIndex == 1 {
+			return nil, assert.AnError
+		}
+		if req.PageIndex == 2 {
+			return []string{"4"}, nil
+		}
+		return []string{"0", "1"}, nil
+	})
+	var summary []RunResult
+
+	d := Depaginate[string](ctx, data, HandlerFunc[string](func(context.Context, int, string) {}), WithSummary(func(result RunResult) {
+		summary = append(summary, result)
+	}))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Len(t, summary, 1)
+	assert.Equal(t, 5, summary[0].TotalItems)
+	assert.Equal(t, 3, summary[0].TotalPages)
+	assert.Equal(t, 3, summary[0].PagesFetched)
+	assert.Equal(t, 3, summary[0].ItemsHandled)
+	assert.Len(t, summary[0].Errors, 1)
+	assert.Equal(t, 1, summary[0].Errors[0].PageRequest.PageIndex)
+	assert.Equal(t, d.Result(), summary[0])
+}
+
+func TestErrorsSoFar(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.Update(PerPage(1))
+		if req.PageIndex == 0 {
+			for i := 1; i <= 5; i++ {
+				depag.Request(i, nil)
+			}
+			return []string{"0"}, nil
+		}
+		if req.PageIndex == 5 {
+			<-release
+			return nil, nil
+		}
+		return nil, assert.AnError
+	})
+
+	d := Depaginate[string](ctx, data, HandlerFunc[string](func(context.Context, int, string) {}))
+	for len(d.ErrorsSoFar()) < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Len(t, d.ErrorsSoFar(), 4)
+}
+
+func TestConcurrentDuplicateRequests(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("duplicates-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			lock := &sync.Mutex{}
+			fetched := map[int]int{}
+			data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+				lock.Lock()
+				fetched[req.PageIndex]++
+				lock.Unlock()
+
+				// Fire off many duplicate requests concurrently
+				wg := &sync.WaitGroup{}
+				for j := 0; j < 20; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						for k := 0; k < 10; k++ {
+							depag.Request(k, nil)
+						}
+					}()
+				}
+				wg.Wait()
+
+				return []string{fmt.Sprintf("%d", req.PageIndex)}, nil
+			})
+
+			d := Depaginate[string](ctx, data, HandlerFunc[string](func(context.Context, int, string) {}))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Len(t, fetched, 10)
+			for k := 0; k < 10; k++ {
+				assert.Equal(t, 1, fetched[k], "page %d", k)
+			}
+		})
+	}
+}
+
+// stepScheduler is a deterministic [Scheduler] that queues tasks for
+// the test to run in a specific order.
+type stepScheduler struct {
+	tasks chan func() // Queued tasks
+}
+
+func (s *stepScheduler) Go(task func()) {
+	s.tasks <- task
+}
+
+func TestDeterministicInterleaving(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data:      []string{"0", "1", "2", "3", "4"},
+		perPage:   2,
+		pageAhead: 2,
+	}
+	var handled []int
+	handler := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+		handled = append(handled, idx)
+	})
+	scheduler := &stepScheduler{
+		tasks: make(chan func(), DefaultCapacity),
+	}
+
+	d := Depaginate[string](ctx, data, handler, WithScheduler(scheduler))
+
+	// Retrieve page 0, which requests pages 1 and 2
+	(<-scheduler.tasks)()
+	page1 := <-scheduler.tasks
+	page2 := <-scheduler.tasks
+	handle0 := <-scheduler.tasks
+
+	// Retrieve the short page 2 before page 1
+	page2()
+	handle2 := <-scheduler.tasks
+	page1()
+	handle1 := <-scheduler.tasks
+
+	// Handle the pages in a specific order
+	handle2()
+	handle0()
+	handle1()
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 0, 1, 2, 3}, handled)
+	assert.Equal(t, 5, d.Result().TotalItems)
+	assert.Equal(t, 3, d.Result().TotalPages)
+}
+
+func TestOverlapHandler(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.Update(PerPage(1))
+		if req.PageIndex == 0 {
+			depag.Request(1, "first")
+			depag.Request(1, "second")
+			return []string{"zero"}, nil
+		}
+		depag.MarkLast(req.PageIndex)
+		return []string{req.Request.(string)}, nil
+	})
+	var overlaps []PageRequest
+	overlap := func(existing, incoming PageRequest) PageRequest {
+		overlaps = append(overlaps, existing, incoming)
+		return incoming
+	}
+	result := &ListHandler[string]{}
+	scheduler := &stepScheduler{
+		tasks: make(chan func(), DefaultCapacity),
+	}
+
+	d := Depaginate[string](ctx, data, result, WithScheduler(scheduler), WithOverlapHandler(overlap))
+
+	// Retrieve page 0, which requests page 1 twice, then page 1
+	(<-scheduler.tasks)()
+	page1 := <-scheduler.tasks
+	handle0 := <-scheduler.tasks
+	page1()
+	handle1 := <-scheduler.tasks
+	handle0()
+	handle1()
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PageRequest{
+		{PageIndex: 1, Request: "first"},
+		{PageIndex: 1, Request: "second"},
+	}, overlaps)
+	assert.Equal(t, []string{"zero", "second"}, result.Items)
+}
+
+func TestLinkPagination(t *testing.T) {
+	ctx := context.Background()
+	srv := linkServer([][]string{{"0", "1"}, {"2", "3"}, {"4"}})
+	defer srv.Close()
+	pager := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, PerPage(2))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, result.Items)
+}
+
+func TestLinkPaginationDiscoveredPerPage(t *testing.T) {
+	ctx := context.Background()
+	srv := linkServer([][]string{{"a", "b"}, {"c", "d"}, {"e"}})
+	defer srv.Close()
+	pager := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, result.Items)
+	assert.Equal(t, 5, d.TotalItems())
+	assert.Equal(t, 3, d.TotalPages())
+}
+
+func TestOffsetPagination(t *testing.T) {
+	ctx := context.Background()
+	data := []string{"0", "1", "2", "3", "4", "5", "6"}
+	fetch := func(_ context.Context, offset, limit int) ([]string, error) {
+		if offset >= len(data) {
+			return nil, nil
+		}
+		end := offset + limit
+		if end > len(data) {
+			end = len(data)
+		}
+		return data[offset:end], nil
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, NewOffsetPageGetter(fetch, 3), result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data, result.Items)
+	assert.Equal(t, 3, d.Result().TotalPages)
+}
+
+func TestOrderedCompletion(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("ordered-completion-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := PagedData{
+				data:      make([]string, 40),
+				perPage:   4,
+				pageAhead: 10,
+			}
+			handler := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+				time.Sleep(time.Duration(rand.Intn(100)) * time.Microsecond)
+			})
+			var completed []int
+			reverse := func(pageLen int) []int {
+				order := make([]int, pageLen)
+				for i := range order {
+					order[i] = pageLen - 1 - i
+				}
+				return order
+			}
+
+			d := Depaginate[string](ctx, data, handler, WithHandleConcurrency(4), WithIntraPageOrder(reverse), WithOrderedCompletion(func(idx int) {
+				completed = append(completed, idx)
+			}))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			expected := make([]int, 40)
+			for i := range expected {
+				expected[i] = i
+			}
+			assert.Equal(t, expected, completed)
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		span, _ := ctx.Value(spanKey{}).(*fakeSpan)
+		if span == nil || span.pageIndex != req.PageIndex {
+			return nil, errors.New("page retrieved without its span")
+		}
+		depag.Update(TotalPages(3), PerPage(2))
+		if req.PageIndex == 0 {
+			depag.RequestRange(1, 3, nil)
+		}
+		if req.PageIndex == 2 {
+			return nil, assert.AnError
+		}
+		return []string{"a", "b"}, nil
+	})
+	tracer := &fakeTracer{}
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {})
+
+	d := Depaginate[string](ctx, data, handler, WithTracer(tracer))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	require.Len(t, tracer.spans, 3)
+	for _, span := range tracer.spans {
+		assert.Equal(t, SpanName, span.name)
+		assert.True(t, span.ended)
+		if span.pageIndex == 2 {
+			assert.Same(t, assert.AnError, span.err)
+		} else {
+			assert.NoError(t, span.err)
+		}
+	}
+}
+
+func TestByteBudget(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 2,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithByteBudget(5, func(items []string) int64 {
+		return int64(len(items))
+	}))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1}, data.fetched)
+	assert.Equal(t, int64(6), d.spent)
+}
+
+func TestByteBudgetMismatch(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data:    []string{"0", "1", "2"},
+		perPage: 2,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithByteBudget(5, func(items []int) int64 {
+		return int64(len(items))
+	}))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	assert.ErrorContains(t, err, "WithByteBudget")
+	assert.Empty(t, result.Items)
+	assert.Nil(t, data.fetched)
+}
+
+func TestBootstrapUpdateFlood(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			for i := 0; i < 100; i++ {
+				depag.Update(TotalItems(3), PerPage(2))
+			}
+			depag.Request(1, nil)
+			return []string{"0", "1"}, nil
+		}
+		return []string{"2"}, nil
+	})
+	result := &ListHandler[string]{}
+	finished := make(chan error)
+
+	go func() {
+		d := Depaginate[string](ctx, data, result, Capacity(1))
+		finished <- d.Wait()
+	}()
+
+	select {
+	case err := <-finished:
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0", "1", "2"}, result.Items)
+	case <-time.After(10 * time.Second):
+		assert.Fail(t, "depagination deadlocked")
+	}
+}
+
+func TestReadySignal(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	data := PageGetterFunc[string](func(_ context.Context, _ State, _ PageRequest) ([]string, error) {
+		<-release
+		return []string{"0"}, nil
+	})
+	ready := make(chan struct{})
+	completed := false
+
+	d := Depaginate[string](ctx, data, HandlerFunc[string](func(context.Context, int, string) {}), WithReadySignal(ready), WithDoner(DonerFunc(func(context.Context, int, int, int) {
+		completed = true
+	})))
+
+	select {
+	case <-ready:
+	default:
+		assert.Fail(t, "ready signal not sent")
+	}
+	assert.False(t, completed)
+	close(release)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.True(t, completed)
+}
+
+func TestPostPageHook(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(10), PerPage(2))
+			for i := 1; i < 10; i++ {
+				depag.Request(i, nil)
+			}
+		}
+		items := []string{
+			fmt.Sprintf("%d", req.PageIndex*2),
+			fmt.Sprintf("%d", req.PageIndex*2+1),
+		}
+		if req.PageIndex == 2 {
+			items[1] = "END"
+		}
+		return items, nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithPostPageHook(func(state State, req PageRequest, items []string) {
+		for i, item := range items {
+			if item == "END" {
+				state.Update(TotalPages(req.PageIndex+1), TotalItems(req.PageIndex*2+i+1))
+			}
+		}
+	}))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, d.Result().TotalPages)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "END"}, result.Items)
+}
+
+func TestMetaPagerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := []string{"0", "1", "2", "3", "4", "5", "6"}
+	pager := PageGetterMetaFunc[string](func(_ context.Context, req PageRequest) (Page[string], PageMeta, error) {
+		meta := PageMeta{}
+		if req.PageIndex == 0 {
+			meta.TotalItems = len(data)
+			meta.PerPage = 3
+			meta.AddRequest(1, "one")
+			meta.AddRequest(2, "two")
+		}
+		start := req.PageIndex * 3
+		end := start + 3
+		if end > len(data) {
+			end = len(data)
+		}
+		return Page[string]{Items: data[start:end]}, meta, nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, MetaPager[string](pager), result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data, result.Items)
+	assert.Equal(t, 3, d.Result().TotalPages)
+}
+
+func TestPostPageHookMismatch(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data:    []string{"0", "1", "2"},
+		perPage: 2,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithPostPageHook(func(state State, req PageRequest, items []int) {}))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	assert.ErrorContains(t, err, "WithPostPageHook")
+	assert.Empty(t, result.Items)
+	assert.Nil(t, data.fetched)
+}
+
+func TestTrustInference(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, TotalItems(5), WithTrustInference(true))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+}
+
+func TestDistrustInference(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, TotalItems(5), WithTrustInference(false))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data[:5], result.Items)
+}
+
+func TestHandleConcurrency(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("handle-concurrency-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data:        make([]string, 61),
+				perPage:     2,
+				reportPages: true,
+			}
+			for j := range data.data {
+				data.data[j] = fmt.Sprintf("%d", j)
+			}
+			result := &ListHandler[string]{}
+			var active, peak atomic.Int32
+			handler := HandlerFunc[string](func(ctx context.Context, idx int, item string) {
+				n := active.Add(1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				result.Handle(ctx, idx, item)
+				active.Add(-1)
+			})
+			result.Start(ctx, 0, 0, 0)
+
+			d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithHandleConcurrency(2))
+			err := d.Wait()
+			result.Done(ctx, d.totalItems, d.totalPages, d.perPage)
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.LessOrEqual(t, peak.Load(), int32(2))
+			assert.Equal(t, 61, d.Result().ItemsHandled)
+		})
+	}
+}
+
+func TestHandleConcurrencyBackpressure(t *testing.T) {
+	ctx := context.Background()
+	const total = 100
+	var outstanding, peak atomic.Int32
+	pager := PageGetterFunc[int](func(_ context.Context, depag State, req PageRequest) ([]int, error) {
+		n := outstanding.Add(1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(total), PerPage(1))
+			depag.RequestRange(1, total, nil)
+		}
+		return []int{req.PageIndex}, nil
+	})
+	var count atomic.Int32
+	handler := HandlerFunc[int](func(_ context.Context, _ int, _ int) {
+		time.Sleep(100 * time.Microsecond)
+		count.Add(1)
+		outstanding.Add(-1)
+	})
+
+	d := Depaginate[int](ctx, pager, handler, MaxConcurrency(2), WithHandleConcurrency(1))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(total), count.Load())
+
+	// Pages being retrieved or awaiting room, plus the page with
+	// room reserved or queued, plus the page being handled
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+}
+
+func TestHandleConcurrencyPageOrder(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data:        make([]string, 60),
+		perPage:     5,
+		reportPages: true,
+	}
+	for j := range data.data {
+		data.data[j] = fmt.Sprintf("%d", j)
+	}
+	var mu sync.Mutex
+	pages := map[int][]int{}
+	handler := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+		mu.Lock()
+		defer mu.Unlock()
+		pages[idx/5] = append(pages[idx/5], idx)
+	})
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithHandleConcurrency(3))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Len(t, pages, 12)
+	for page, handled := range pages {
+		assert.Equal(t, []int{page * 5, page*5 + 1, page*5 + 2, page*5 + 3, page*5 + 4}, handled)
+	}
+}
+
+// fakeTimer is a fake implementation of timer, allowing tests to
+// control when the timer fires.
+type fakeTimer struct {
+	sync.Mutex
+
+	d       time.Duration // Most recent duration
+	f       func()        // Function to call when fired
+	resets  int           // Number of times the timer was reset
+	stopped bool          // Whether the timer was stopped
+}
+
+func useFakeTimer(t *testing.T) *fakeTimer {
+	ft := &fakeTimer{}
+	orig := afterFunc
+	afterFunc = func(d time.Duration, f func()) timer {
+		ft.Lock()
+		defer ft.Unlock()
+		ft.d = d
+		ft.f = f
+		return ft
+	}
+	t.Cleanup(func() {
+		afterFunc = orig
+	})
+
+	return ft
+}
+
+func (ft *fakeTimer) Reset(d time.Duration) bool {
+	ft.Lock()
+	defer ft.Unlock()
+	ft.d = d
+	ft.resets++
+	return true
+}
+
+func (ft *fakeTimer) Stop() bool {
+	ft.Lock()
+	defer ft.Unlock()
+	ft.stopped = true
+	return true
+}
+
+func (ft *fakeTimer) Fire() {
+	ft.Lock()
+	f := ft.f
+	ft.Unlock()
+	f()
+}
+
+func TestActivityTimeoutReset(t *testing.T) {
+	ft := useFakeTimer(t)
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithActivityTimeout(time.Minute))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Equal(t, time.Minute, ft.d)
+	assert.Equal(t, 4, ft.resets)
+	assert.True(t, ft.stopped)
+}
+
+func TestActivityTimeoutStalled(t *testing.T) {
+	ft := useFakeTimer(t)
+	ctx := context.Background()
+	stalled := make(chan struct{})
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(PerPage(2))
+			depag.Request(1, nil)
+			return []string{"a", "b"}, nil
+		}
+		close(stalled)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithActivityTimeout(time.Minute), WithPartialResults())
+	<-stalled
+	ft.Fire()
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrStalled)
+	assert.Equal(t, []string{"a", "b"}, result.Items)
+	assert.True(t, ft.stopped)
+	assert.ErrorIs(t, context.Cause(d.Context()), ErrStalled)
+}
+
+func TestActivityTimeoutContext(t *testing.T) {
+	useFakeTimer(t)
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithActivityTimeout(time.Minute))
+	runCtx := d.Context()
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.NotSame(t, ctx, runCtx)
+	actual, ok := runCtx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, deadline, actual)
+}
+
+func TestPageStream(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("page-stream-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage:     3,
+				reportPages: true,
+			}
+
+			d := Depaginate[string](ctx, data, nil, WithAutoStrategy(), WithPageStream())
+			errs := make(chan error)
+			go func() {
+				errs <- d.Wait()
+			}()
+			pages := map[int][]string{}
+			for page := range d.Pages() {
+				pages[page.Request.PageIndex] = page.Items
+			}
+
+			assert.NoError(t, <-errs)
+			var items []string
+			for j := 0; j < len(pages); j++ {
+				items = append(items, pages[j]...)
+			}
+			assert.Equal(t, data.data, items)
+			assert.Equal(t, 11, d.Result().ItemsHandled)
+		})
+	}
+}
+
+func TestConflictingOptions(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, FailFast(), WithMaxErrorsBeforeAbort(3))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	assert.Empty(t, result.Items)
+	assert.Nil(t, data.fetched)
+}
+
+func TestSchedulerHandleConcurrency(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	var active, peak atomic.Int32
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {
+		n := active.Add(1)
+		defer active.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+	})
+	var tasks atomic.Int32
+	scheduler := SchedulerFunc(func(task func()) {
+		tasks.Add(1)
+		go task()
+	})
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithScheduler(scheduler), WithHandleConcurrency(2))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, d.Result().ItemsHandled)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Greater(t, int(tasks.Load()), len(data.fetched))
+}
+
+func TestSchedulerActivityTimeout(t *testing.T) {
+	ft := useFakeTimer(t)
+	ctx := context.Background()
+	stalled := make(chan struct{})
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(PerPage(2))
+			depag.Request(1, nil)
+			return []string{"a", "b"}, nil
+		}
+		close(stalled)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	var tasks atomic.Int32
+	scheduler := SchedulerFunc(func(task func()) {
+		tasks.Add(1)
+		go task()
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithScheduler(scheduler), WithActivityTimeout(time.Minute), WithPartialResults())
+	<-stalled
+	before := tasks.Load()
+	ft.Fire()
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrStalled)
+	assert.Equal(t, []string{"a", "b"}, result.Items)
+	assert.Greater(t, tasks.Load(), before)
+}
+
+func TestRequestDecorator(t *testing.T) {
+	ctx := context.Background()
+	var token atomic.Int32
+	var mu sync.Mutex
+	seen := map[int]any{}
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		mu.Lock()
+		seen[req.PageIndex] = req.Request
+		mu.Unlock()
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(3), PerPage(1))
+			depag.Request(1, nil)
+			depag.Request(2, nil)
+		}
+		if req.PageIndex == 2 {
+			return nil, assert.AnError
+		}
+		return []string{fmt.Sprintf("%d", req.PageIndex)}, nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithRequestDecorator(func(req PageRequest) PageRequest {
+		req.Request = fmt.Sprintf("token-%d", token.Add(1))
+		return req
+	}))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, int32(3), token.Load())
+	assert.Len(t, seen, 3)
+	tokens := map[any]bool{}
+	for _, tok := range seen {
+		tokens[tok] = true
+	}
+	assert.Equal(t, map[any]bool{"token-1": true, "token-2": true, "token-3": true}, tokens)
+	assert.Equal(t, "token-1", seen[0])
+	var pe PageError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, PageRequest{PageIndex: 2}, pe.PageRequest)
+}
+
+func TestHealthGate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+	var healthy atomic.Bool
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithHealthGate(healthy.Load))
+	time.Sleep(5 * HealthPollMin)
+	data.Lock()
+	paused := len(data.fetched)
+	data.Unlock()
+	healthy.Store(true)
+	err := d.Wait()
+
+	assert.Equal(t, 0, paused)
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+}
+
+func TestHealthGateCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithHealthGate(func() bool {
+		return false
+	}))
+	cancel()
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Items)
+	assert.Nil(t, data.fetched)
+}
+
+func TestUpdateRecorder(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, _ PageRequest) ([]string, error) {
+		depag.Update(TotalItems(2))
+		return []string{"a", "b"}, nil
+	})
+	result := &ListHandler[string]{}
+	var recorded []string
+
+	d := Depaginate[string](ctx, pager, result, WithUpdateRecorder(func(updateType string) {
+		recorded = append(recorded, updateType)
+	}))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result.Items)
+	assert.Len(t, recorded, 6)
+	assert.Equal(t, []string{
+		"cancelerFor",
+		"bundle",
+		"withdrawCanceler",
+		"itemHandler",
+	}, recorded[:4])
+	// The page retrieval and the handling of its items finish
+	// concurrently
+	assert.ElementsMatch(t, []string{
+		"pageDone",
+		"handleDone",
+	}, recorded[4:])
+}
+
+// sparsePager is a pager with an empty page in the middle, which
+// requests each following page itself.
+var sparsePager = PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+	pages := [][]string{{"a", "b"}, {}, {"c", "d"}, {"e"}}
+	if req.PageIndex == 0 {
+		depag.Update(PerPage(2))
+	}
+	if req.PageIndex+1 < len(pages) {
+		depag.Request(req.PageIndex+1, nil)
+	}
+	return pages[req.PageIndex], nil
+})
+
+func TestAllowEmptyPages(t *testing.T) {
+	ctx := context.Background()
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, sparsePager, result, WithAllowEmptyPages())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "", "", "c", "d", "e"}, result.Items)
+	assert.Equal(t, 4, d.Result().TotalPages)
+	assert.Equal(t, []int{2, 3}, result.Missing())
+}
+
+func TestEmptyPageEndsIteration(t *testing.T) {
+	ctx := context.Background()
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, sparsePager, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result.Items)
+	assert.Equal(t, 2, d.Result().TotalPages)
+}
+
+func TestRequestRange(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	var mu sync.Mutex
+	reqs := map[int]any{}
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		mu.Lock()
+		reqs[req.PageIndex] = req.Request
+		mu.Unlock()
+		page, err := data.GetPage(ctx, depag, req)
+		if req.PageIndex == 0 {
+			// Page 0 is a duplicate, and pages 4 through 9 are out
+			// of bounds, as data reports 4 pages
+			depag.RequestRange(0, 10, func(idx int) any {
+				return fmt.Sprintf("page-%d", idx)
+			})
+		}
+		return page, err
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithRequest("first"))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}, data.fetched)
+	assert.Equal(t, map[int]any{0: "first", 1: "page-1", 2: "page-2", 3: "page-3"}, reqs)
+}
+
+// committingHandler is a [ListHandler] that records whether its
+// results were committed or rolled back.
+type committingHandler struct {
+	ListHandler[string]
+
+	committed  bool
+	rolledBack bool
+}
+
+func (ch *committingHandler) Commit(_ context.Context) error {
+	ch.committed = true
+	return nil
+}
+
+func (ch *committingHandler) Rollback(_ context.Context) {
+	ch.rolledBack = true
+}
+
+func TestCommitterCommit(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &committingHandler{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.True(t, result.committed)
+	assert.False(t, result.rolledBack)
+}
+
+func TestCommitterRollback(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(PerPage(2))
+			depag.Request(1, nil)
+			return []string{"a", "b"}, nil
+		}
+		return nil, assert.AnError
+	})
+	result := &committingHandler{}
+
+	d := Depaginate[string](ctx, pager, result)
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.False(t, result.committed)
+	assert.True(t, result.rolledBack)
+}
+
+func TestPerPageOnlyUpdate(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, _ PageRequest) ([]string, error) {
+		depag.Update(PerPage(3))
+		depag.Update(PerPage(3))
+		return []string{"a", "b", "c"}, nil
+	})
+	result := &ListHandler[string]{}
+	type call struct {
+		totalItems, totalPages, perPage int
+	}
+	var calls []call
+	updater := UpdaterFunc(func(ctx context.Context, totalItems, totalPages, perPage int) {
+		calls = append(calls, call{totalItems, totalPages, perPage})
+		result.Update(ctx, totalItems, totalPages, perPage)
+	})
+
+	d := Depaginate[string](ctx, pager, result, TotalItems(3), TotalPages(1), WithUpdater(updater))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, result.Items)
+	assert.Equal(t, []call{{3, 1, 3}}, calls)
+}
+
+func TestCompactPageMap(t *testing.T) {
+	ctx := context.Background()
+	const total = 3 * CompactWindow
+	var mu sync.Mutex
+	fetched := map[int]int{}
+	pager := PageGetterFunc[int](func(_ context.Context, depag State, req PageRequest) ([]int, error) {
+		mu.Lock()
+		fetched[req.PageIndex]++
+		mu.Unlock()
+		if req.PageIndex == 0 {
+			depag.Update(PerPage(1))
+		}
+
+		// Duplicate requests within the window are still ignored
+		depag.Request(req.PageIndex, nil)
+		if req.PageIndex > 0 {
+			depag.Request(req.PageIndex-1, nil)
+		}
+		if req.PageIndex+1 < total {
+			depag.Request(req.PageIndex+1, nil)
+		}
+		return []int{req.PageIndex}, nil
+	})
+	var count atomic.Int32
+	handler := HandlerFunc[int](func(_ context.Context, _ int, _ int) {
+		count.Add(1)
+	})
+
+	d := Depaginate[int](ctx, pager, handler, WithCompactPageMap())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(total), count.Load())
+	assert.Len(t, fetched, total)
+	for idx, n := range fetched {
+		assert.Equal(t, 1, n, "page %d fetched %d times", idx, n)
+	}
+	assert.LessOrEqual(t, len(d.pages.bits), CompactWindow/bits.UintSize+1)
+}
+
+func TestRequestsDuringGetPage(t *testing.T) {
+	ctx := context.Background()
+	started := make([]chan struct{}, 4)
+	for i := range started {
+		started[i] = make(chan struct{})
+	}
+	pager := PageGetterFunc[int](func(ctx context.Context, depag State, req PageRequest) ([]int, error) {
+		close(started[req.PageIndex])
+		if req.PageIndex == 0 {
+			depag.Update(PerPage(1))
+		}
+		if req.PageIndex+1 == len(started) {
+			depag.MarkLast(req.PageIndex)
+			return []int{req.PageIndex}, nil
+		}
+
+		// The next page must be retrieved while this one waits
+		depag.Request(req.PageIndex+1, nil)
+		select {
+		case <-started[req.PageIndex+1]:
+		case <-time.After(5 * time.Second):
+			return nil, assert.AnError
+		}
+		return []int{req.PageIndex}, nil
+	})
+	result := &ListHandler[int]{}
+
+	d := Depaginate[int](ctx, pager, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, result.Items)
+}
+
+func TestBatchedRequests(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data:      make([]string, 41),
+		perPage:   2,
+		pageAhead: 25,
+	}
+	for i := range data.data {
+		data.data[i] = fmt.Sprintf("%d", i)
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithBatchedRequests())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+}
+
+func TestCompactCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	const total = 3 * CompactWindow
+	var mu sync.Mutex
+	fetched := map[int]int{}
+	pager := PageGetterFunc[int](func(_ context.Context, depag State, req PageRequest) ([]int, error) {
+		mu.Lock()
+		fetched[req.PageIndex]++
+		mu.Unlock()
+		if req.PageIndex == 0 {
+			depag.Update(PerPage(1))
+		}
+		if req.PageIndex+1 < total {
+			depag.Request(req.PageIndex+1, nil)
+		} else {
+			depag.MarkLast(req.PageIndex)
+		}
+		return []int{req.PageIndex}, nil
+	})
+	var count atomic.Int32
+	handler := HandlerFunc[int](func(_ context.Context, _ int, _ int) {
+		count.Add(1)
+	})
+
+	d := Depaginate[int](ctx, pager, handler, WithCompactPageMap(), WithCheckpoint(nil))
+	err := d.Wait()
+	checkpoint := d.Checkpoint()
+
+	require.NoError(t, err)
+	require.Equal(t, int32(total), count.Load())
+	assert.LessOrEqual(t, len(d.completed.bits), CompactWindow/bits.UintSize+2)
+	fetched = map[int]int{}
+	count.Store(0)
+
+	d = Depaginate[int](ctx, pager, handler, WithCheckpoint(checkpoint))
+	err = d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 1}, fetched)
+	assert.Equal(t, int32(0), count.Load())
+}
+
+func TestCheckpointNotRecorded(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data:    []string{"0", "1", "2"},
+		perPage: 2,
+	}
+
+	d := Depaginate[string](ctx, data, &ListHandler[string]{})
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Nil(t, d.completed)
+	assert.Nil(t, d.Checkpoint())
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("max-concurrency-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data:        make([]string, 41),
+				perPage:     2,
+				reportPages: true,
+			}
+			for j := range data.data {
+				data.data[j] = fmt.Sprintf("%d", j)
+			}
+			var active, peak atomic.Int32
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					old := peak.Load()
+					if n <= old || peak.CompareAndSwap(old, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return data.GetPage(ctx, depag, req)
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, pager, result, WithAutoStrategy(), MaxConcurrency(3))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.LessOrEqual(t, peak.Load(), int32(3))
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+	limiter := rate.NewLimiter(rate.Every(10*time.Millisecond), 1)
+
+	start := time.Now()
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithRateLimiter(limiter), MaxConcurrency(2))
+	err := d.Wait()
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.GreaterOrEqual(t, elapsed, 30*time.Millisecond)
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+	limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+	limiter.Allow()
+
+	d := Depaginate[string](ctx, data, result, WithRateLimiter(limiter))
+	cancel()
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Nil(t, data.fetched)
+}
+
+func TestResultCallback(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("result-callback-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage:     3,
+				reportPages: true,
+			}
+			var calls [][]string
+
+			d := Depaginate[string](ctx, data, nil, WithAutoStrategy(), WithResultCallback(func(items []string) {
+				calls = append(calls, items)
+			}))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, [][]string{data.data}, calls)
+		})
+	}
+}
+
+func TestResultCallbackFailedPages(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalItems(100), PerPage(10))
+			depag.RequestRange(1, 10, nil)
+		}
+		if req.PageIndex >= 5 {
+			return nil, assert.AnError
+		}
+		items := make([]string, 10)
+		for i := range items {
+			items[i] = fmt.Sprintf("%d", req.PageIndex*10+i)
+		}
+		return items, nil
+	})
+	var calls [][]string
+
+	d := Depaginate[string](ctx, pager, nil, WithResultCallback(func(items []string) {
+		calls = append(calls, items)
+	}))
+	var err error
+	assert.NotPanics(t, func() {
+		err = d.Wait()
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Len(t, calls, 1)
+	assert.Len(t, calls[0], 100)
+	assert.Equal(t, "0", calls[0][0])
+	assert.Equal(t, "49", calls[0][49])
+	assert.Equal(t, "", calls[0][50])
+}
+
+func TestLimit(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("limit-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage:     3,
+				reportPages: true,
+			}
+			result := &ListHandler[string]{}
+			var count atomic.Int32
+			handler := HandlerFunc[string](func(ctx context.Context, idx int, item string) {
+				count.Add(1)
+				result.Handle(ctx, idx, item)
+			})
+			result.Start(ctx, 0, 0, 0)
+
+			d := Depaginate[string](ctx, data, handler, PerPage(3), WithLimit(4), WithDoner(result))
+			d.RequestRange(1, 4, nil)
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, int32(4), count.Load())
+			assert.Equal(t, []string{"0", "1", "2", "3"}, result.Items)
+			assert.Equal(t, 4, d.Result().ItemsHandled)
+		})
+	}
+}
+
+func TestMaxItems(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), MaxItems(4))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, result.Items)
+	assert.Equal(t, 4, d.Result().ItemsHandled)
+	assert.Equal(t, 4, d.Config().Limit)
+}
+
+func TestLimitBeyondData(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithLimit(100))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+}
+
+func TestLimitAuto(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), WithLimit(5))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, result.Items)
+	assert.Equal(t, map[int]int{0: 1, 1: 1}, data.fetched)
+}
+
+func TestLimitCancels(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("limit-cancels-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			var canceled atomic.Int32
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				switch req.PageIndex {
+				case 0:
+					// Request the pages before the number of items
+					// per page is known
+					depag.RequestRange(1, 10, nil)
+					depag.Update(TotalPages(10), PerPage(3))
+					return []string{"0", "1", "2"}, nil
+				case 1:
+					return []string{"3", "4", "5"}, nil
+				}
+
+				// Block until canceled
+				<-ctx.Done()
+				canceled.Add(1)
+				return nil, ctx.Err()
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, pager, result, WithLimit(5))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"0", "1", "2", "3", "4"}, result.Items)
+			assert.Equal(t, int32(8), canceled.Load())
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("retry-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage: 3,
+			}
+			var mu sync.Mutex
+			failures := map[int]int{}
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				mu.Lock()
+				failed := failures[req.PageIndex]
+				failures[req.PageIndex]++
+				mu.Unlock()
+				if failed < 2 {
+					return nil, assert.AnError
+				}
+				return data.GetPage(ctx, depag, req)
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, pager, result, WithAutoStrategy(), WithRetry(3, func(attempt int) time.Duration {
+				return time.Millisecond
+			}))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.Equal(t, map[int]int{0: 3, 1: 3, 2: 3, 3: 3}, failures)
+		})
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		calls++
+		return nil, assert.AnError
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithRetry(3, nil))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 3, calls)
+	assert.Len(t, d.Result().Errors, 1)
+}
+
+func TestRetryBudget(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	calls := map[int]int{}
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[req.PageIndex]++
+		if req.PageIndex == 0 {
+			depag.Request(1, nil)
+		}
+		return nil, assert.AnError
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, MaxConcurrency(1), WithRetry(4, nil), WithRetryBudget(4))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, map[int]int{0: 4, 1: 2}, calls)
+	assert.Len(t, d.Result().Errors, 2)
+}
+
+func TestConcurrencySampler(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     2,
+		reportPages: true,
+	}
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		time.Sleep(20 * time.Millisecond)
+		return data.GetPage(ctx, depag, req)
+	})
+	var mu sync.Mutex
+	var samples []int
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithAutoStrategy(), MaxConcurrency(3), WithConcurrencySampler(time.Millisecond, func(active int) {
+		mu.Lock()
+		defer mu.Unlock()
+		samples = append(samples, active)
+	}))
+	err := d.Wait()
+	mu.Lock()
+	count := len(samples)
+	mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	require.NotEmpty(t, samples)
+	assert.Len(t, samples, count)
+	peak := 0
+	for _, active := range samples {
+		assert.LessOrEqual(t, active, 3)
+		if active > peak {
+			peak = active
+		}
+	}
+	assert.Equal(t, 3, peak)
+}
+
+func TestFailFastCancels(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("failfast-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			var canceled atomic.Int32
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				if req.PageIndex == 0 {
+					depag.Update(TotalPages(5), PerPage(1))
+					depag.RequestRange(1, 5, nil)
+					return []string{"0"}, nil
+				}
+				if req.PageIndex == 2 {
+					return nil, assert.AnError
+				}
+
+				// Block until canceled
+				<-ctx.Done()
+				canceled.Add(1)
+				return nil, ctx.Err()
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, pager, result, FailFast())
+			err := d.Wait()
+
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.Len(t, d.Result().Errors, 1)
+			assert.Equal(t, int32(3), canceled.Load())
+		})
+	}
+}
+
+func TestMaxErrorsBeforeAbort(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("max-errors-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			var canceled atomic.Int32
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				if req.PageIndex == 0 {
+					depag.Update(TotalPages(8), PerPage(1))
+					depag.RequestRange(1, 8, nil)
+					return []string{"0"}, nil
+				}
+				if req.PageIndex <= 3 {
+					return nil, assert.AnError
+				}
+
+				// Block until canceled
+				<-ctx.Done()
+				canceled.Add(1)
+				return nil, ctx.Err()
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, pager, result, WithMaxErrorsBeforeAbort(2))
+			err := d.Wait()
+
+			assert.ErrorIs(t, err, assert.AnError)
+			assert.Len(t, d.Result().Errors, 3)
+			assert.Equal(t, int32(4), canceled.Load())
+		})
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(3), PerPage(1))
+			depag.RequestRange(1, 3, nil)
+			return []string{"0"}, nil
+		}
+		return nil, assert.AnError
+	})
+	var reported []int
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithErrorHandler(func(ctx context.Context, req PageRequest, err error) {
+		assert.ErrorIs(t, err, assert.AnError)
+		reported = append(reported, req.PageIndex)
+	}, false))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ElementsMatch(t, []int{1, 2}, reported)
+}
+
+func TestHandleGate(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("gate-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage:     3,
+				reportPages: true,
+			}
+
+			// The logical boundary is only discovered from page 0
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				if req.PageIndex == 0 {
+					depag.Update(TotalItems(7))
+				}
+				return data.GetPage(ctx, depag, req)
+			})
+			var mu sync.Mutex
+			var handled []int
+			handler := HandlerFunc[string](func(ctx context.Context, idx int, item string) {
+				mu.Lock()
+				defer mu.Unlock()
+				handled = append(handled, idx)
+			})
+
+			d := Depaginate[string](ctx, pager, handler, WithAutoStrategy(), WithHandleGate(func(idx int, totals Totals) bool {
+				return totals.TotalItems == 0 || idx < totals.TotalItems
+			}))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6}, handled)
+			assert.Equal(t, 4, d.Result().PagesFetched)
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("cancel-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			var canceled atomic.Int32
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				if req.PageIndex == 0 {
+					depag.Update(TotalPages(4), PerPage(1))
+					depag.RequestRange(1, 4, nil)
+					return []string{"0"}, nil
+				}
+
+				// Block until canceled
+				<-ctx.Done()
+				canceled.Add(1)
+				return nil, ctx.Err()
+			})
+			handled := make(chan struct{})
+			handler := &committingHandler{}
+			items := HandlerFunc[string](func(ctx context.Context, idx int, item string) {
+				close(handled)
+			})
+
+			d := Depaginate[string](ctx, pager, items, WithCommitter(handler))
+			<-handled
+			d.Cancel()
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, int32(3), canceled.Load())
+			assert.NoError(t, ctx.Err())
+			assert.False(t, handler.committed)
+			assert.True(t, handler.rolledBack)
+		})
+	}
+}
+
+func TestErrWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(3), PerPage(1))
+			depag.RequestRange(1, 3, nil)
+			return []string{"0"}, nil
+		}
+		if req.PageIndex == 1 {
+			return nil, assert.AnError
+		}
+		<-release
+		return []string{"2"}, nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result)
+	assert.Eventually(t, func() bool {
+		return d.Err() != nil
+	}, time.Second, time.Millisecond)
+	close(release)
+	err := d.Wait()
+
+	assert.ErrorIs(t, d.Err(), assert.AnError)
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+type requestingHandler struct {
+	ListHandler[string]
+}
+
+func (rh *requestingHandler) Handle(ctx context.Context, idx int, item string, state State) {
+	// The item "more" indicates that the next page is needed
+	if item == "more" {
+		state.Request(idx/2+1, nil)
+	}
+	rh.ListHandler.Handle(ctx, idx, item)
+}
+
+func TestStatefulHandler(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("stateful-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "more", "2", "more", "4",
+				},
+				perPage: 2,
+			}
+			result := &requestingHandler{}
+
+			d := Depaginate[string](ctx, data, Stateful[string](result))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1}, data.fetched)
+		})
+	}
+}
+
+func TestWaitStats(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+	stats, err := d.WaitStats()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Equal(t, 11, stats.TotalItems)
+	assert.Equal(t, 4, stats.TotalPages)
+	assert.Equal(t, 3, stats.PerPage)
+	assert.Equal(t, 4, stats.PagesFetched)
+	assert.Equal(t, 11, stats.ItemsHandled)
+	assert.Empty(t, stats.Errors)
+}
+
+func TestSequentialRetry(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("sequential-retry-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data: []string{
+					"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+				},
+				perPage: 3,
+			}
+
+			// Page 1 fails once; no other page may start until its
+			// retry has concluded
+			var mu sync.Mutex
+			var order []int
+			failed := false
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				mu.Lock()
+				order = append(order, req.PageIndex)
+				fail := req.PageIndex == 1 && !failed
+				failed = failed || fail
+				mu.Unlock()
+				if fail {
+					return nil, assert.AnError
+				}
+				return data.GetPage(ctx, depag, req)
+			})
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, pager, result, WithAutoStrategy(), MaxConcurrency(1), WithRetry(2, func(attempt int) time.Duration {
+				return 5 * time.Millisecond
+			}))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+			assert.Equal(t, []int{0, 1, 1, 2, 3}, order)
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), TotalItems(11), PerPage(3), Capacity(20), MaxConcurrency(2), WithRetry(3, nil))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		TotalItems:     11,
+		PerPage:        3,
+		Capacity:       20,
+		MaxConcurrency: 2,
+		RetryAttempts:  3,
+		AutoStrategy:   true,
+	}, d.Config())
+}
+
+// panickingUpdater is a [Handler] whose [Updater.Update] panics.
+type panickingUpdater struct {
+	ListHandler[string]
+}
+
+// Update panics.
+func (pu *panickingUpdater) Update(_ context.Context, _, _, _ int) {
+	panic("oops")
+}
+
+func TestDaemonPanic(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &panickingUpdater{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrPanic)
+	assert.ErrorContains(t, err, "oops")
+}
+
+func TestIntraPageOrder(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	var mu sync.Mutex
+	pages := map[int][]int{}
+	handler := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+		mu.Lock()
+		defer mu.Unlock()
+		pages[idx/3] = append(pages[idx/3], idx)
+	})
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithIntraPageOrder(func(pageLen int) []int {
+		order := make([]int, pageLen)
+		for i := range order {
+			order[i] = pageLen - 1 - i
+		}
+		return order
+	}))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int][]int{
+		0: {2, 1, 0},
+		1: {5, 4, 3},
+		2: {8, 7, 6},
+		3: {10, 9},
+	}, pages)
+}
+
+func TestReverseFetchOrder(t *testing.T) {
+	ctx := context.Background()
+	data := []string{
+		"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+	}
+	var mu sync.Mutex
+	var fetched []int
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		mu.Lock()
+		fetched = append(fetched, req.PageIndex)
+		mu.Unlock()
+
+		// Fetch the last page first, then work backwards
+		switch req.PageIndex {
+		case 0:
+			depag.Update(TotalPages(4), PerPage(3))
+			depag.Request(3, nil)
+		case 1:
+		default:
+			depag.Request(req.PageIndex-1, nil)
+		}
+
+		start := req.PageIndex * 3
+		end := start + 3
+		if end > len(data) {
+			end = len(data)
+		}
+		return data[start:end], nil
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 3, 2, 1}, fetched)
+	assert.Equal(t, data, result.Items)
+}
+
+func TestTotals(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, d.TotalItems())
+	assert.Equal(t, 4, d.TotalPages())
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithDryRun(), TotalItems(11), PerPage(3))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, d.PlannedPages())
+	pager.AssertExpectations(t)
+}
+
+func TestDryRunLimit(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithDryRun(), TotalPages(10), PerPage(3), WithLimit(5))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, d.PlannedPages())
+	pager.AssertExpectations(t)
+}
+
+func TestPerPageConsistent(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	var mu sync.Mutex
+	var seen []int
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		items, err := data.GetPage(ctx, depag, req)
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, depag.PerPage())
+		return items, err
+	})
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Equal(t, []int{3, 3, 3, 3}, seen)
+	assert.Equal(t, 3, d.PerPage())
+}
+
+func TestInconsistentHints(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	handler := &mockHandlerFull{}
+	handler.On("Start", ctx, 12, 4, 3)
+	handler.On("Handle", ctx, mock.Anything, mock.Anything)
+	handler.On("Update", ctx, mock.Anything, mock.Anything, mock.Anything)
+	handler.On("Done", ctx, 11, 4, 3)
+	var logged []string
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), TotalItems(100), TotalPages(4), PerPage(3), WithLogger(func(msg string) {
+		logged = append(logged, msg)
+	}))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Len(t, logged, 1)
+	handler.AssertExpectations(t)
+}
+
+func TestLifecycleLogging(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.Update(TotalItems(6), TotalPages(3), PerPage(2))
+		if req.PageIndex == 0 {
+			depag.RequestRange(1, 3, nil)
+		}
+		if req.PageIndex == 2 {
+			return nil, assert.AnError
+		}
+		return []string{"a", "b"}, nil
+	})
+	logger := &fakeLogger{}
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {})
+
+	d := Depaginate[string](ctx, data, handler, WithLifecycleLogger(logger))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ElementsMatch(t, []string{
+		"requesting page 0",
+		"requesting page 1",
+		"requesting page 2",
+		"retrieved page 0: 2 items",
+		"retrieved page 1: 2 items",
+		"iteration complete: 6 items, 3 pages, 2 per page, 1 errors",
+	}, logger.debug)
+	assert.Equal(t, "iteration complete: 6 items, 3 pages, 2 per page, 1 errors", logger.debug[len(logger.debug)-1])
+	assert.Equal(t, []string{"page 2: " + assert.AnError.Error()}, logger.warn)
+}
+
+func TestMetricsCollection(t *testing.T) {
+	ctx := context.Background()
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.Update(TotalItems(6), TotalPages(3), PerPage(2))
+		if req.PageIndex == 0 {
+			depag.RequestRange(1, 3, nil)
+		}
+		if req.PageIndex == 2 {
+			return nil, assert.AnError
+		}
+		return []string{"a", "b"}, nil
+	})
+	metrics := &fakeMetrics{}
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {})
+
+	d := Depaginate[string](ctx, data, handler, WithMetrics(metrics))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ElementsMatch(t, []error{nil, nil, assert.AnError}, metrics.fetched)
+	assert.Equal(t, 4, metrics.handled)
+	assert.Equal(t, 1, metrics.completed)
+	assert.Equal(t, 6, metrics.totalItems)
+	assert.Equal(t, 3, metrics.totalPages)
+}
+
+func TestInconsistentHintsPreallocation(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), TotalItems(1000000), TotalPages(4), PerPage(3))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Less(t, cap(result.Items), 1000000)
+}
+
+func TestSmallCapacityPageAhead(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("capacity-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := PagedData{
+				perPage:   3,
+				pageAhead: 200,
+			}
+			for j := 0; j < 600; j++ {
+				data.data = append(data.data, fmt.Sprint(j))
+			}
+			result := &ListHandler[string]{}
+
+			d := Depaginate[string](ctx, data, result, Capacity(1))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, data.data, result.Items)
+		})
+	}
+}
+
+func TestSmallCapacityHandleConcurrency(t *testing.T) {
+	for i := 0; i < 5*TestCount; i++ {
+		t.Run(fmt.Sprintf("capacity-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := PagedData{
+				perPage:   3,
+				pageAhead: 200,
+			}
+			for j := 0; j < 600; j++ {
+				data.data = append(data.data, fmt.Sprint(j))
+			}
+			var handled atomic.Int32
+			handler := Stateful[string](StatefulHandlerFunc[string](func(_ context.Context, idx int, _ string, state State) {
+				handled.Add(1)
+				state.Request(idx/3+1, nil)
+			}))
+
+			d := Depaginate[string](ctx, data, handler, Capacity(1), WithHandleConcurrency(1))
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, int32(600), handled.Load())
+		})
+	}
+}
+
+func TestBufferedPages(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	release := make(chan struct{})
+	var handled atomic.Int64
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {
+		<-release
+		handled.Add(1)
+	})
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy())
+
+	assert.Eventually(t, func() bool {
+		return d.BufferedPages() == 4
+	}, time.Second, time.Millisecond)
+	close(release)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), handled.Load())
+	assert.Equal(t, 0, d.BufferedPages())
+}
+
+func TestInOrderHandling(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		t.Run(fmt.Sprintf("in-order-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			data := &SelfPagedData{
+				data:        make([]string, 61),
+				perPage:     2,
+				reportPages: true,
+			}
+			for j := range data.data {
+				data.data[j] = fmt.Sprintf("%d", j)
+			}
+			pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+				// Retrieve the early pages slowest
+				time.Sleep(time.Duration(31-req.PageIndex) * 50 * time.Microsecond)
+				return data.GetPage(ctx, depag, req)
+			})
+			var handled []int
+			handler := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+				handled = append(handled, idx)
+			})
+			expected := make([]int, 61)
+			for j := range expected {
+				expected[j] = j
+			}
+
+			d := Depaginate[string](ctx, pager, handler, WithAutoStrategy(), InOrder())
+			err := d.Wait()
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, handled)
+		})
+	}
+}
+
+func TestInOrderHandlingPageError(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 1 {
+			time.Sleep(time.Millisecond)
+			return nil, assert.AnError
+		}
+		return data.GetPage(ctx, depag, req)
+	})
+	var handled []int
+	handler := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+		handled = append(handled, idx)
+	})
+
+	d := Depaginate[string](ctx, pager, handler, WithAutoStrategy(), InOrder())
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, []int{0, 1, 2, 6, 7, 8, 9, 10}, handled)
+}
+
+func TestProgressBarRendering(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	buf := &bytes.Buffer{}
+	bar := ProgressBar(buf)
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {
+		bar.Add(1)
+	})
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithUpdater(bar), WithDoner(bar))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(buf.String(), "\r[####################] 100% (11/11)\n"))
+}
+
+func TestCheckpointResume(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	failing := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 2 {
+			return nil, assert.AnError
+		}
+		return data.GetPage(ctx, depag, req)
+	})
+	var mu sync.Mutex
+	items := map[int]string{}
+	handled := 0
+	handler := HandlerFunc[string](func(_ context.Context, idx int, item string) {
+		mu.Lock()
+		defer mu.Unlock()
+		items[idx] = item
+		handled++
+	})
+
+	d := Depaginate[string](ctx, failing, handler, WithAutoStrategy(), WithCheckpoint(nil))
+	err := d.Wait()
+	checkpoint := d.Checkpoint()
+
+	require.ErrorIs(t, err, assert.AnError)
+	require.Equal(t, 8, handled)
+	data.fetched = nil
+	handled = 0
+
+	d = Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithCheckpoint(checkpoint))
+	err = d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 1, 2: 1}, data.fetched)
+	assert.Equal(t, 3, handled)
+	assert.Len(t, items, 11)
+	for idx, item := range items {
+		assert.Equal(t, data.data[idx], item)
+	}
+	restored := &pageMap{}
+	require.NoError(t, restored.Restore(d.Checkpoint()))
+	for i := 0; i < 4; i++ {
+		assert.True(t, restored.IsSet(i))
+	}
+}
+
+type checkpointCursor struct {
+	Token string `json:"token"`
+}
+
+func TestCheckpointResumeRequests(t *testing.T) {
+	ctx := context.Background()
+	fail := true
+	var mu sync.Mutex
+	fetched := map[int]int{}
+	data := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		mu.Lock()
+		fetched[req.PageIndex]++
+		mu.Unlock()
+		cursor := checkpointCursor{Token: "start"}
+		if req.PageIndex > 0 {
+			cursor = req.Request.(checkpointCursor)
+		}
+		if cursor.Token != fmt.Sprintf("token-%d", req.PageIndex) && req.PageIndex > 0 {
+			return nil, fmt.Errorf("page %d: bad cursor %q", req.PageIndex, cursor.Token)
+		}
+		if req.PageIndex == 3 && fail {
+			return nil, assert.AnError
+		}
+		if req.PageIndex < 4 {
+			depag.Request(req.PageIndex+1, checkpointCursor{Token: fmt.Sprintf("token-%d", req.PageIndex+1)})
+		} else {
+			depag.MarkLast(req.PageIndex)
+		}
+		return []string{fmt.Sprintf("%d", req.PageIndex)}, nil
+	})
+	codec := WithRequestCodec(func(req any) ([]byte, error) {
+		return json.Marshal(req)
+	}, func(data []byte) (any, error) {
+		var cursor checkpointCursor
+		err := json.Unmarshal(data, &cursor)
+		return cursor, err
+	})
+	items := map[int]string{}
+	handler := HandlerFunc[string](func(_ context.Context, idx int, item string) {
+		mu.Lock()
+		defer mu.Unlock()
+		items[idx] = item
+	})
+
+	d := Depaginate[string](ctx, data, handler, PerPage(1), codec)
+	err := d.Wait()
+	checkpoint := d.Checkpoint()
+
+	require.ErrorIs(t, err, assert.AnError)
+	require.Len(t, items, 3)
+	fail = false
+	fetched = map[int]int{}
+
+	d = Depaginate[string](ctx, data, handler, PerPage(1), codec, WithCheckpoint(checkpoint))
+	err = d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int{0: 1, 3: 1, 4: 1}, fetched)
+	assert.Equal(t, map[int]string{0: "0", 1: "1", 2: "2", 3: "3", 4: "4"}, items)
+	_, requests, err := decodeCheckpoint(d.Checkpoint())
+	require.NoError(t, err)
+	assert.Empty(t, requests)
+}
+
+func TestCheckpointInvalid(t *testing.T) {
+	ctx := context.Background()
+	pager := &mockPageGetter{}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, result, WithCheckpoint([]byte{0}))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+	pager.AssertExpectations(t)
+}
+
+func TestHandleTimeout(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	var handled atomic.Int32
+	handler := HandlerFunc[string](func(ctx context.Context, idx int, _ string) {
+		// Item 4 is too slow, and gives up when its context expires
+		if idx == 4 {
+			<-ctx.Done()
+			return
+		}
+		handled.Add(1)
+	})
+
+	d := Depaginate[string](ctx, data, handler, WithAutoStrategy(), WithHandleTimeout(10*time.Millisecond))
+	err := d.Wait()
+
+	var itemErr ItemError
+	require.ErrorAs(t, err, &itemErr)
+	assert.Equal(t, 4, itemErr.Index)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(10), handled.Load())
+	assert.Empty(t, d.Result().Errors)
+}
+
+func TestHandleTimeoutCancel(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.MarkLast(0)
+		return []string{"0"}, nil
+	})
+	handling := make(chan struct{})
+	canceled := make(chan struct{})
+	var handleErr error
+	handler := HandlerFunc[string](func(ctx context.Context, _ int, _ string) {
+		close(handling)
+		<-canceled
+		handleErr = ctx.Err()
+	})
+
+	d := Depaginate[string](ctx, pager, handler, WithHandleTimeout(time.Minute))
+	<-handling
+	d.Cancel()
+	close(canceled)
+	err := d.Wait()
+
+	// Canceling the iteration does not cancel the handler's context
+	assert.NoError(t, err)
+	assert.NoError(t, handleErr)
+}
+
+func TestMaxPages(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), MaxPages(2))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5"}, result.Items)
+	assert.Equal(t, map[int]int{0: 1, 1: 1}, data.fetched)
+	assert.Equal(t, 2, d.totalPages)
+}
+
+func TestMaxPagesAbsurdTotal(t *testing.T) {
+	ctx := context.Background()
+	pager := PageGetterFunc[string](func(_ context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(1<<40), PerPage(1))
+		}
+		return []string{fmt.Sprintf("%d", req.PageIndex)}, nil
+	})
+	var handled atomic.Int32
+	handler := HandlerFunc[string](func(_ context.Context, _ int, _ string) {
+		handled.Add(1)
+	})
+
+	d := Depaginate[string](ctx, pager, handler, WithAutoStrategy(), MaxPages(3))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), handled.Load())
+	assert.Equal(t, 3, d.totalPages)
+	assert.Equal(t, 3, d.totalItems)
+}
+
+func TestMaxPagesUnknownTotal(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), MaxPages(2))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5"}, result.Items)
+	assert.Equal(t, map[int]int{0: 1, 1: 1}, data.fetched)
+}
+
+func TestMaxPagesSmallerTotal(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), MaxPages(10))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}, data.fetched)
+	assert.Equal(t, 4, d.totalPages)
+}
+
+func TestListHandlerUtilizationRun(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	result := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, data, result, WithAutoStrategy(), TotalItems(20))
+	err := d.Wait()
+	used, capacity := result.Utilization()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, result.Items)
+	assert.Equal(t, 11, used)
+	assert.Equal(t, 20, capacity)
+}
+
+func TestGroup(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+	failing := PageGetterFunc[string](func(_ context.Context, _ State, _ PageRequest) ([]string, error) {
+		return nil, assert.AnError
+	})
+	result1 := &ListHandler[string]{}
+	result2 := &ListHandler[string]{}
+	g := &fakeGroup{}
+
+	Depaginate[string](ctx, data, result1, WithAutoStrategy()).Go(g)
+	Depaginate[string](ctx, failing, result2).Go(g)
+	g.wg.Wait()
+
+	assert.Equal(t, data.data, result1.Items)
+	assert.Empty(t, result2.Items)
+	require.Len(t, g.errs, 2)
+	assert.ErrorIs(t, errors.Join(g.errs...), assert.AnError)
+	assert.Contains(t, g.errs, nil)
+}
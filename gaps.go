@@ -0,0 +1,118 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "context"
+
+// GapDetectorHandler is an implementation of [Handler] that checks
+// the integrity of data whose items have sequential integer IDs.  The
+// IDFunc field must be set to a function that extracts the ID from an
+// item.  Once [GapDetectorHandler.Done] is called (which is called by
+// [Depaginator.Wait]), [GapDetectorHandler.Gaps] reports the IDs
+// missing from the contiguous sequence between the smallest and
+// largest IDs seen.  If the First or Last field is set, the sequence
+// instead begins or ends at that ID, so IDs missing from the start or
+// the end of the data are also reported.  Items may arrive in any
+// order.  No constructor is necessary, as a pointer to a
+// GapDetectorHandler with only IDFunc set is valid.
+type GapDetectorHandler[T any] struct {
+	IDFunc func(item T) int // Function to extract the ID of an item
+	First  *int             // Optional expected first ID
+	Last   *int             // Optional expected last ID
+
+	seen map[int]struct{} // Set of IDs seen
+	min  int              // Smallest ID seen
+	max  int              // Largest ID seen
+
+	ids  chan int      // IDs to process
+	done chan struct{} // Used to signal the daemon has exited
+}
+
+// daemon processes the IDs.  Using a daemon prevents
+// [GapDetectorHandler] from needing to use [sync.Mutex].
+func (gd *GapDetectorHandler[T]) daemon() {
+	defer close(gd.done)
+	for id := range gd.ids {
+		if len(gd.seen) == 0 || id < gd.min {
+			gd.min = id
+		}
+		if len(gd.seen) == 0 || id > gd.max {
+			gd.max = id
+		}
+		gd.seen[id] = struct{}{}
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (gd *GapDetectorHandler[T]) Start(_ context.Context, _, _, _ int) {
+	gd.seen = map[int]struct{}{}
+	gd.min = 0
+	gd.max = 0
+	gd.ids = make(chan int, DefaultCapacity)
+	gd.done = make(chan struct{})
+
+	// Start the daemon
+	go gd.daemon()
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (gd *GapDetectorHandler[T]) Handle(_ context.Context, _ int, item T) {
+	gd.ids <- gd.IDFunc(item)
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (gd *GapDetectorHandler[T]) Done(_ context.Context, _, _, _ int) {
+	close(gd.ids)
+	<-gd.done
+	gd.ids = nil
+	gd.done = nil
+}
+
+// Gaps returns the IDs missing from the contiguous sequence between
+// the smallest and largest IDs seen, or the expected first and last
+// IDs if set, in ascending order.  It must only be called after
+// [GapDetectorHandler.Done] has been called (which is done by
+// [Depaginator.Wait]).
+func (gd *GapDetectorHandler[T]) Gaps() []int {
+	var gaps []int
+
+	// Determine the bounds of the sequence; without IDs seen, the
+	// sequence is only known if both bounds are expected
+	first, last := gd.min, gd.max
+	if gd.First != nil {
+		first = *gd.First
+	}
+	if gd.Last != nil {
+		last = *gd.Last
+	}
+	if len(gd.seen) == 0 && (gd.First == nil || gd.Last == nil) {
+		return gaps
+	}
+
+	for id := first; id <= last; id++ {
+		if _, ok := gd.seen[id]; !ok {
+			gaps = append(gaps, id)
+		}
+	}
+
+	return gaps
+}
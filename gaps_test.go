@@ -0,0 +1,189 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func atoi(item string) int {
+	id, _ := strconv.Atoi(item)
+	return id
+}
+
+func TestGapDetectorHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &GapDetectorHandler[string]{})
+	assert.Implements(t, (*Starter)(nil), &GapDetectorHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &GapDetectorHandler[string]{})
+}
+
+func TestGapDetectorHandlerBase(t *testing.T) {
+	ctx := context.Background()
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "7")
+	obj.Handle(ctx, 1, "3")
+	obj.Handle(ctx, 2, "4")
+	obj.Handle(ctx, 3, "1")
+	obj.Handle(ctx, 4, "6")
+	obj.Handle(ctx, 5, "2")
+	obj.Done(ctx, 6, 0, 0)
+
+	assert.Equal(t, []int{5}, obj.Gaps())
+	assert.Equal(t, 1, obj.min)
+	assert.Equal(t, 7, obj.max)
+	assert.Nil(t, obj.ids)
+	assert.Nil(t, obj.done)
+}
+
+func TestGapDetectorHandlerNoGaps(t *testing.T) {
+	ctx := context.Background()
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "2")
+	obj.Handle(ctx, 1, "1")
+	obj.Handle(ctx, 2, "3")
+	obj.Done(ctx, 3, 0, 0)
+
+	assert.Nil(t, obj.Gaps())
+}
+
+func TestGapDetectorHandlerEmpty(t *testing.T) {
+	ctx := context.Background()
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Done(ctx, 0, 0, 0)
+
+	assert.Nil(t, obj.Gaps())
+}
+
+func TestGapDetectorHandlerFirst(t *testing.T) {
+	ctx := context.Background()
+	first := 1
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+		First:  &first,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "5")
+	obj.Handle(ctx, 1, "3")
+	obj.Done(ctx, 2, 0, 0)
+
+	assert.Equal(t, []int{1, 2, 4}, obj.Gaps())
+}
+
+func TestGapDetectorHandlerLast(t *testing.T) {
+	ctx := context.Background()
+	last := 7
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+		Last:   &last,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "5")
+	obj.Handle(ctx, 1, "3")
+	obj.Done(ctx, 2, 0, 0)
+
+	assert.Equal(t, []int{4, 6, 7}, obj.Gaps())
+}
+
+func TestGapDetectorHandlerFirstLastEmpty(t *testing.T) {
+	ctx := context.Background()
+	first, last := 2, 4
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+		First:  &first,
+		Last:   &last,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Done(ctx, 0, 0, 0)
+
+	assert.Equal(t, []int{2, 3, 4}, obj.Gaps())
+}
+
+func TestGapDetectorHandlerFirstOnlyEmpty(t *testing.T) {
+	ctx := context.Background()
+	first := 2
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+		First:  &first,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Done(ctx, 0, 0, 0)
+
+	assert.Nil(t, obj.Gaps())
+}
+
+func TestGapDetectorHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "6", "7", "8", "9", "10", "12",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+	}
+
+	d := Depaginate[string](ctx, data, obj)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{5, 11}, obj.Gaps())
+}
+
+func TestGapDetectorHandlerDepaginateEdges(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"2", "3", "4", "6", "7", "8",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+	first, last := 0, 10
+	obj := &GapDetectorHandler[string]{
+		IDFunc: atoi,
+		First:  &first,
+		Last:   &last,
+	}
+
+	d := Depaginate[string](ctx, data, obj)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 5, 9, 10}, obj.Gaps())
+}
@@ -18,6 +18,8 @@ package depaginator
 
 import (
 	"context"
+	"sort"
+	"sync/atomic"
 )
 
 // grow is a utility to ensure that an array has at least the
@@ -31,26 +33,91 @@ func grow[S ~[]E, E any](s S, n int) S {
 	return s
 }
 
+// GrowPolicy is a function that selects the capacity to allocate
+// when [ListHandler] must grow its list of items.  It is passed the
+// minimum capacity required and the number of items per page, which
+// may be 0 if not known, and returns the capacity to allocate.  A
+// return value smaller than the minimum required is ignored.
+type GrowPolicy func(need, perPage int) int
+
+// GrowPowerOfTwo is a [GrowPolicy] that rounds the required capacity
+// up to the next power of two.  This bounds the number of
+// reallocations when the total number of items is not known.
+func GrowPowerOfTwo(need, _ int) int {
+	capacity := 1
+	for capacity < need {
+		capacity <<= 1
+	}
+	return capacity
+}
+
+// GrowByPage is a [GrowPolicy] that rounds the required capacity up
+// to the next multiple of the number of items per page.  If the
+// number of items per page is not known, the required capacity is
+// used unchanged.  This minimizes wasted capacity, at the cost of
+// reallocating the list for nearly every page when the total number
+// of items is not known.
+func GrowByPage(need, perPage int) int {
+	if perPage <= 0 {
+		return need
+	}
+	return (need + perPage - 1) / perPage * perPage
+}
+
 // ListHandler is an implementation of [Handler] that constructs a
 // slice containing all the retrieved items in order.  It can be
 // passed to [Depaginate] multiple times, with additional items added
 // at the end of the list.  Once [ListHandler.Done] is called (which
 // is called by [Depaginator.Wait]), the Items field of the object
 // will contain the properly ordered list of items retrieved via the
-// [PageGetter].  No constructor is necessary, as a pointer to the
-// zero value of ListHandler is valid.
+// [PageGetter].  The Grow field may optionally be set to a
+// [GrowPolicy] controlling how the list is grown when the total number
+// of items is not known in advance; by default, the growth heuristic
+// of the append builtin is used.  No constructor is necessary, as a
+// pointer to the zero value of ListHandler is valid.
+//
+// If the Flush field is set, the items are not retained in the Items
+// field; instead, as soon as every item of a page-sized chunk has
+// been handled, the chunk is passed to Flush and discarded, bounding
+// the memory used.  Chunks are flushed in order, so a chunk is held
+// until all the chunks preceding it have been flushed.  Any items
+// remaining once [ListHandler.Done] is called, such as the final,
+// partial chunk, are then flushed in order.  Flush is called from a
+// single goroutine, so calls to it are serialized.
 type ListHandler[T any] struct {
-	Items []T // Final list of items
+	Items []T             // Final list of items
+	Grow  GrowPolicy      // Optional policy for growing the list of items
+	Flush func(chunk []T) // Optional function to flush completed chunks to
 
 	offset     int // Offset of starting item
 	totalItems int // Total number of items reported by [Depaginator]
 	totalPages int // Total number of pages reported by [Depaginator]
 	perPage    int // Items per page reported by [Depaginator]
 
+	pending map[int]T // Items awaiting flushing, by index
+	flushed int       // Index of the first item not yet flushed
+
+	filled  *pageMap       // Bitmap of filled item indexes
 	actions chan action[T] // Actions to process
 	done    chan struct{}  // Used to signal the daemon has exited
 }
 
+// grow ensures that the Items field has at least the specified
+// length, applying the [GrowPolicy] if one has been set.
+func (lh *ListHandler[T]) grow(n int) {
+	if lh.Grow != nil && n > cap(lh.Items) {
+		capacity := lh.Grow(n, lh.perPage)
+		if capacity < n {
+			capacity = n
+		}
+		items := make([]T, len(lh.Items), capacity)
+		copy(items, lh.Items)
+		lh.Items = items
+	}
+
+	lh.Items = grow(lh.Items, n)
+}
+
 // action submits an action to the daemon goroutine.
 func (lh *ListHandler[T]) action(act action[T]) {
 	lh.actions <- act
@@ -75,16 +142,25 @@ func (lh *ListHandler[T]) Start(_ context.Context, totalItems, totalPages, perPa
 	lh.totalItems = totalItems
 	lh.totalPages = totalPages
 	lh.perPage = perPage
+	lh.filled = &pageMap{}
 	lh.actions = make(chan action[T], DefaultCapacity)
 	lh.done = make(chan struct{})
 
+	// Items are not retained if flushing
+	if lh.Flush != nil {
+		lh.pending = map[int]T{}
+		lh.flushed = 0
+		go lh.daemon()
+		return
+	}
+
 	// Check if we can select an initial size for the Items list
 	if lh.totalItems > 0 {
-		lh.Items = grow(lh.Items, lh.offset+lh.totalItems)
+		lh.grow(lh.offset + lh.totalItems)
 	} else if lh.totalPages > 0 && lh.perPage > 0 {
-		lh.Items = grow(lh.Items, lh.offset+lh.totalPages*lh.perPage)
+		lh.grow(lh.offset + lh.totalPages*lh.perPage)
 	} else if lh.perPage > 0 {
-		lh.Items = grow(lh.Items, lh.offset+lh.perPage)
+		lh.grow(lh.offset + lh.perPage)
 	}
 
 	// Start the daemon
@@ -94,15 +170,27 @@ func (lh *ListHandler[T]) Start(_ context.Context, totalItems, totalPages, perPa
 // Done is called with the most up-to-date values of total items,
 // total pages, and items per page.  It is called once all pages have
 // been retrieved and all items handled.
-func (lh *ListHandler[T]) Done(_ context.Context, totalItems, _, _ int) {
+func (lh *ListHandler[T]) Done(_ context.Context, totalItems, _, perPage int) {
 	// Wait for processing to be completed and zero the channels
 	close(lh.actions)
 	<-lh.done
 	lh.actions = nil
 	lh.done = nil
+	lh.totalItems = totalItems
+
+	// Flush the remaining items if flushing
+	if lh.Flush != nil {
+		if perPage > 0 {
+			lh.perPage = perPage
+		}
+		lh.flushRemaining()
+		return
+	}
 
 	// Resize the slice to include just the items we got; totalItems
-	// is guaranteed to be correct at this point
+	// is guaranteed to be correct at this point, but may exceed the
+	// items seen if trailing pages could not be retrieved
+	lh.grow(lh.offset + totalItems)
 	lh.Items = lh.Items[:lh.offset+totalItems]
 }
 
@@ -125,6 +213,409 @@ func (lh *ListHandler[T]) Update(_ context.Context, totalItems, totalPages, perP
 	})
 }
 
+// flushChunks flushes each complete page-sized chunk, in order,
+// starting with the first item not yet flushed.
+func (lh *ListHandler[T]) flushChunks() {
+	for lh.perPage > 0 {
+		// Is the chunk complete?
+		for i := lh.flushed; i < lh.flushed+lh.perPage; i++ {
+			if _, ok := lh.pending[i]; !ok {
+				return
+			}
+		}
+
+		lh.flush(lh.flushed + lh.perPage)
+	}
+}
+
+// flushRemaining flushes all the remaining items, in order, in
+// page-sized chunks if the number of items per page is known.
+// Indexes with no item, such as those belonging to pages that could
+// not be retrieved, are skipped.
+func (lh *ListHandler[T]) flushRemaining() {
+	lh.flushChunks()
+
+	// Select the indexes of the remaining items
+	idxs := make([]int, 0, len(lh.pending))
+	for idx := range lh.pending {
+		if idx < lh.totalItems {
+			idxs = append(idxs, idx)
+		}
+	}
+	sort.Ints(idxs)
+
+	// Flush them in chunks
+	size := lh.perPage
+	if size <= 0 {
+		size = len(idxs)
+	}
+	for len(idxs) > 0 {
+		n := size
+		if n > len(idxs) {
+			n = len(idxs)
+		}
+		chunk := make([]T, n)
+		for i, idx := range idxs[:n] {
+			chunk[i] = lh.pending[idx]
+		}
+		lh.Flush(chunk)
+		idxs = idxs[n:]
+	}
+
+	lh.pending = nil
+}
+
+// flush flushes the items from the first item not yet flushed up to,
+// but not including, the item with the specified index.
+func (lh *ListHandler[T]) flush(end int) {
+	chunk := make([]T, 0, end-lh.flushed)
+	for i := lh.flushed; i < end; i++ {
+		chunk = append(chunk, lh.pending[i])
+		delete(lh.pending, i)
+	}
+	lh.flushed = end
+	lh.Flush(chunk)
+}
+
+// Missing returns the indexes within the Items field of the items
+// from the most recent iteration that were never filled in, such as
+// those belonging to pages that could not be retrieved.  Unlike
+// checking for zero values, this correctly distinguishes a missing
+// item from one that was retrieved but has the zero value, such as a
+// nil pointer.  It must only be called after [ListHandler.Done] has
+// been called (which is done by [Depaginator.Wait]).
+func (lh *ListHandler[T]) Missing() []int {
+	var missing []int
+	for i := 0; i < lh.totalItems; i++ {
+		if lh.filled == nil || !lh.filled.IsSet(i) {
+			missing = append(missing, lh.offset+i)
+		}
+	}
+
+	return missing
+}
+
+// SortBy stably sorts the Items field using the provided less
+// function.  It must only be called after [ListHandler.Done] has
+// been called (which is done by [Depaginator.Wait]); items that
+// compare equal retain their original, index-based order.
+func (lh *ListHandler[T]) SortBy(less func(a, b T) bool) {
+	sort.SliceStable(lh.Items, func(i, j int) bool {
+		return less(lh.Items[i], lh.Items[j])
+	})
+}
+
+// Reverse reverses the order of the Items field in place, so that
+// the last item retrieved comes first.  This is useful for presenting
+// newest-first views of APIs that return the oldest items first.  It
+// must only be called after [ListHandler.Done] has been called (which
+// is done by [Depaginator.Wait]).
+func (lh *ListHandler[T]) Reverse() {
+	for i, j := 0, len(lh.Items)-1; i < j; i, j = i+1, j-1 {
+		lh.Items[i], lh.Items[j] = lh.Items[j], lh.Items[i]
+	}
+}
+
+// Utilization reports how much of the capacity allocated for the
+// Items field was used: used is the number of items, and capacity is
+// the capacity of the slice.  A capacity much larger than used
+// suggests that the totals hints passed to [Depaginate] are too high.
+// It must only be called after [ListHandler.Done] has been called
+// (which is done by [Depaginator.Wait]).
+func (lh *ListHandler[T]) Utilization() (used, capacity int) {
+	return len(lh.Items), cap(lh.Items)
+}
+
+// intoHandler is an implementation of [Handler] that wraps a
+// [ListHandler] to fill a caller-provided slice.
+type intoHandler[T any] struct {
+	ListHandler[T]
+
+	dst *[]T // Destination slice
+}
+
+// Into returns a [Handler] that appends the retrieved items, in
+// order, to the slice pointed to by dst.  The slice is updated once
+// [Depaginator.Wait] has returned.  This is a convenience for the
+// common case where only the list of items is required; for more
+// control, use [ListHandler] directly.
+func Into[T any](dst *[]T) Handler[T] {
+	return &intoHandler[T]{
+		dst: dst,
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (ih *intoHandler[T]) Start(ctx context.Context, totalItems, totalPages, perPage int) {
+	ih.Items = *ih.dst
+	ih.ListHandler.Start(ctx, totalItems, totalPages, perPage)
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (ih *intoHandler[T]) Done(ctx context.Context, totalItems, totalPages, perPage int) {
+	ih.ListHandler.Done(ctx, totalItems, totalPages, perPage)
+	*ih.dst = ih.Items
+}
+
+// resultHandler is a [ListHandler] that passes the final list of
+// items to a callback.  It is used to implement [WithResultCallback].
+type resultHandler[T any] struct {
+	ListHandler[T]
+
+	callback func(items []T) // Function to call with the items
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (rh *resultHandler[T]) Done(ctx context.Context, totalItems, totalPages, perPage int) {
+	rh.ListHandler.Done(ctx, totalItems, totalPages, perPage)
+	rh.callback(rh.Items)
+}
+
+// ItemResult bundles together an item and its index, as sent by
+// [ChannelHandler].
+type ItemResult[T any] struct {
+	Index int // Index of the item
+	Item  T   // The item
+}
+
+// ChannelHandler is an implementation of [Handler] that sends each
+// item, along with its index, to the channel in the C field as it is
+// handled, allowing items to be processed as a stream with bounded
+// memory.  The channel is closed when [ChannelHandler.Done] is
+// called (which is called by [Depaginator.Wait]), so Wait must be
+// called for consumers ranging over the channel to terminate.  Since
+// [Handler.Handle] is called from the goroutines retrieving each
+// page, items are NOT guaranteed to be sent in order; consumers that
+// care about order must use the Index field of [ItemResult].  If the
+// context is canceled while waiting for the consumer to receive an
+// item, the item is dropped.  The C field may be set to a
+// caller-supplied channel, or [NewChannelHandler] may be used to
+// construct one.  A ChannelHandler may only be passed to
+// [Depaginate] once.
+type ChannelHandler[T any] struct {
+	C chan ItemResult[T] // Channel to send items to
+}
+
+// NewChannelHandler constructs a [ChannelHandler] with a channel
+// having the specified buffer size.
+func NewChannelHandler[T any](buf int) *ChannelHandler[T] {
+	return &ChannelHandler[T]{
+		C: make(chan ItemResult[T], buf),
+	}
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (ch *ChannelHandler[T]) Handle(ctx context.Context, idx int, item T) {
+	select {
+	case ch.C <- ItemResult[T]{Index: idx, Item: item}:
+	case <-ctx.Done():
+	}
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (ch *ChannelHandler[T]) Done(_ context.Context, _, _, _ int) {
+	close(ch.C)
+}
+
+// SinkAhead is the maximum number of pages which may be retrieved, or
+// be waiting for their items to be sent, ahead of the sink of a
+// [SinkHandler].
+const SinkAhead = 4
+
+// SinkHandler is an implementation of [Handler] that forwards each
+// item to a sink channel whose consumer may go away before the
+// iteration is complete, such as one feeding a downstream connection.
+// The consumer signals that it has gone away by closing the closed
+// channel; the handler then calls the cancel function, which should
+// cancel the context passed to [Depaginate], so that no more pages
+// are retrieved for a sink that can no longer receive them; for the
+// same reason, no more than [SinkAhead] pages are retrieved ahead of
+// the sink.  Items handled after the closed channel is closed are
+// dropped, as are items waiting to be sent when the context is
+// canceled.  Unlike [ChannelHandler], the handler does not close the
+// sink channel, which belongs to the caller.  A SinkHandler must be constructed with
+// [NewSinkHandler], and may only be passed to [Depaginate] once.
+type SinkHandler[T any] struct {
+	sink   chan<- T           // Channel to send items to
+	closed <-chan struct{}    // Closed when the sink goes away
+	cancel context.CancelFunc // Cancels the iteration
+	stop   chan struct{}      // Closed to stop watching the sink
+}
+
+// NewSinkHandler constructs a [SinkHandler] which sends items to the
+// sink channel, calling the cancel function if the closed channel is
+// closed.
+func NewSinkHandler[T any](sink chan<- T, closed <-chan struct{}, cancel context.CancelFunc) *SinkHandler[T] {
+	return &SinkHandler[T]{
+		sink:   sink,
+		closed: closed,
+		cancel: cancel,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It starts watching the sink, so that
+// the iteration is canceled as soon as the sink goes away, even if no
+// item is being sent at the time.
+func (sh *SinkHandler[T]) Start(_ context.Context, _, _, _ int) {
+	go func() {
+		select {
+		case <-sh.closed:
+			sh.cancel()
+		case <-sh.stop:
+		}
+	}()
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (sh *SinkHandler[T]) Handle(ctx context.Context, _ int, item T) {
+	// Never send to a sink that has gone away
+	select {
+	case <-sh.closed:
+		sh.cancel()
+		return
+	default:
+	}
+
+	select {
+	case sh.sink <- item:
+	case <-sh.closed:
+		sh.cancel()
+	case <-ctx.Done():
+	}
+}
+
+// ahead returns the maximum number of pages which may be retrieved
+// ahead of the sink.
+func (sh *SinkHandler[T]) ahead() int {
+	return SinkAhead
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.  It stops watching the sink.
+func (sh *SinkHandler[T]) Done(_ context.Context, _, _, _ int) {
+	close(sh.stop)
+}
+
+// CountingHandler is an implementation of [Handler] that counts the
+// retrieved items without retaining them.  The count may be read at
+// any time, from any goroutine, using [CountingHandler.Count]; once
+// [CountingHandler.Done] is called (which is called by
+// [Depaginator.Wait]), the Total field of the object will contain the
+// final count.  No constructor is necessary, as a pointer to the zero
+// value of CountingHandler is valid.  It can be passed to
+// [Depaginate] multiple times, in which case the count accumulates.
+type CountingHandler[T any] struct {
+	Total int // Final count of items
+
+	count atomic.Int64 // Count of items handled
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (ch *CountingHandler[T]) Handle(_ context.Context, _ int, _ T) {
+	ch.count.Add(1)
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (ch *CountingHandler[T]) Done(_ context.Context, _, _, _ int) {
+	ch.Total = ch.Count()
+}
+
+// Count returns the number of items handled so far.
+func (ch *CountingHandler[T]) Count() int {
+	return int(ch.count.Load())
+}
+
+// MapHandler is an implementation of [Handler] that collects the
+// retrieved items into a map, keyed by the result of a caller-supplied
+// key function.  Once [MapHandler.Done] is called (which is called by
+// [Depaginator.Wait]), the Items field of the object will contain all
+// the items retrieved via the [PageGetter].  If several items have
+// the same key, the last one handled wins; as pages may be retrieved
+// concurrently, which one that is is not defined.  A MapHandler must
+// be constructed with [NewMapHandler]; it may be passed to
+// [Depaginate] multiple times, with additional items added to the
+// map.
+type MapHandler[K comparable, T any] struct {
+	Items map[K]T // Final map of items
+
+	keyFn   func(item T) K       // Function to compute item keys
+	actions chan mapAction[K, T] // Actions to process
+	done    chan struct{}        // Used to signal the daemon has exited
+}
+
+// NewMapHandler constructs a [MapHandler] that keys items by the
+// result of calling keyFn on them.
+func NewMapHandler[K comparable, T any](keyFn func(item T) K) *MapHandler[K, T] {
+	return &MapHandler[K, T]{
+		Items: map[K]T{},
+		keyFn: keyFn,
+	}
+}
+
+// action submits an action to the daemon goroutine.
+func (mh *MapHandler[K, T]) action(act mapAction[K, T]) {
+	mh.actions <- act
+}
+
+// daemon processes actions.  Using [MapHandler.action] and daemon
+// together prevents [MapHandler] from needing to use [sync.Mutex].
+func (mh *MapHandler[K, T]) daemon() {
+	defer close(mh.done)
+	for act := range mh.actions {
+		// Apply the action
+		act.applyAction(mh)
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (mh *MapHandler[K, T]) Start(_ context.Context, totalItems, _, _ int) {
+	if mh.Items == nil {
+		mh.Items = make(map[K]T, totalItems)
+	}
+	mh.actions = make(chan mapAction[K, T], DefaultCapacity)
+	mh.done = make(chan struct{})
+
+	// Start the daemon
+	go mh.daemon()
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (mh *MapHandler[K, T]) Done(_ context.Context, _, _, _ int) {
+	// Wait for processing to be completed and zero the channels
+	close(mh.actions)
+	<-mh.done
+	mh.actions = nil
+	mh.done = nil
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (mh *MapHandler[K, T]) Handle(_ context.Context, _ int, item T) {
+	mh.action(mapItem[K, T]{
+		item: item,
+	})
+}
+
 // action specifies an action to perform on a [ListHandler] instance.
 type action[T any] interface {
 	// applyAction applies an action.
@@ -141,17 +632,30 @@ type handleItem[T any] struct {
 
 // applyAction applies an action.
 func (a handleItem[T]) applyAction(lh *ListHandler[T]) {
+	// Hold the item for flushing if required
+	if lh.Flush != nil {
+		lh.pending[a.idx] = a.item
+		if lh.filled != nil {
+			lh.filled.CheckAndSet(a.idx)
+		}
+		lh.flushChunks()
+		return
+	}
+
 	// Do we need to grow the list?
 	if lh.offset+a.idx >= len(lh.Items) {
 		if lh.perPage > 0 {
-			lh.Items = grow(lh.Items, lh.offset+a.idx+lh.perPage)
+			lh.grow(lh.offset + a.idx + lh.perPage)
 		} else {
-			lh.Items = grow(lh.Items, lh.offset+a.idx+1)
+			lh.grow(lh.offset + a.idx + 1)
 		}
 	}
 
 	// Save the item
 	lh.Items[lh.offset+a.idx] = a.item
+	if lh.filled != nil {
+		lh.filled.CheckAndSet(a.idx)
+	}
 }
 
 // listUpdate is an implementation of [action] that saves updates to
@@ -171,10 +675,34 @@ func (a listUpdate[T]) applyAction(lh *ListHandler[T]) {
 	lh.totalPages = a.totalPages
 	lh.perPage = a.perPage
 
+	// Flush any chunks now known to be complete
+	if lh.Flush != nil {
+		lh.flushChunks()
+		return
+	}
+
 	// Update the capacity if warranted
 	if lh.totalItems > 0 {
-		lh.Items = grow(lh.Items, lh.offset+lh.totalItems)
+		lh.grow(lh.offset + lh.totalItems)
 	} else if lh.totalPages > 0 && lh.perPage > 0 {
-		lh.Items = grow(lh.Items, lh.offset+lh.totalPages*lh.perPage)
+		lh.grow(lh.offset + lh.totalPages*lh.perPage)
 	}
 }
+
+// mapAction specifies an action to perform on a [MapHandler]
+// instance.
+type mapAction[K comparable, T any] interface {
+	// applyAction applies an action.
+	applyAction(mh *MapHandler[K, T])
+}
+
+// mapItem is an implementation of [mapAction] that handles an item,
+// adding it to the map maintained in [MapHandler] under its key.
+type mapItem[K comparable, T any] struct {
+	item T // Item to be handled
+}
+
+// applyAction applies an action.
+func (a mapItem[K, T]) applyAction(mh *MapHandler[K, T]) {
+	mh.Items[mh.keyFn(a.item)] = a.item
+}
@@ -18,7 +18,10 @@ package depaginator
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -42,6 +45,102 @@ func TestGrowExisting(t *testing.T) {
 	assert.GreaterOrEqual(t, cap(result), 5)
 }
 
+func TestGrowPowerOfTwo(t *testing.T) {
+	assert.Equal(t, 1, GrowPowerOfTwo(0, 5))
+	assert.Equal(t, 1, GrowPowerOfTwo(1, 5))
+	assert.Equal(t, 8, GrowPowerOfTwo(5, 5))
+	assert.Equal(t, 16, GrowPowerOfTwo(16, 5))
+	assert.Equal(t, 32, GrowPowerOfTwo(17, 5))
+}
+
+func TestGrowByPageBase(t *testing.T) {
+	assert.Equal(t, 10, GrowByPage(6, 5))
+	assert.Equal(t, 10, GrowByPage(10, 5))
+}
+
+func TestGrowByPageUnknown(t *testing.T) {
+	assert.Equal(t, 7, GrowByPage(7, 0))
+}
+
+func TestListHandlerGrowBase(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: []string{"foo"},
+	}
+
+	obj.grow(5)
+
+	assert.Len(t, obj.Items, 5)
+	assert.Equal(t, "foo", obj.Items[0])
+}
+
+func TestListHandlerGrowPolicy(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: []string{"foo"},
+		Grow:  GrowPowerOfTwo,
+	}
+
+	obj.grow(5)
+
+	assert.Len(t, obj.Items, 5)
+	assert.Equal(t, 8, cap(obj.Items))
+	assert.Equal(t, "foo", obj.Items[0])
+}
+
+func TestListHandlerGrowPolicyUnneeded(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: make([]string, 3, 10),
+		Grow: func(_, _ int) int {
+			panic("policy called")
+		},
+	}
+
+	obj.grow(5)
+
+	assert.Len(t, obj.Items, 5)
+	assert.Equal(t, 10, cap(obj.Items))
+}
+
+func TestListHandlerGrowPolicyTooSmall(t *testing.T) {
+	obj := &ListHandler[string]{
+		Grow: func(_, _ int) int {
+			return 2
+		},
+	}
+
+	obj.grow(5)
+
+	assert.Len(t, obj.Items, 5)
+	assert.GreaterOrEqual(t, cap(obj.Items), 5)
+}
+
+func benchmarkListHandlerGrow(b *testing.B, policy GrowPolicy) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lh := &ListHandler[int]{
+			Grow:    policy,
+			perPage: 10,
+		}
+		for idx := 0; idx < 10000; idx++ {
+			handleItem[int]{
+				idx:  idx,
+				item: idx,
+			}.applyAction(lh)
+		}
+	}
+}
+
+func BenchmarkListHandlerGrowDefault(b *testing.B) {
+	benchmarkListHandlerGrow(b, nil)
+}
+
+func BenchmarkListHandlerGrowPowerOfTwo(b *testing.B) {
+	benchmarkListHandlerGrow(b, GrowPowerOfTwo)
+}
+
+func BenchmarkListHandlerGrowByPage(b *testing.B) {
+	benchmarkListHandlerGrow(b, GrowByPage)
+}
+
 func TestListHandlerImplementsInterfaces(t *testing.T) {
 	assert.Implements(t, (*Handler[string])(nil), &ListHandler[string]{})
 	assert.Implements(t, (*Starter)(nil), &ListHandler[string]{})
@@ -300,6 +399,211 @@ func TestListHandlerUpdate(t *testing.T) {
 	close(obj.actions)
 }
 
+func TestListHandlerStartFlush(t *testing.T) {
+	ctx := context.Background()
+	obj := &ListHandler[string]{
+		Flush:   func(chunk []string) {},
+		flushed: 5,
+	}
+
+	obj.Start(ctx, 10, 4, 3)
+
+	assert.Empty(t, obj.Items)
+	assert.NotNil(t, obj.pending)
+	assert.Equal(t, 0, obj.flushed)
+	obj.Done(ctx, 0, 0, 0)
+}
+
+func TestListHandlerDoneFlush(t *testing.T) {
+	ctx := context.Background()
+	var chunks [][]string
+	obj := &ListHandler[string]{
+		Flush: func(chunk []string) {
+			chunks = append(chunks, chunk)
+		},
+		pending: map[int]string{
+			3: "three",
+			4: "four",
+			6: "six",
+			7: "seven",
+			8: "eight",
+			9: "nine",
+		},
+		flushed: 3,
+		actions: make(chan action[string], DefaultCapacity),
+		done:    make(chan struct{}),
+	}
+	close(obj.done)
+
+	obj.Done(ctx, 9, 3, 3)
+
+	assert.Equal(t, [][]string{
+		{"three", "four", "six"},
+		{"seven", "eight"},
+	}, chunks)
+	assert.Nil(t, obj.pending)
+	assert.Empty(t, obj.Items)
+}
+
+func TestListHandlerFlushChunksUnknownPerPage(t *testing.T) {
+	var chunks [][]string
+	obj := &ListHandler[string]{
+		Flush: func(chunk []string) {
+			chunks = append(chunks, chunk)
+		},
+		pending: map[int]string{
+			0: "zero",
+		},
+	}
+
+	obj.flushChunks()
+
+	assert.Empty(t, chunks)
+}
+
+func TestListHandlerFlushRemainingUnknownPerPage(t *testing.T) {
+	var chunks [][]string
+	obj := &ListHandler[string]{
+		Flush: func(chunk []string) {
+			chunks = append(chunks, chunk)
+		},
+		totalItems: 3,
+		pending: map[int]string{
+			2: "two",
+			0: "zero",
+			1: "one",
+		},
+	}
+
+	obj.flushRemaining()
+
+	assert.Equal(t, [][]string{{"zero", "one", "two"}}, chunks)
+}
+
+func TestListHandlerFlushDepaginate(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		ctx := context.Background()
+		data := []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+		pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+			if req.PageIndex == 0 {
+				depag.Update(TotalPages(4), PerPage(3))
+				depag.RequestRange(1, 4, nil)
+
+				// Make the first page arrive last
+				time.Sleep(10 * time.Millisecond)
+			}
+			end := (req.PageIndex + 1) * 3
+			if end > len(data) {
+				end = len(data)
+			}
+			return data[req.PageIndex*3 : end], nil
+		})
+		var chunks [][]string
+		obj := &ListHandler[string]{
+			Flush: func(chunk []string) {
+				chunks = append(chunks, chunk)
+			},
+		}
+
+		err := Depaginate[string](ctx, pager, obj).Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"0", "1", "2"},
+			{"3", "4", "5"},
+			{"6", "7", "8"},
+			{"9", "10"},
+		}, chunks)
+		assert.Empty(t, obj.Items)
+	}
+}
+
+func TestListHandlerSortBy(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: []string{"bb", "a", "cc", "b", "aa", "c"},
+	}
+
+	obj.SortBy(func(a, b string) bool {
+		return len(a) < len(b)
+	})
+
+	assert.Equal(t, []string{"a", "b", "c", "bb", "cc", "aa"}, obj.Items)
+}
+
+func TestListHandlerReverseEven(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: []string{"a", "b", "c", "d"},
+	}
+
+	obj.Reverse()
+
+	assert.Equal(t, []string{"d", "c", "b", "a"}, obj.Items)
+}
+
+func TestListHandlerReverseOdd(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: []string{"a", "b", "c", "d", "e"},
+	}
+
+	obj.Reverse()
+
+	assert.Equal(t, []string{"e", "d", "c", "b", "a"}, obj.Items)
+}
+
+func TestListHandlerUtilization(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items: make([]string, 3, 8),
+	}
+
+	used, capacity := obj.Utilization()
+
+	assert.Equal(t, 3, used)
+	assert.Equal(t, 8, capacity)
+}
+
+func TestListHandlerMissingBase(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items:      []string{"foo", "", "baz", "", "qux"},
+		offset:     1,
+		totalItems: 4,
+		filled: &pageMap{
+			bits: []uint{5},
+		},
+	}
+
+	result := obj.Missing()
+
+	assert.Equal(t, []int{2, 4}, result)
+}
+
+func TestListHandlerMissingNone(t *testing.T) {
+	obj := &ListHandler[string]{
+		Items:      []string{"foo", "bar"},
+		totalItems: 2,
+		filled: &pageMap{
+			bits: []uint{3},
+		},
+	}
+
+	result := obj.Missing()
+
+	assert.Nil(t, result)
+}
+
+func TestListHandlerMissingPointers(t *testing.T) {
+	ctx := context.Background()
+	obj := &ListHandler[*string]{}
+	foo := "foo"
+
+	obj.Start(ctx, 3, 0, 0)
+	obj.Handle(ctx, 0, &foo)
+	obj.Handle(ctx, 1, nil)
+	obj.Done(ctx, 3, 0, 0)
+
+	assert.Equal(t, []*string{&foo, nil, nil}, obj.Items)
+	assert.Equal(t, []int{2}, obj.Missing())
+}
+
 type mockAction struct {
 	mock.Mock
 }
@@ -308,6 +612,418 @@ func (m *mockAction) applyAction(lh *ListHandler[string]) { //nolint:unused
 	m.Called(lh)
 }
 
+func TestIntoImplementsInterfaces(t *testing.T) {
+	obj := Into(&[]string{})
+
+	assert.Implements(t, (*Starter)(nil), obj)
+	assert.Implements(t, (*Updater)(nil), obj)
+	assert.Implements(t, (*Doner)(nil), obj)
+}
+
+func TestIntoBase(t *testing.T) {
+	ctx := context.Background()
+	dst := []string{"existing"}
+	obj := Into(&dst)
+
+	obj.(Starter).Start(ctx, 3, 1, 3)
+	obj.Handle(ctx, 2, "two")
+	obj.Handle(ctx, 0, "zero")
+	obj.Handle(ctx, 1, "one")
+	obj.(Doner).Done(ctx, 3, 1, 3)
+
+	assert.Equal(t, []string{"existing", "zero", "one", "two"}, dst)
+}
+
+func TestIntoDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	var dst []string
+
+	d := Depaginate[string](ctx, data, Into(&dst), WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, dst)
+}
+
+func TestResultHandlerDone(t *testing.T) {
+	ctx := context.Background()
+	var calls [][]string
+	obj := &resultHandler[string]{
+		callback: func(items []string) {
+			calls = append(calls, items)
+		},
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 1, "one")
+	obj.Handle(ctx, 0, "zero")
+	obj.Done(ctx, 2, 1, 2)
+
+	assert.Equal(t, [][]string{{"zero", "one"}}, calls)
+}
+
+func TestChannelHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &ChannelHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &ChannelHandler[string]{})
+}
+
+func TestNewChannelHandler(t *testing.T) {
+	result := NewChannelHandler[string](5)
+
+	assert.Equal(t, 5, cap(result.C))
+}
+
+func TestChannelHandlerHandleBase(t *testing.T) {
+	ctx := context.Background()
+	obj := NewChannelHandler[string](1)
+
+	obj.Handle(ctx, 3, "three")
+
+	assert.Equal(t, ItemResult[string]{Index: 3, Item: "three"}, <-obj.C)
+}
+
+func TestChannelHandlerHandleCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	obj := NewChannelHandler[string](0)
+
+	obj.Handle(ctx, 3, "three")
+
+	assert.Len(t, obj.C, 0)
+}
+
+func TestChannelHandlerDone(t *testing.T) {
+	ctx := context.Background()
+	obj := NewChannelHandler[string](1)
+
+	obj.Done(ctx, 0, 0, 0)
+
+	_, ok := <-obj.C
+	assert.False(t, ok)
+}
+
+func TestChannelHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	obj := NewChannelHandler[string](0)
+	result := make([]string, len(data.data))
+
+	d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+	errs := make(chan error)
+	go func() {
+		errs <- d.Wait()
+	}()
+	for res := range obj.C {
+		result[res.Index] = res.Item
+	}
+
+	assert.NoError(t, <-errs)
+	assert.Equal(t, data.data, result)
+}
+
+func TestSinkHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &SinkHandler[string]{})
+	assert.Implements(t, (*Starter)(nil), &SinkHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &SinkHandler[string]{})
+	assert.Implements(t, (*pacer)(nil), &SinkHandler[string]{})
+}
+
+func TestSinkHandlerAhead(t *testing.T) {
+	obj := &SinkHandler[string]{}
+
+	result := obj.ahead()
+
+	assert.Equal(t, SinkAhead, result)
+}
+
+func TestNewSinkHandler(t *testing.T) {
+	sink := make(chan string)
+	closed := make(chan struct{})
+
+	result := NewSinkHandler[string](sink, closed, func() {})
+
+	assert.Equal(t, (chan<- string)(sink), result.sink)
+	assert.Equal(t, (<-chan struct{})(closed), result.closed)
+	assert.NotNil(t, result.cancel)
+	assert.NotNil(t, result.stop)
+}
+
+func TestSinkHandlerHandleBase(t *testing.T) {
+	ctx := context.Background()
+	sink := make(chan string, 1)
+	canceled := false
+	obj := NewSinkHandler[string](sink, make(chan struct{}), func() {
+		canceled = true
+	})
+
+	obj.Handle(ctx, 3, "three")
+
+	assert.Equal(t, "three", <-sink)
+	assert.False(t, canceled)
+}
+
+func TestSinkHandlerHandleClosed(t *testing.T) {
+	ctx := context.Background()
+	sink := make(chan string, 1)
+	closed := make(chan struct{})
+	close(closed)
+	canceled := false
+	obj := NewSinkHandler[string](sink, closed, func() {
+		canceled = true
+	})
+
+	obj.Handle(ctx, 3, "three")
+
+	assert.Len(t, sink, 0)
+	assert.True(t, canceled)
+}
+
+func TestSinkHandlerHandleClosedWhileWaiting(t *testing.T) {
+	ctx := context.Background()
+	sink := make(chan string)
+	closed := make(chan struct{})
+	canceled := make(chan struct{})
+	obj := NewSinkHandler[string](sink, closed, func() {
+		close(canceled)
+	})
+	go close(closed)
+
+	obj.Handle(ctx, 3, "three")
+
+	<-canceled
+}
+
+func TestSinkHandlerHandleCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	sink := make(chan string)
+	canceled := false
+	obj := NewSinkHandler[string](sink, make(chan struct{}), func() {
+		canceled = true
+	})
+
+	obj.Handle(ctx, 3, "three")
+
+	assert.False(t, canceled)
+}
+
+func TestSinkHandlerStartWatches(t *testing.T) {
+	ctx := context.Background()
+	closed := make(chan struct{})
+	canceled := make(chan struct{})
+	obj := NewSinkHandler[string](make(chan string), closed, func() {
+		close(canceled)
+	})
+
+	obj.Start(ctx, 0, 0, 0)
+	close(closed)
+
+	<-canceled
+}
+
+func TestSinkHandlerDone(t *testing.T) {
+	ctx := context.Background()
+	canceled := false
+	obj := NewSinkHandler[string](make(chan string), make(chan struct{}), func() {
+		canceled = true
+	})
+	obj.Start(ctx, 0, 0, 0)
+
+	obj.Done(ctx, 0, 0, 0)
+
+	_, ok := <-obj.stop
+	assert.False(t, ok)
+	assert.False(t, canceled)
+}
+
+func TestSinkHandlerDepaginate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var fetched atomic.Int32
+	data := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		fetched.Add(1)
+		depag.Update(TotalPages(100), PerPage(1))
+		depag.Request(req.PageIndex+1, nil)
+		select {
+		case <-time.After(time.Millisecond):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return []string{fmt.Sprintf("%d", req.PageIndex)}, nil
+	})
+	sink := make(chan string)
+	closed := make(chan struct{})
+	obj := NewSinkHandler[string](sink, closed, cancel)
+
+	d := Depaginate[string](ctx, data, obj)
+	for i := 0; i < 3; i++ {
+		<-sink
+	}
+	close(closed)
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Less(t, int(fetched.Load()), 50)
+}
+
+func TestCountingHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &CountingHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &CountingHandler[string]{})
+}
+
+func TestCountingHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	obj := &CountingHandler[string]{}
+
+	obj.Handle(ctx, 0, "zero")
+	obj.Handle(ctx, 1, "one")
+
+	assert.Equal(t, 2, obj.Count())
+	assert.Equal(t, 0, obj.Total)
+}
+
+func TestCountingHandlerDone(t *testing.T) {
+	ctx := context.Background()
+	obj := &CountingHandler[string]{}
+	obj.count.Store(3)
+
+	obj.Done(ctx, 3, 1, 3)
+
+	assert.Equal(t, 3, obj.Total)
+}
+
+func TestCountingHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	obj := &CountingHandler[string]{}
+
+	d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11, obj.Total)
+	assert.Equal(t, 11, obj.Count())
+}
+
+func TestMapHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &MapHandler[int, string]{})
+	assert.Implements(t, (*Starter)(nil), &MapHandler[int, string]{})
+	assert.Implements(t, (*Doner)(nil), &MapHandler[int, string]{})
+}
+
+func TestNewMapHandler(t *testing.T) {
+	result := NewMapHandler(func(item string) int { return len(item) })
+
+	assert.Equal(t, map[int]string{}, result.Items)
+	assert.NotNil(t, result.keyFn)
+}
+
+func TestMapHandlerAction(t *testing.T) {
+	obj := &MapHandler[int, string]{
+		actions: make(chan mapAction[int, string], 1),
+	}
+	act := mapItem[int, string]{
+		item: "one",
+	}
+
+	obj.action(act)
+
+	assert.Equal(t, act, <-obj.actions)
+}
+
+func TestMapHandlerStartBase(t *testing.T) {
+	ctx := context.Background()
+	obj := &MapHandler[int, string]{}
+
+	obj.Start(ctx, 5, 0, 0)
+	defer obj.Done(ctx, 5, 0, 0)
+
+	assert.Equal(t, map[int]string{}, obj.Items)
+	assert.NotNil(t, obj.actions)
+	assert.NotNil(t, obj.done)
+}
+
+func TestMapHandlerStartExisting(t *testing.T) {
+	ctx := context.Background()
+	obj := &MapHandler[int, string]{
+		Items: map[int]string{1: "a"},
+	}
+
+	obj.Start(ctx, 5, 0, 0)
+	defer obj.Done(ctx, 5, 0, 0)
+
+	assert.Equal(t, map[int]string{1: "a"}, obj.Items)
+}
+
+func TestMapHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	obj := NewMapHandler(func(item string) int { return len(item) })
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "a")
+	obj.Handle(ctx, 1, "bb")
+	obj.Handle(ctx, 2, "cc")
+	obj.Done(ctx, 3, 0, 0)
+
+	assert.Equal(t, map[int]string{1: "a", 2: "cc"}, obj.Items)
+	assert.Nil(t, obj.actions)
+	assert.Nil(t, obj.done)
+}
+
+func TestMapHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	obj := NewMapHandler(func(item string) string { return "k" + item })
+
+	d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Len(t, obj.Items, len(data.data))
+	for _, item := range data.data {
+		assert.Equal(t, item, obj.Items["k"+item])
+	}
+}
+
+func TestMapItemImplementsMapAction(t *testing.T) {
+	assert.Implements(t, (*mapAction[int, string])(nil), mapItem[int, string]{})
+}
+
+func TestMapItemApplyActionOverwrites(t *testing.T) {
+	obj := mapItem[int, string]{
+		item: "bb",
+	}
+	mh := &MapHandler[int, string]{
+		Items: map[int]string{2: "aa"},
+		keyFn: func(item string) int { return len(item) },
+	}
+
+	obj.applyAction(mh)
+
+	assert.Equal(t, map[int]string{2: "bb"}, mh.Items)
+}
+
 func TestHandleItemImplementsAction(t *testing.T) {
 	assert.Implements(t, (*action[string])(nil), handleItem[string]{})
 }
@@ -327,6 +1043,22 @@ func TestHandleItemApplyActionBase(t *testing.T) {
 	assert.Equal(t, "three", lh.Items[3])
 }
 
+func TestHandleItemApplyActionFilled(t *testing.T) {
+	obj := handleItem[string]{
+		idx:  3,
+		item: "three",
+	}
+	lh := &ListHandler[string]{
+		Items:  make([]string, 5),
+		filled: &pageMap{},
+	}
+
+	obj.applyAction(lh)
+
+	assert.Equal(t, "three", lh.Items[3])
+	assert.True(t, lh.filled.IsSet(3))
+}
+
 func TestHandleItemApplyActionWithOffset(t *testing.T) {
 	obj := handleItem[string]{
 		idx:  3,
@@ -402,6 +1134,34 @@ func TestHandleItemApplyActionGrowPerPageWithOffset(t *testing.T) {
 	assert.Equal(t, "three", lh.Items[4])
 }
 
+func TestHandleItemApplyActionFlushOutOfOrder(t *testing.T) {
+	var chunks [][]string
+	lh := &ListHandler[string]{
+		Flush: func(chunk []string) {
+			chunks = append(chunks, chunk)
+		},
+		perPage: 2,
+		pending: map[int]string{},
+		filled:  &pageMap{},
+	}
+
+	handleItem[string]{idx: 3, item: "three"}.applyAction(lh)
+	handleItem[string]{idx: 2, item: "two"}.applyAction(lh)
+	assert.Empty(t, chunks)
+	handleItem[string]{idx: 0, item: "zero"}.applyAction(lh)
+	assert.Empty(t, chunks)
+	handleItem[string]{idx: 1, item: "one"}.applyAction(lh)
+
+	assert.Equal(t, [][]string{
+		{"zero", "one"},
+		{"two", "three"},
+	}, chunks)
+	assert.Empty(t, lh.pending)
+	assert.Equal(t, 4, lh.flushed)
+	assert.True(t, lh.filled.IsSet(3))
+	assert.Empty(t, lh.Items)
+}
+
 func TestListUpdateImplementsAction(t *testing.T) {
 	assert.Implements(t, (*action[string])(nil), listUpdate[string]{})
 }
@@ -473,3 +1233,28 @@ func TestListUpdateApplyActionWithOffsetNoTotal(t *testing.T) {
 }
 
 // XXX TestListUpdateApplyAction
+
+func TestListUpdateApplyActionFlush(t *testing.T) {
+	var chunks [][]string
+	obj := listUpdate[string]{
+		totalItems: 10,
+		totalPages: 4,
+		perPage:    2,
+	}
+	lh := &ListHandler[string]{
+		Flush: func(chunk []string) {
+			chunks = append(chunks, chunk)
+		},
+		pending: map[int]string{
+			0: "zero",
+			1: "one",
+			2: "two",
+		},
+	}
+
+	obj.applyAction(lh)
+
+	assert.Equal(t, [][]string{{"zero", "one"}}, chunks)
+	assert.Equal(t, map[int]string{2: "two"}, lh.pending)
+	assert.Empty(t, lh.Items)
+}
@@ -16,7 +16,10 @@
 
 package depaginator
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // State describes the state of depagination.  It provides the
 // feedback mechanism for requesting updates to the depaginator state,
@@ -37,15 +40,44 @@ type State interface {
 	// number of pages (if known).
 	Request(idx int, req any)
 
-	// PerPage retrieves the configured "per page" value for
+	// RequestRange requests the [Depaginator] retrieve the pages with
+	// indexes from start up to, but not including, end.  If reqFn is
+	// not nil, it is called with each page index to obtain the
+	// request for that page.  This is equivalent to calling Request
+	// for each page, but places far less load on the [Depaginator],
+	// as all the requests are submitted together; as with Request,
+	// duplicate requests and requests for pages beyond the total
+	// number of pages are ignored.
+	RequestRange(start, end int, reqFn func(idx int) any)
+
+	// RequestNext requests the [Depaginator] retrieve the page
+	// following the highest page requested so far.  This is intended
+	// for cursor-based APIs, where the page getter learns of the next
+	// page only from the page it is retrieving and has no use for a
+	// page index of its own.  The index is assigned by the
+	// [Depaginator] when the request is processed, so concurrent
+	// calls each receive a distinct index; as with Request, the
+	// request is ignored if the assigned index is beyond the total
+	// number of pages.
+	RequestNext(req any)
+
+	// MarkLast declares that the page with the specified index is
+	// the final page.  This sets the total number of pages
+	// authoritatively; subsequent attempts to update the total
+	// number of pages are ignored, and any in-flight requests for
+	// pages beyond the final page are canceled.  This is intended
+	// for APIs that signal the last page explicitly, rather than
+	// through item counts.
+	MarkLast(idx int)
+
+	// PerPage retrieves the current "per page" value for
 	// [Depaginator].  This allows a consumer to set the number of
 	// items per page when calling [Depaginate] (using the [PerPage]
-	// option).  Applications should be careful to not mix this
-	// functionality with dynamic collection of the "per page" value,
-	// as the value is not protected by any mutex; if using this
-	// method, avoid passing [PerPage] to [Depaginator.Update] and
-	// arrange for a reasonable default if [PerPage] is not passed to
-	// [Depaginate] (in which case, this method will return 0).
+	// option) and read it back when retrieving pages.  The value is
+	// read consistently with any updates to the totals, including
+	// those previously submitted through Update, so it may be called
+	// at any time; if the number of items per page was neither passed
+	// to [Depaginate] nor reported, this method will return 0.
 	PerPage() int
 }
 
@@ -84,6 +116,90 @@ func (f PageGetterFunc[T]) GetPage(ctx context.Context, depag State, req PageReq
 	return f(ctx, depag, req)
 }
 
+// PageGetterMeta is an interface for a GetPageMeta method that
+// retrieves a page specified by the given [PageRequest].  Unlike
+// [PageGetter], it is not passed the [State]; instead, it returns the
+// metadata it discovers, such as the total number of items or the
+// additional pages to request, in a [PageMeta] along with the page.
+// Since its method differs from that of [PageGetter], a
+// PageGetterMeta must be wrapped using [MetaPager] to be passed to
+// [Depaginate].
+type PageGetterMeta[T any] interface {
+	// GetPageMeta is a page retriever function.  It is passed a
+	// [PageRequest] object describing the page to request, and
+	// returns the page of items of the appropriate type and its
+	// metadata, or an error.  The TotalItems, TotalPages, and
+	// PerPage fields of the metadata are used to update the
+	// [Depaginator] if they are greater than 0, and the pages
+	// listed in its Requests field are requested; its other fields
+	// are ignored.
+	GetPageMeta(ctx context.Context, req PageRequest) (Page[T], PageMeta, error)
+}
+
+// PageGetterMetaFunc is a wrapper for a function matching the
+// [PageGetterMeta.GetPageMeta] signature.  The wrapper implements the
+// [PageGetterMeta] interface, allowing a function to be passed
+// instead of an interface implementation.
+type PageGetterMetaFunc[T any] func(ctx context.Context, req PageRequest) (Page[T], PageMeta, error)
+
+// GetPageMeta is a page retriever function.  It is passed a
+// [PageRequest] object describing the page to request, and returns
+// the page of items of the appropriate type and its metadata, or an
+// error.
+func (f PageGetterMetaFunc[T]) GetPageMeta(ctx context.Context, req PageRequest) (Page[T], PageMeta, error) {
+	return f(ctx, req)
+}
+
+// metaPager is an implementation of [PageGetter] that wraps a
+// [PageGetterMeta], submitting the metadata it returns through the
+// [State].
+type metaPager[T any] struct {
+	pager PageGetterMeta[T] // The wrapped page getter
+}
+
+// MetaPager wraps a [PageGetterMeta] so that it may be passed to
+// [Depaginate].  The metadata returned with each page is submitted
+// through the [State] before the items of the page are handled: the
+// totals using [State.Update], then the additional pages using
+// [State.Request].  The metadata returned with an error is ignored.
+func MetaPager[T any](pager PageGetterMeta[T]) PageGetter[T] {
+	return metaPager[T]{
+		pager: pager,
+	}
+}
+
+// GetPage is a page retriever function.  It is passed the [State] and
+// a [PageRequest] object describing the page to request, and returns
+// the items retrieved by the wrapped [PageGetterMeta], or an error.
+func (mp metaPager[T]) GetPage(ctx context.Context, depag State, req PageRequest) ([]T, error) {
+	page, meta, err := mp.pager.GetPageMeta(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Submit the totals
+	var updates []any
+	if meta.TotalItems > 0 {
+		updates = append(updates, TotalItems(meta.TotalItems))
+	}
+	if meta.TotalPages > 0 {
+		updates = append(updates, TotalPages(meta.TotalPages))
+	}
+	if meta.PerPage > 0 {
+		updates = append(updates, PerPage(meta.PerPage))
+	}
+	if len(updates) > 0 {
+		depag.Update(updates...)
+	}
+
+	// Request the additional pages
+	for _, r := range meta.Requests {
+		depag.Request(r.PageIndex, r.Request)
+	}
+
+	return page.Items, nil
+}
+
 // Handler is an interface for handling items iterated over in a given
 // page.  Note that the handler is called from a common goroutine, so
 // if extensive processing will be performed, a new goroutine should
@@ -107,6 +223,58 @@ func (f HandlerFunc[T]) Handle(ctx context.Context, idx int, item T) {
 	f(ctx, idx, item)
 }
 
+// StatefulHandler is an interface for handling items iterated over in
+// a given page, for handlers which require access to the [State].
+// This allows a handler which, on seeing certain items, knows that
+// additional pages are needed to request them itself.  Since its
+// Handle method differs from that of [Handler], a StatefulHandler
+// must be wrapped using [Stateful] to be passed to [Depaginate].
+type StatefulHandler[T any] interface {
+	// Handle is called for each item in a page of items retrieved by
+	// the [PageGetter].  It is called with the item index, the item,
+	// and the [State] of the depagination.
+	Handle(ctx context.Context, idx int, item T, state State)
+}
+
+// StatefulHandlerFunc is a wrapper for a function matching the
+// [StatefulHandler.Handle] signature.  The wrapper implements the
+// [StatefulHandler] interface, allowing a function to be passed
+// instead of an interface implementation.
+type StatefulHandlerFunc[T any] func(ctx context.Context, idx int, item T, state State)
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index, the item, and the
+// [State] of the depagination.
+func (f StatefulHandlerFunc[T]) Handle(ctx context.Context, idx int, item T, state State) {
+	f(ctx, idx, item, state)
+}
+
+// statefulHandler is an implementation of [Handler] that wraps a
+// [StatefulHandler].  It is detected by [Depaginate], which passes
+// the [State] to the wrapped [StatefulHandler].
+type statefulHandler[T any] struct {
+	handler StatefulHandler[T] // The wrapped handler
+}
+
+// Stateful wraps a [StatefulHandler] so that it may be passed to
+// [Depaginate].  If the [StatefulHandler] also implements [Starter],
+// [Updater], [Doner], or [Committer], these are used as they would be
+// for a [Handler].
+func Stateful[T any](handler StatefulHandler[T]) Handler[T] {
+	return statefulHandler[T]{
+		handler: handler,
+	}
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+// [Depaginate] does not call this method, instead passing the [State]
+// directly to the wrapped [StatefulHandler]; if called, the wrapped
+// handler is passed a nil [State].
+func (sh statefulHandler[T]) Handle(ctx context.Context, idx int, item T) {
+	sh.handler.Handle(ctx, idx, item, nil)
+}
+
 // Starter is an interface that can be additionally implemented by
 // [Handler] implementations.  The Start method will be called before
 // [Depaginate] begins its work, allowing the [Handler] to implement
@@ -177,3 +345,131 @@ type DonerFunc func(ctx context.Context, totalItems, totalPages, perPage int)
 func (f DonerFunc) Done(ctx context.Context, totalItems, totalPages, perPage int) {
 	f(ctx, totalItems, totalPages, perPage)
 }
+
+// Committer is an interface that can be additionally implemented by
+// [Handler] implementations, such as those writing to a transactional
+// store.  Once all pages have been retrieved and all items have been
+// handled, and after any [Doner] has been called, [Depaginator.Wait]
+// calls Commit if the iteration completed without errors, or Rollback
+// otherwise.
+type Committer interface {
+	// Commit is called if the iteration completed without errors.
+	// Any error it returns is included in the errors returned by
+	// [Depaginator.Wait].
+	Commit(ctx context.Context) error
+
+	// Rollback is called if the iteration encountered errors or was
+	// canceled.
+	Rollback(ctx context.Context)
+}
+
+// pacer is an interface that can be additionally implemented by
+// [Handler] implementations which must not have pages retrieved
+// faster than their items can be handled, such as [SinkHandler].  The
+// retrieval of a page is held back while the number of pages being
+// retrieved or waiting to be handled is at the limit returned by
+// ahead.
+type pacer interface {
+	// ahead returns the maximum number of pages which may be
+	// retrieved ahead of the handling of their items.
+	ahead() int
+}
+
+// Scheduler is an interface for running the tasks started by the
+// [Depaginator], such as page retrievals and the handling of the
+// items in a page.  By default, each task runs in its own goroutine;
+// an alternative Scheduler may be set using the [WithScheduler]
+// option, allowing, for instance, tests to control the order in
+// which tasks run.  Note that the [Depaginator] still uses its own
+// goroutine to process updates.
+type Scheduler interface {
+	// Go runs a task.  The task need not run before Go returns, but
+	// it must eventually be run for the iteration to complete.  Go
+	// must not run the task synchronously, as it is called from the
+	// goroutine that processes the updates the task sends.
+	Go(task func())
+}
+
+// SchedulerFunc is a wrapper for a function matching the
+// [Scheduler.Go] signature.  The wrapper implements the [Scheduler]
+// interface, allowing a function to be passed instead of an
+// interface implementation.
+type SchedulerFunc func(task func())
+
+// Go runs a task.  The task need not run before Go returns, but it
+// must eventually be run for the iteration to complete.
+func (f SchedulerFunc) Go(task func()) {
+	f(task)
+}
+
+// Group is an interface for a group of goroutines whose errors are
+// collected, such as the Group type of the
+// golang.org/x/sync/errgroup package.  It is used by
+// [Depaginator.Go] to wait for an iteration as part of the group,
+// without this package depending on any particular implementation.
+type Group interface {
+	// Go calls the function in a new goroutine, collecting the
+	// error it returns.
+	Go(f func() error)
+}
+
+// Logger is an interface for logging the lifecycle of an iteration,
+// set using the [WithLifecycleLogger] option.  The methods take a
+// format string and arguments, as for [fmt.Printf], and may be called
+// concurrently.
+type Logger interface {
+	// Debugf logs routine events, such as the issuing of page
+	// requests, the completion of page retrievals, and the final
+	// totals.
+	Debugf(format string, args ...any)
+
+	// Warnf logs page retrieval errors, as well as the warnings
+	// otherwise passed to the function set by [WithLogger].
+	Warnf(format string, args ...any)
+}
+
+// Metrics is an interface for collecting metrics about an iteration,
+// set using the [WithMetrics] option, such as counters and histograms
+// for a metrics system.  The methods may be called concurrently, so
+// implementations must be safe for concurrent use.
+type Metrics interface {
+	// PageFetched is called after each call to
+	// [PageGetter.GetPage], including each retry, with the time the
+	// call took and the error it returned, if any.
+	PageFetched(dur time.Duration, err error)
+
+	// ItemHandled is called after each item is passed to the
+	// [Handler].
+	ItemHandled()
+
+	// Completed is called by [Depaginator.Wait] once the iteration
+	// is complete, with the final total number of items and pages.
+	Completed(totalItems, totalPages int)
+}
+
+// SpanName is the name of the span started by the [Tracer] for each
+// call to [PageGetter.GetPage].
+const SpanName = "depaginator.GetPage"
+
+// Tracer is an interface for starting spans tracing the retrieval of
+// pages, set using the [WithTracer] option.  The oteltrace
+// subpackage provides an implementation using an OpenTelemetry
+// tracer, which sets the "page.index" attribute on each span, and
+// records the error, if any, setting the error status before ending
+// the span.
+type Tracer interface {
+	// Start starts a span with the specified name for the
+	// retrieval of the page with the specified index.  It returns a
+	// context carrying the span, which is passed to
+	// [PageGetter.GetPage] so that the spans of downstream calls
+	// nest beneath it, along with the span.
+	Start(ctx context.Context, name string, pageIndex int) (context.Context, Span)
+}
+
+// Span is an interface for a span started by a [Tracer].
+type Span interface {
+	// End ends the span.  It is passed the error returned by
+	// [PageGetter.GetPage], which should be recorded on the span if
+	// it is not nil.
+	End(err error)
+}
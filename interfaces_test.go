@@ -18,7 +18,10 @@ package depaginator
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -53,6 +56,103 @@ func TestPageGetterFuncGetPage(t *testing.T) {
 	pager.AssertExpectations(t)
 }
 
+type mockPageGetterMeta struct {
+	mock.Mock
+}
+
+func (m *mockPageGetterMeta) GetPageMeta(ctx context.Context, req PageRequest) (Page[string], PageMeta, error) {
+	args := m.Called(ctx, req)
+
+	return args.Get(0).(Page[string]), args.Get(1).(PageMeta), args.Error(2)
+}
+
+func TestPageGetterMetaFuncImplementsPageGetterMeta(t *testing.T) {
+	assert.Implements(t, (*PageGetterMeta[string])(nil), PageGetterMetaFunc[string](nil))
+}
+
+func TestPageGetterMetaFuncGetPageMeta(t *testing.T) {
+	ctx := context.Background()
+	req := PageRequest{}
+	pager := &mockPageGetterMeta{}
+	pager.On("GetPageMeta", ctx, req).Return(Page[string]{Items: []string{"foo", "bar"}}, PageMeta{TotalItems: 5}, nil)
+	obj := PageGetterMetaFunc[string](pager.GetPageMeta)
+
+	page, meta, err := obj.GetPageMeta(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, Page[string]{Items: []string{"foo", "bar"}}, page)
+	assert.Equal(t, PageMeta{TotalItems: 5}, meta)
+	pager.AssertExpectations(t)
+}
+
+func TestMetaPager(t *testing.T) {
+	pager := &mockPageGetterMeta{}
+
+	result := MetaPager[string](pager)
+
+	assert.Equal(t, metaPager[string]{
+		pager: pager,
+	}, result)
+}
+
+func TestMetaPagerGetPageBase(t *testing.T) {
+	ctx := context.Background()
+	req := PageRequest{PageIndex: 0}
+	state := &mockState{}
+	state.On("Update", TotalItems(10), TotalPages(4), PerPage(3))
+	state.On("Request", 1, "one")
+	state.On("Request", 2, nil)
+	meta := PageMeta{
+		TotalItems: 10,
+		TotalPages: 4,
+		PerPage:    3,
+	}
+	meta.AddRequest(1, "one")
+	meta.AddRequest(2, nil)
+	pager := &mockPageGetterMeta{}
+	pager.On("GetPageMeta", ctx, req).Return(Page[string]{Items: []string{"foo", "bar", "baz"}}, meta, nil)
+	obj := MetaPager[string](pager)
+
+	result, err := obj.GetPage(ctx, state, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar", "baz"}, result)
+	state.AssertExpectations(t)
+	pager.AssertExpectations(t)
+}
+
+func TestMetaPagerGetPageNoMeta(t *testing.T) {
+	ctx := context.Background()
+	req := PageRequest{PageIndex: 3}
+	state := &mockState{}
+	pager := &mockPageGetterMeta{}
+	pager.On("GetPageMeta", ctx, req).Return(Page[string]{Items: []string{"foo"}}, PageMeta{}, nil)
+	obj := MetaPager[string](pager)
+
+	result, err := obj.GetPage(ctx, state, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestMetaPagerGetPageError(t *testing.T) {
+	ctx := context.Background()
+	req := PageRequest{PageIndex: 3}
+	state := &mockState{}
+	meta := PageMeta{TotalPages: 4}
+	meta.AddRequest(4, nil)
+	pager := &mockPageGetterMeta{}
+	pager.On("GetPageMeta", ctx, req).Return(Page[string]{}, meta, assert.AnError)
+	obj := MetaPager[string](pager)
+
+	result, err := obj.GetPage(ctx, state, req)
+
+	assert.Same(t, assert.AnError, err)
+	assert.Nil(t, result)
+	state.AssertExpectations(t)
+}
+
 type mockHandler struct {
 	mock.Mock
 }
@@ -76,6 +176,53 @@ func TestHandlerFuncHandle(t *testing.T) {
 	handler.AssertExpectations(t)
 }
 
+type mockStatefulHandler struct {
+	mock.Mock
+}
+
+func (m *mockStatefulHandler) Handle(ctx context.Context, idx int, item string, state State) {
+	m.Called(ctx, idx, item, state)
+}
+
+func TestStatefulHandlerFuncImplementsStatefulHandler(t *testing.T) {
+	assert.Implements(t, (*StatefulHandler[string])(nil), StatefulHandlerFunc[string](nil))
+}
+
+func TestStatefulHandlerFuncHandle(t *testing.T) {
+	ctx := context.Background()
+	state := &Depaginator[string]{}
+	handler := &mockStatefulHandler{}
+	handler.On("Handle", ctx, 5, "five", state)
+	obj := StatefulHandlerFunc[string](handler.Handle)
+
+	obj.Handle(ctx, 5, "five", state)
+
+	handler.AssertExpectations(t)
+}
+
+func TestStateful(t *testing.T) {
+	handler := &mockStatefulHandler{}
+
+	result := Stateful[string](handler)
+
+	assert.Equal(t, statefulHandler[string]{
+		handler: handler,
+	}, result)
+}
+
+func TestStatefulHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockStatefulHandler{}
+	handler.On("Handle", ctx, 5, "five", nil)
+	obj := statefulHandler[string]{
+		handler: handler,
+	}
+
+	obj.Handle(ctx, 5, "five")
+
+	handler.AssertExpectations(t)
+}
+
 type mockStarter struct {
 	mock.Mock
 }
@@ -145,6 +292,37 @@ func TestDonerFuncDone(t *testing.T) {
 	doner.AssertExpectations(t)
 }
 
+type mockCommitter struct {
+	mock.Mock
+}
+
+func (m *mockCommitter) Commit(ctx context.Context) error {
+	args := m.Called(ctx)
+
+	return args.Error(0)
+}
+
+func (m *mockCommitter) Rollback(ctx context.Context) {
+	m.Called(ctx)
+}
+
+func TestSchedulerFuncImplementsScheduler(t *testing.T) {
+	assert.Implements(t, (*Scheduler)(nil), SchedulerFunc(nil))
+}
+
+func TestSchedulerFuncGo(t *testing.T) {
+	called := false
+	obj := SchedulerFunc(func(task func()) {
+		task()
+	})
+
+	obj.Go(func() {
+		called = true
+	})
+
+	assert.True(t, called)
+}
+
 type mockHandlerFull struct {
 	mock.Mock
 }
@@ -164,3 +342,92 @@ func (m *mockHandlerFull) Update(ctx context.Context, totalItems, totalPages, pe
 func (m *mockHandlerFull) Done(ctx context.Context, totalItems, totalPages, perPage int) {
 	m.Called(ctx, totalItems, totalPages, perPage)
 }
+
+type spanKey struct{}
+
+type fakeSpan struct {
+	name      string
+	pageIndex int
+	ended     bool
+	err       error
+}
+
+func (s *fakeSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type fakeTracer struct {
+	sync.Mutex
+
+	spans []*fakeSpan
+}
+
+func (tr *fakeTracer) Start(ctx context.Context, name string, pageIndex int) (context.Context, Span) {
+	tr.Lock()
+	defer tr.Unlock()
+
+	span := &fakeSpan{
+		name:      name,
+		pageIndex: pageIndex,
+	}
+	tr.spans = append(tr.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+type fakeLogger struct {
+	sync.Mutex
+
+	debug []string
+	warn  []string
+}
+
+func (l *fakeLogger) Debugf(format string, args ...any) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.debug = append(l.debug, fmt.Sprintf(format, args...))
+}
+
+func (l *fakeLogger) Warnf(format string, args ...any) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.warn = append(l.warn, fmt.Sprintf(format, args...))
+}
+
+type fakeMetrics struct {
+	sync.Mutex
+
+	fetched    []error
+	durations  []time.Duration
+	handled    int
+	totalItems int
+	totalPages int
+	completed  int
+}
+
+func (m *fakeMetrics) PageFetched(dur time.Duration, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.fetched = append(m.fetched, err)
+	m.durations = append(m.durations, dur)
+}
+
+func (m *fakeMetrics) ItemHandled() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.handled++
+}
+
+func (m *fakeMetrics) Completed(totalItems, totalPages int) {
+	m.Lock()
+	defer m.Unlock()
+
+	m.totalItems = totalItems
+	m.totalPages = totalPages
+	m.completed++
+}
@@ -0,0 +1,67 @@
+//go:build go1.23
+
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over the items in a paginated response,
+// yielding the index of each item along with the item.  Each time the
+// iterator is ranged over, it calls [Depaginate] with the specified
+// [context.Context], [PageGetter], and options, and yields the items
+// as the pages are retrieved; as with [ReaderHandler], the items are
+// not guaranteed to be in index order.  If the range is stopped
+// early, the iteration is canceled, so that no further pages are
+// retrieved.  Errors are not reported by the iterator; applications
+// that must detect errors should use [Depaginate] directly, or pass
+// the [WithErrorHandler] option.
+func All[T any](ctx context.Context, pager PageGetter[T], opts ...Option) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// Construct a handler to feed the items to the iterator
+		items := make(chan indexedItem[T])
+		handler := HandlerFunc[T](func(_ context.Context, idx int, item T) {
+			items <- indexedItem[T]{
+				idx:  idx,
+				item: item,
+			}
+		})
+
+		// Run the iteration, closing the channel once complete
+		dp := Depaginate[T](ctx, pager, handler, opts...)
+		go func() {
+			_ = dp.Wait()
+			close(items)
+		}()
+
+		for it := range items {
+			if !yield(it.idx, it.item) {
+				// Cancel the iteration and drain the remaining items
+				cancel()
+				for range items {
+				}
+				return
+			}
+		}
+	}
+}
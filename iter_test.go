@@ -0,0 +1,81 @@
+//go:build go1.23
+
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllBase(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportPages: true,
+	}
+
+	result := make([]string, len(data.data))
+	idxs := []int{}
+	for idx, item := range All[string](ctx, data, WithAutoStrategy()) {
+		result[idx] = item
+		idxs = append(idxs, idx)
+	}
+
+	sort.Ints(idxs)
+	assert.Equal(t, data.data, result)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, idxs)
+}
+
+func TestAllStopEarly(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var canceled bool
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		if req.PageIndex == 0 {
+			depag.Update(TotalPages(3), PerPage(2))
+			depag.RequestRange(1, 3, nil)
+			return []string{"0", "1"}, nil
+		}
+
+		// Block until canceled
+		<-ctx.Done()
+		mu.Lock()
+		canceled = true
+		mu.Unlock()
+		return nil, ctx.Err()
+	})
+
+	count := 0
+	for range All[string](ctx, pager) {
+		count++
+		break
+	}
+
+	assert.Equal(t, 1, count)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.True(t, canceled)
+}
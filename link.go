@@ -0,0 +1,143 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// LinkPager is an implementation of [PageGetter] for HTTP APIs that
+// paginate using the Link response header, as described in RFC 8288
+// (formerly RFC 5988) and used by APIs such as GitHub and GitLab.
+// The first page is retrieved from the starting URL; each subsequent
+// page is retrieved from the URL in the Request field of its
+// [PageRequest], which is the target of the link with relation "next"
+// in the response for the previous page.  The page with no such link
+// is marked as the last page.  Response bodies are passed to a decode
+// function to obtain the items.  As every page but the last is
+// expected to be full, the number of items in the first page is
+// reported as the number of items per page, unless already known, and
+// the total number of items is reported with the last page.
+type LinkPager[T any] struct {
+	client *http.Client                   // The client to issue requests with
+	start  string                         // The URL of the first page
+	decode func(body []byte) ([]T, error) // Function to decode a response body
+}
+
+// NewLinkPager constructs a new [LinkPager] which retrieves pages
+// with the specified client, starting from the specified URL, and
+// decodes the response bodies with the specified decode function.  If
+// the client is nil, [http.DefaultClient] is used.
+func NewLinkPager[T any](client *http.Client, start string, decode func(body []byte) ([]T, error)) *LinkPager[T] {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &LinkPager[T]{
+		client: client,
+		start:  start,
+		decode: decode,
+	}
+}
+
+// GetPage is a page retriever function.  It retrieves the page from
+// the URL in the request, decodes the response body, and requests the
+// next page if the response links to one.
+func (lp *LinkPager[T]) GetPage(ctx context.Context, depag State, req PageRequest) ([]T, error) {
+	// Select the URL to retrieve
+	target, _ := req.Request.(string)
+	if target == "" {
+		target = lp.start
+	}
+
+	// Issue the request
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := lp.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s: %s", ErrUnexpectedStatus, target, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	items, err := lp.decode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Request the next page, resolving the link against the URL
+	// retrieved; the totals are known once the last page is reached
+	next := nextLink(resp.Header.Values("Link"))
+	perPage := depag.PerPage()
+	if req.PageIndex == 0 && next != "" && perPage == 0 && len(items) > 0 {
+		depag.Update(PerPage(len(items)))
+	}
+	if next == "" {
+		if req.PageIndex == 0 || perPage > 0 {
+			depag.Update(TotalItems(perPage*req.PageIndex + len(items)))
+		}
+		depag.MarkLast(req.PageIndex)
+	} else if nextURL, err := resp.Request.URL.Parse(next); err != nil {
+		return nil, err
+	} else {
+		depag.Request(req.PageIndex+1, nextURL.String())
+	}
+
+	return items, nil
+}
+
+// nextLink parses the values of the Link header and returns the target
+// of the link with relation "next", or the empty string if there is
+// none.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			params := strings.Split(link, ";")
+			target := strings.TrimSpace(params[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			// Look for the "rel" parameter, which may list several
+			// space-separated relations
+			for _, param := range params[1:] {
+				key, rels, ok := strings.Cut(param, "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(rels), `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
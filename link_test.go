@@ -0,0 +1,237 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeStrings(body []byte) ([]string, error) {
+	var items []string
+	err := json.Unmarshal(body, &items)
+	return items, err
+}
+
+func linkServer(pages [][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page int
+		if _, err := fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page); err != nil {
+			page = 0
+		}
+		if page >= len(pages) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if page+1 < len(pages) {
+			w.Header().Add("Link", fmt.Sprintf(`</items?page=%d>; rel="next", </items?page=%d>; rel="last"`, page+1, len(pages)-1))
+		}
+		_ = json.NewEncoder(w).Encode(pages[page])
+	}))
+}
+
+func TestLinkPagerImplementsPageGetter(t *testing.T) {
+	assert.Implements(t, (*PageGetter[string])(nil), &LinkPager[string]{})
+}
+
+func TestNewLinkPagerBase(t *testing.T) {
+	client := &http.Client{}
+
+	result := NewLinkPager[string](client, "http://example.com/items", decodeStrings)
+
+	assert.Same(t, client, result.client)
+	assert.Equal(t, "http://example.com/items", result.start)
+	assert.NotNil(t, result.decode)
+}
+
+func TestNewLinkPagerDefaultClient(t *testing.T) {
+	result := NewLinkPager[string](nil, "http://example.com/items", decodeStrings)
+
+	assert.Same(t, http.DefaultClient, result.client)
+}
+
+func TestLinkPagerGetPageFirst(t *testing.T) {
+	srv := linkServer([][]string{{"a", "b"}, {"c"}})
+	defer srv.Close()
+	obj := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	state := &mockState{}
+	state.On("PerPage").Return(0)
+	state.On("Update", PerPage(2))
+	state.On("Request", 1, srv.URL+"/items?page=1")
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestLinkPagerGetPageLast(t *testing.T) {
+	srv := linkServer([][]string{{"a", "b"}, {"c"}})
+	defer srv.Close()
+	obj := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	state := &mockState{}
+	state.On("PerPage").Return(2)
+	state.On("Update", TotalItems(3))
+	state.On("MarkLast", 1)
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{
+		PageIndex: 1,
+		Request:   srv.URL + "/items?page=1",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestLinkPagerGetPageLastPerPageUnknown(t *testing.T) {
+	srv := linkServer([][]string{{"a", "b"}, {"c"}})
+	defer srv.Close()
+	obj := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	state := &mockState{}
+	state.On("PerPage").Return(0)
+	state.On("MarkLast", 1)
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{
+		PageIndex: 1,
+		Request:   srv.URL + "/items?page=1",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestLinkPagerGetPageOnly(t *testing.T) {
+	srv := linkServer([][]string{{"a", "b"}})
+	defer srv.Close()
+	obj := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	state := &mockState{}
+	state.On("PerPage").Return(0)
+	state.On("Update", TotalItems(2))
+	state.On("MarkLast", 0)
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestLinkPagerGetPageStatus(t *testing.T) {
+	srv := linkServer([][]string{{"a", "b"}})
+	defer srv.Close()
+	obj := NewLinkPager[string](srv.Client(), srv.URL+"/items", decodeStrings)
+	state := &mockState{}
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{
+		PageIndex: 5,
+		Request:   srv.URL + "/items?page=5",
+	})
+
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.Nil(t, result)
+	state.AssertExpectations(t)
+}
+
+func TestLinkPagerGetPageDecodeError(t *testing.T) {
+	srv := linkServer([][]string{{"a", "b"}, {"c"}})
+	defer srv.Close()
+	decodeErr := errors.New("decode error")
+	obj := NewLinkPager[string](srv.Client(), srv.URL+"/items", func(body []byte) ([]string, error) {
+		return nil, decodeErr
+	})
+	state := &mockState{}
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{})
+
+	assert.Same(t, decodeErr, err)
+	assert.Nil(t, result)
+	state.AssertExpectations(t)
+}
+
+func TestLinkPagerGetPageBadURL(t *testing.T) {
+	obj := NewLinkPager[string](nil, "://bad", decodeStrings)
+	state := &mockState{}
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	state.AssertExpectations(t)
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		expect string
+	}{
+		{
+			name:   "none",
+			values: nil,
+		},
+		{
+			name:   "next only",
+			values: []string{`<https://example.com/items?page=2>; rel="next"`},
+			expect: "https://example.com/items?page=2",
+		},
+		{
+			name:   "several links",
+			values: []string{`<https://example.com/items?page=1>; rel="prev", <https://example.com/items?page=3>; rel="next", <https://example.com/items?page=9>; rel="last"`},
+			expect: "https://example.com/items?page=3",
+		},
+		{
+			name:   "several values",
+			values: []string{`<https://example.com/items?page=1>; rel="prev"`, `<https://example.com/items?page=3>; rel="next"`},
+			expect: "https://example.com/items?page=3",
+		},
+		{
+			name:   "several relations",
+			values: []string{`<https://example.com/items?page=3>; title="more"; REL="last next"`},
+			expect: "https://example.com/items?page=3",
+		},
+		{
+			name:   "unquoted",
+			values: []string{`</items?page=3>;rel=next`},
+			expect: "/items?page=3",
+		},
+		{
+			name:   "no next",
+			values: []string{`<https://example.com/items?page=1>; rel="prev"`},
+		},
+		{
+			name:   "malformed",
+			values: []string{`https://example.com/items?page=3; rel="next"`},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := nextLink(test.values)
+
+			assert.Equal(t, test.expect, result)
+		})
+	}
+}
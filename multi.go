@@ -0,0 +1,89 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "context"
+
+// MultiHandler is an implementation of [Handler] that passes each
+// item to every one of a number of member handlers, in order,
+// allowing a single iteration to feed several consumers; for
+// instance, to collect the items with a [ListHandler] while counting
+// them with a [CountingHandler].  Each item is forwarded with its
+// global index, so handlers that order items by index, such as
+// [ListHandler], produce the same ordering they would if used alone,
+// even though items are handled in the order pages arrive.  Calls to
+// [Starter.Start], [Updater.Update], and [Doner.Done] are forwarded
+// to those members implementing the corresponding interfaces, so
+// handlers that do and do not implement them may be mixed freely.  A
+// MultiHandler must be constructed with [NewMultiHandler].
+type MultiHandler[T any] struct {
+	handlers []Handler[T] // Member handlers
+}
+
+// NewMultiHandler constructs a [MultiHandler] that passes each item
+// to each of the specified handlers.
+func NewMultiHandler[T any](handlers ...Handler[T]) *MultiHandler[T] {
+	return &MultiHandler[T]{
+		handlers: handlers,
+	}
+}
+
+// Tee returns a [Handler] that passes each item to every one of the
+// specified handlers.  It is equivalent to [NewMultiHandler].
+func Tee[T any](handlers ...Handler[T]) Handler[T] {
+	return NewMultiHandler(handlers...)
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (mh *MultiHandler[T]) Start(ctx context.Context, totalItems, totalPages, perPage int) {
+	for _, h := range mh.handlers {
+		if starter, ok := h.(Starter); ok {
+			starter.Start(ctx, totalItems, totalPages, perPage)
+		}
+	}
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (mh *MultiHandler[T]) Handle(ctx context.Context, idx int, item T) {
+	for _, h := range mh.handlers {
+		h.Handle(ctx, idx, item)
+	}
+}
+
+// Update is called with the new values of total items, total pages,
+// and items per page.  It should not undertake extensive processing.
+func (mh *MultiHandler[T]) Update(ctx context.Context, totalItems, totalPages, perPage int) {
+	for _, h := range mh.handlers {
+		if updater, ok := h.(Updater); ok {
+			updater.Update(ctx, totalItems, totalPages, perPage)
+		}
+	}
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (mh *MultiHandler[T]) Done(ctx context.Context, totalItems, totalPages, perPage int) {
+	for _, h := range mh.handlers {
+		if doner, ok := h.(Doner); ok {
+			doner.Done(ctx, totalItems, totalPages, perPage)
+		}
+	}
+}
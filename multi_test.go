@@ -0,0 +1,162 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &MultiHandler[string]{})
+	assert.Implements(t, (*Starter)(nil), &MultiHandler[string]{})
+	assert.Implements(t, (*Updater)(nil), &MultiHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &MultiHandler[string]{})
+}
+
+func TestNewMultiHandler(t *testing.T) {
+	h1 := &mockHandler{}
+	h2 := &mockHandlerFull{}
+
+	result := NewMultiHandler[string](h1, h2)
+
+	assert.Equal(t, []Handler[string]{h1, h2}, result.handlers)
+}
+
+func TestTee(t *testing.T) {
+	h1 := &mockHandler{}
+
+	result := Tee[string](h1)
+
+	assert.Equal(t, &MultiHandler[string]{handlers: []Handler[string]{h1}}, result)
+}
+
+func TestTeeImplementsInterfaces(t *testing.T) {
+	obj := Tee[string]()
+
+	assert.Implements(t, (*Starter)(nil), obj)
+	assert.Implements(t, (*Updater)(nil), obj)
+	assert.Implements(t, (*Doner)(nil), obj)
+}
+
+func TestMultiHandlerStart(t *testing.T) {
+	ctx := context.Background()
+	h1 := &mockHandler{}
+	h2 := &mockHandlerFull{}
+	h2.On("Start", ctx, 1, 2, 3)
+	obj := NewMultiHandler[string](h1, h2)
+
+	obj.Start(ctx, 1, 2, 3)
+
+	h1.AssertExpectations(t)
+	h2.AssertExpectations(t)
+}
+
+func TestMultiHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	h1 := &mockHandler{}
+	h1.On("Handle", ctx, 5, "five")
+	h2 := &mockHandlerFull{}
+	h2.On("Handle", ctx, 5, "five")
+	obj := NewMultiHandler[string](h1, h2)
+
+	obj.Handle(ctx, 5, "five")
+
+	h1.AssertExpectations(t)
+	h2.AssertExpectations(t)
+}
+
+func TestMultiHandlerUpdate(t *testing.T) {
+	ctx := context.Background()
+	h1 := &mockHandler{}
+	h2 := &mockHandlerFull{}
+	h2.On("Update", ctx, 1, 2, 3)
+	obj := NewMultiHandler[string](h1, h2)
+
+	obj.Update(ctx, 1, 2, 3)
+
+	h1.AssertExpectations(t)
+	h2.AssertExpectations(t)
+}
+
+func TestMultiHandlerDone(t *testing.T) {
+	ctx := context.Background()
+	h1 := &mockHandler{}
+	h2 := &mockHandlerFull{}
+	h2.On("Done", ctx, 1, 2, 3)
+	obj := NewMultiHandler[string](h1, h2)
+
+	obj.Done(ctx, 1, 2, 3)
+
+	h1.AssertExpectations(t)
+	h2.AssertExpectations(t)
+}
+
+func TestTeeDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	pager := PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		// Later pages arrive first
+		time.Sleep(time.Duration(4-req.PageIndex) * time.Millisecond)
+		return data.GetPage(ctx, depag, req)
+	})
+	first := &ListHandler[string]{}
+	second := &ListHandler[string]{}
+
+	d := Depaginate[string](ctx, pager, Tee[string](first, second), WithAutoStrategy(), TotalPages(4))
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, first.Items)
+	assert.Equal(t, data.data, second.Items)
+}
+
+func TestMultiHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	list := &ListHandler[string]{}
+	count := &CountingHandler[string]{}
+	var mu sync.Mutex
+	var seen []int
+	plain := HandlerFunc[string](func(_ context.Context, idx int, _ string) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, idx)
+	})
+
+	d := Depaginate[string](ctx, data, NewMultiHandler[string](list, count, plain), WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, list.Items)
+	assert.Equal(t, 11, count.Total)
+	assert.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, seen)
+}
@@ -0,0 +1,43 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "context"
+
+// NewOffsetPageGetter constructs a [PageGetter] for APIs that
+// paginate using an offset and a limit, such as those accepting
+// "?offset=&limit=" query parameters.  The page size is the specified
+// limit, which is reported to the [Depaginator] as the number of
+// items per page; each page is retrieved by calling the fetch
+// function with the offset of the page's first item.  The next page
+// is requested after each full page, so pages are retrieved one at a
+// time until a short page is returned.
+func NewOffsetPageGetter[T any](fetch func(ctx context.Context, offset, limit int) ([]T, error), limit int) PageGetter[T] {
+	return PageGetterFunc[T](func(ctx context.Context, depag State, req PageRequest) ([]T, error) {
+		depag.Update(PerPage(limit))
+
+		items, err := fetch(ctx, req.PageIndex*limit, limit)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) >= limit {
+			depag.Request(req.PageIndex+1, nil)
+		}
+
+		return items, nil
+	})
+}
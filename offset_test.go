@@ -0,0 +1,71 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOffsetPageGetterFull(t *testing.T) {
+	var offset, limit int
+	obj := NewOffsetPageGetter[string](func(_ context.Context, o, l int) ([]string, error) {
+		offset, limit = o, l
+		return []string{"a", "b", "c"}, nil
+	}, 3)
+	state := &mockState{}
+	state.On("Update", PerPage(3))
+	state.On("Request", 3, nil)
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{PageIndex: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, result)
+	assert.Equal(t, 6, offset)
+	assert.Equal(t, 3, limit)
+	state.AssertExpectations(t)
+}
+
+func TestNewOffsetPageGetterShort(t *testing.T) {
+	obj := NewOffsetPageGetter[string](func(_ context.Context, o, l int) ([]string, error) {
+		return []string{"a"}, nil
+	}, 3)
+	state := &mockState{}
+	state.On("Update", PerPage(3))
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{PageIndex: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestNewOffsetPageGetterError(t *testing.T) {
+	obj := NewOffsetPageGetter[string](func(_ context.Context, o, l int) ([]string, error) {
+		return []string{"a", "b", "c"}, assert.AnError
+	}, 3)
+	state := &mockState{}
+	state.On("Update", PerPage(3))
+
+	result, err := obj.GetPage(context.Background(), state, PageRequest{PageIndex: 2})
+
+	assert.Same(t, assert.AnError, err)
+	assert.Nil(t, result)
+	state.AssertExpectations(t)
+}
@@ -0,0 +1,56 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"fmt"
+	"io"
+)
+
+// metricsText is the template for the output of
+// [Depaginator.WriteMetrics].
+const metricsText = `# HELP depaginator_pages_fetched Number of page retrievals completed.
+# TYPE depaginator_pages_fetched counter
+depaginator_pages_fetched_total %d
+# HELP depaginator_errors Number of page retrievals that failed.
+# TYPE depaginator_errors counter
+depaginator_errors_total %d
+# HELP depaginator_items_handled Number of items handled.
+# TYPE depaginator_items_handled counter
+depaginator_items_handled_total %d
+# HELP depaginator_pages_in_flight Number of page retrievals in progress.
+# TYPE depaginator_pages_in_flight gauge
+depaginator_pages_in_flight %d
+# EOF
+`
+
+// WriteMetrics writes the counters describing the iteration to the
+// specified [io.Writer] in the OpenMetrics text exposition format,
+// which is also understood by Prometheus.  This allows, for instance,
+// an HTTP handler to expose the state of a running iteration.  It may
+// be called at any time from any goroutine.
+func (dp *Depaginator[T]) WriteMetrics(w io.Writer) error {
+	var fetched, errs, inFlight int
+	dp.snapshot(func(depag *Depaginator[T]) {
+		fetched = depag.fetched
+		errs = len(depag.errors)
+		inFlight = len(depag.cancelers)
+	})
+
+	_, err := fmt.Fprintf(w, metricsText, fetched, errs, dp.handled.Load(), inFlight)
+	return err
+}
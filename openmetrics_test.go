@@ -0,0 +1,102 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, assert.AnError
+}
+
+func TestDepaginatorWriteMetricsBase(t *testing.T) {
+	obj := &Depaginator[string]{
+		errors:  []error{assert.AnError},
+		fetched: 4,
+		cancelers: map[int]context.CancelFunc{
+			5: func() {},
+			6: func() {},
+		},
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	obj.handled.Store(11)
+	close(obj.done)
+	buf := &bytes.Buffer{}
+
+	err := obj.WriteMetrics(buf)
+
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Contains(t, lines, "depaginator_pages_fetched_total 4")
+	assert.Contains(t, lines, "depaginator_errors_total 1")
+	assert.Contains(t, lines, "depaginator_items_handled_total 11")
+	assert.Contains(t, lines, "depaginator_pages_in_flight 2")
+	assert.Equal(t, "# EOF", lines[len(lines)-1])
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			fields := strings.Fields(line)
+			require.GreaterOrEqual(t, len(fields), 2, line)
+			assert.Contains(t, []string{"HELP", "TYPE", "EOF"}, fields[1], line)
+			continue
+		}
+		assert.Len(t, strings.Fields(line), 2, line)
+	}
+}
+
+func TestDepaginatorWriteMetricsError(t *testing.T) {
+	obj := &Depaginator[string]{
+		updates: make(chan update[string]),
+		done:    make(chan struct{}),
+	}
+	close(obj.done)
+
+	err := obj.WriteMetrics(failWriter{})
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestDepaginatorWriteMetricsRun(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+
+	d := Depaginate[string](ctx, data, &ListHandler[string]{})
+	err := d.Wait()
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	err = d.WriteMetrics(buf)
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "depaginator_pages_fetched_total 6\n")
+	assert.Contains(t, buf.String(), "depaginator_items_handled_total 11\n")
+	assert.Contains(t, buf.String(), "depaginator_pages_in_flight 0\n")
+}
@@ -19,21 +19,216 @@ package depaginator
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // DefaultCapacity is the default capacity for the updates channel.
 const DefaultCapacity = 500
 
+// CompactWindow is the number of pages below the highest page
+// requested for which duplicate requests are still detected when the
+// [WithCompactPageMap] option is used.
+const CompactWindow = 4096
+
+// Polling intervals for the health gate set by [WithHealthGate].
+const (
+	HealthPollMin = 10 * time.Millisecond // Initial polling interval
+	HealthPollMax = time.Second           // Maximum polling interval
+)
+
+// Inference modes, which control whether totals inferred from a short
+// page replace the totals already known.
+const (
+	inferLower  = iota // Inferred totals replace larger totals
+	inferAlways        // Inferred totals always replace totals
+	inferNever         // Inferred totals never replace totals
+)
+
 // options describes options for [Depaginate].
 type options struct {
-	totalItems int     // Total number of items (hint)
-	totalPages int     // Total number of pages (hint)
-	perPage    int     // Number of items per page
-	capacity   int     // Capacity of the update queue
-	starter    Starter // Object with a Start method
-	updater    Updater // Object with an Update method
-	doner      Doner   // Object with a Done method
-	initReq    any     // Initial request
+	totalItems int                                        // Total number of items (hint)
+	totalPages int                                        // Total number of pages (hint)
+	perPage    int                                        // Number of items per page
+	capacity   int                                        // Capacity of the update queue
+	starter    Starter                                    // Object with a Start method
+	updater    Updater                                    // Object with an Update method
+	doner      Doner                                      // Object with a Done method
+	committer  Committer                                  // Object with Commit and Rollback methods
+	initReq    any                                        // Initial request
+	auto       bool                                       // Use the automatic fetch strategy
+	partial    bool                                       // Report partial results on cancellation
+	failFast   bool                                       // Stop the iteration on the first error
+	maxErrors  int                                        // Maximum page errors tolerated
+	dryRun     bool                                       // Plan the pages without retrieving them
+	inOrder    bool                                       // Handle the pages in strict page order
+	checkpoint []byte                                     // Checkpoint of a previous iteration
+	recordCP   bool                                       // Record the pages handled for checkpoints
+	encodeReq  func(any) ([]byte, error)                  // Function to encode requests in checkpoints
+	decodeReq  func([]byte) (any, error)                  // Function to decode requests from checkpoints
+	summary    func(RunResult)                            // Function to call with the summary
+	scheduler  Scheduler                                  // Object to run tasks with
+	budget     int64                                      // Maximum bytes to fetch
+	sizeOf     any                                        // Function to compute the size of a page
+	ready      chan<- struct{}                            // Channel to close once running
+	postPage   any                                        // Function to call after each page
+	inference  int                                        // Inference mode
+	workers    int                                        // Number of item handling workers
+	maxPages   int                                        // Maximum number of pages to retrieve
+	activity   time.Duration                              // Maximum time between retrieved pages
+	timeout    time.Duration                              // Maximum time to handle an item
+	stream     bool                                       // Deliver pages on a channel
+	decorator  func(PageRequest) PageRequest              // Function to decorate requests
+	overlap    func(PageRequest, PageRequest) PageRequest // Function to resolve overlapping requests
+	completion func(int)                                  // Function to call as items complete in order
+	tracer     Tracer                                     // Tracer for page retrievals
+	healthy    func() bool                                // Function to check downstream health
+	recorder   func(string)                               // Function to record updates
+	gate       func(int, Totals) bool                     // Function to gate item handling
+	onError    errorHook                                  // Function to call with page errors
+	ctxErrors  bool                                       // Call onError for context errors too
+	allowEmpty bool                                       // Empty pages do not end the iteration
+	compact    bool                                       // Compact the map of requested pages
+	batch      bool                                       // Bundle updates and requests until GetPage returns
+	maxActive  int                                        // Maximum concurrent page retrievals
+	limiter    *rate.Limiter                              // Rate limiter for page retrievals
+	result     any                                        // Function to call with all the items
+	limit      int                                        // Maximum number of items to handle
+	attempts   int                                        // Maximum attempts to retrieve a page
+	backoff    func(int) time.Duration                    // Function to compute retry delays
+	retries    int                                        // Maximum retries across all pages
+	order      func(int) []int                            // Function to order items within a page
+	logger     func(string)                               // Function to log warnings
+	lifecycle  Logger                                     // Logger for the iteration lifecycle
+	metrics    Metrics                                    // Collector of metrics
+	sampling   time.Duration                              // Interval between concurrency samples
+	sampler    func(int)                                  // Function to call with concurrency samples
+}
+
+// conflicts is the matrix of options that may not be combined.  Each
+// entry names a pair of options and a function reporting whether both
+// have been set.
+var conflicts = []struct {
+	first  string                // Name of the first option
+	second string                // Name of the second option
+	check  func(o *options) bool // Reports whether both are set
+}{
+	{
+		// FailFast stops on the first error, contradicting the number
+		// of errors WithMaxErrorsBeforeAbort tolerates
+		first:  "FailFast",
+		second: "WithMaxErrorsBeforeAbort",
+		check: func(o *options) bool {
+			return o.failFast && o.maxErrors > 0
+		},
+	},
+	{
+		// A dry run retrieves no pages, so a consumer of the page
+		// stream would wait for pages that never arrive
+		first:  "WithDryRun",
+		second: "WithPageStream",
+		check: func(o *options) bool {
+			return o.dryRun && o.stream
+		},
+	},
+	{
+		// A dry run retrieves no pages, so there is nothing to retry
+		first:  "WithDryRun",
+		second: "WithRetry",
+		check: func(o *options) bool {
+			return o.dryRun && o.attempts > 1
+		},
+	},
+	{
+		first:  "WithDryRun",
+		second: "WithRetryBudget",
+		check: func(o *options) bool {
+			return o.dryRun && o.retries > 0
+		},
+	},
+}
+
+// validate checks the options against the matrix of conflicting
+// options, returning an error wrapping [ErrConflictingOptions] for
+// the first conflict found.
+func (o *options) validate() error {
+	for _, conflict := range conflicts {
+		if conflict.check(o) {
+			return fmt.Errorf("%w: %s may not be combined with %s", ErrConflictingOptions, conflict.first, conflict.second)
+		}
+	}
+
+	return nil
+}
+
+// mismatch returns an error wrapping [ErrConflictingOptions] which
+// reports that the function passed to a generic option does not
+// accept the items of the iteration, because the option was
+// instantiated with a different item type.
+func mismatch[T any](option string, fn any) error {
+	return fmt.Errorf("%w: %s function %T does not accept items of type %T", ErrConflictingOptions, option, fn, []T(nil))
+}
+
+// config returns the effective configuration described by the
+// options.
+func (o *options) config() Config {
+	attempts := o.attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return Config{
+		TotalItems:        o.totalItems,
+		TotalPages:        o.totalPages,
+		PerPage:           o.perPage,
+		Capacity:          o.capacity,
+		MaxConcurrency:    o.maxActive,
+		HandleConcurrency: o.workers,
+		RetryAttempts:     attempts,
+		RetryBudget:       o.retries,
+		Limit:             o.limit,
+		AutoStrategy:      o.auto,
+		FailFast:          o.failFast,
+	}
+}
+
+// reconcile reconciles the hints for the total number of items, the
+// total number of pages, and the number of items per page, returning
+// the totals to report to the handler for preallocation.  If all
+// three hints are given but are mutually inconsistent, the most
+// conservative values are chosen: if there are more items than the
+// pages can hold, the number of items is reduced to the number the
+// pages can hold, and if there are too few items to reach the last
+// page, the number of pages is reduced to the number the items fill.
+// A warning is logged for each adjustment.
+func (o *options) reconcile() (totalItems, totalPages int) {
+	totalItems, totalPages = o.totalItems, o.totalPages
+	if totalItems <= 0 || totalPages <= 0 || o.perPage <= 0 {
+		return totalItems, totalPages
+	}
+
+	if capacity := totalPages * o.perPage; totalItems > capacity {
+		o.warn(fmt.Sprintf("inconsistent hints: %d items do not fit in %d pages of %d items; assuming %d items", totalItems, totalPages, o.perPage, capacity))
+		totalItems = capacity
+	} else if needed := (totalItems + o.perPage - 1) / o.perPage; needed < totalPages {
+		o.warn(fmt.Sprintf("inconsistent hints: %d items fill only %d of %d pages of %d items; assuming %d pages", totalItems, needed, totalPages, o.perPage, needed))
+		totalPages = needed
+	}
+
+	return totalItems, totalPages
+}
+
+// warn logs a warning, if a logger has been set by [WithLogger] or
+// [WithLifecycleLogger].
+func (o *options) warn(msg string) {
+	if o.logger != nil {
+		o.logger(msg)
+	}
+	if o.lifecycle != nil {
+		o.lifecycle.Warnf("%s", msg)
+	}
 }
 
 // Option describes an option that may be passed to [Depaginate].
@@ -44,7 +239,16 @@ type Option interface {
 
 // TotalItems is used to indicate an update to the total number of
 // items to be expected.  It may also be passed to [Depaginate] to
-// hint to the total number of items to be expected.
+// hint to the total number of items to be expected.  If the
+// [TotalItems], [TotalPages], and [PerPage] hints are all passed to
+// [Depaginate] but are inconsistent, the most conservative totals are
+// reported to [Starter.Start], so that handlers do not over-allocate:
+// if the pages cannot hold that many items, the number of items is
+// reduced to the number the pages can hold, and if the items do not
+// reach the last page, the number of pages is reduced to the number
+// the items fill.  A warning is logged through [WithLogger] in either
+// case.  The hints themselves are not altered, and are corrected as
+// pages are retrieved.
 type TotalItems int
 
 // apply applies an option.
@@ -84,6 +288,49 @@ func (o Capacity) apply(opts *options) {
 	opts.capacity = int(o)
 }
 
+// MaxConcurrency may be passed to [Depaginate] to limit the number of
+// calls to [PageGetter.GetPage] that may be in progress at once.
+// Pages requested while the limit is reached wait for an earlier
+// page retrieval to complete.  By default, or if the value is 0 or
+// less, the number of concurrent page retrievals is not limited.
+type MaxConcurrency int
+
+// apply applies an option.
+func (o MaxConcurrency) apply(opts *options) {
+	opts.maxActive = int(o)
+}
+
+// MaxPages may be passed to [Depaginate] to limit the number of pages
+// retrieved.  Pages with an index of n or greater are never requested,
+// even if the total number of pages reported is larger; if a smaller
+// total number of pages is reported, that is respected instead.  This
+// is useful for sampling the first few pages of an API, or as a guard
+// against an API reporting an absurd number of pages.  The totals
+// reported to the [Doner] are reduced to those of the pages retrieved
+// if required.  By default, or if the value is 0 or less, the number
+// of pages is not limited.
+type MaxPages int
+
+// apply applies an option.
+func (o MaxPages) apply(opts *options) {
+	opts.maxPages = int(o)
+}
+
+// MaxItems may be passed to [Depaginate] to limit the number of items
+// handled.  It is equivalent to [WithLimit], and is provided alongside
+// [MaxPages] for symmetry: items with an index of n or greater are not
+// passed to the [Handler], pages beginning at or beyond the limit are
+// not retrieved once the number of items per page is known, and the
+// total number of items reported to the [Doner] is reduced to the
+// limit if required.  By default, or if the value is 0 or less, the
+// number of items is not limited.
+type MaxItems int
+
+// apply applies an option.
+func (o MaxItems) apply(opts *options) {
+	opts.limit = int(o)
+}
+
 // WithStarterOption is an [Option] implementation that explicitly
 // sets the [Starter] to use.
 type WithStarterOption struct {
@@ -147,6 +394,26 @@ func WithDoner(doner Doner) WithDonerOption {
 	}
 }
 
+// WithCommitterOption is an [Option] implementation that explicitly
+// sets the [Committer] to use.
+type WithCommitterOption struct {
+	committer Committer
+}
+
+// apply applies an option.
+func (o WithCommitterOption) apply(opts *options) {
+	opts.committer = o.committer
+}
+
+// WithCommitter returns an [Option] that can be passed to [Depaginate]
+// which sets a [Committer] to be called once all pages are retrieved.
+// The default is the [Handler], if it implements [Committer].
+func WithCommitter(committer Committer) WithCommitterOption {
+	return WithCommitterOption{
+		committer: committer,
+	}
+}
+
 // WithRequestOption is an [Option] implementation that sets the
 // initial request.
 type WithRequestOption struct {
@@ -166,6 +433,1004 @@ func WithRequest(req any) WithRequestOption {
 	}
 }
 
+// WithAutoStrategyOption is an [Option] implementation that enables
+// the automatic fetch strategy.
+type WithAutoStrategyOption struct{}
+
+// apply applies an option.
+func (o WithAutoStrategyOption) apply(opts *options) {
+	opts.auto = true
+}
+
+// WithAutoStrategy returns an [Option] which enables the automatic
+// fetch strategy.  With this strategy, the [Depaginator] requests
+// additional pages itself: once the total number of pages is known
+// (typically because [PageGetter.GetPage] reported it for page 0 via
+// [State.Update]), all remaining pages are requested at once;
+// otherwise, each full page causes the next page to be requested, a
+// page at a time, until a short page is encountered.  Page requests
+// issued this way carry a nil request.
+func WithAutoStrategy() WithAutoStrategyOption {
+	return WithAutoStrategyOption{}
+}
+
+// WithPartialResultsOption is an [Option] implementation that enables
+// partial results on cancellation.
+type WithPartialResultsOption struct{}
+
+// apply applies an option.
+func (o WithPartialResultsOption) apply(opts *options) {
+	opts.partial = true
+}
+
+// WithPartialResults returns an [Option] which alters the total
+// number of items reported to the [Doner] if the context passed to
+// [Depaginate] is canceled before the iteration completes.  Instead
+// of the total number of items reported by the [PageGetter], the
+// [Doner] will be passed the number of items in the contiguous run
+// of pages, starting with the first page, that were actually
+// retrieved.  For [ListHandler], this means that the Items field will
+// contain only the items that were actually collected, with no gaps.
+func WithPartialResults() WithPartialResultsOption {
+	return WithPartialResultsOption{}
+}
+
+// FailFastOption is an [Option] implementation that stops the
+// iteration on the first error.
+type FailFastOption struct{}
+
+// apply applies an option.
+func (o FailFastOption) apply(opts *options) {
+	opts.failFast = true
+}
+
+// FailFast returns an [Option] which stops the iteration as soon as
+// any page retrieval fails with an error other than a context error.
+// All in-flight page retrievals are canceled, no further pages are
+// requested, and the items of pages retrieved after the failure are
+// not handled, so [Depaginator.Wait] returns promptly with the error.
+// Items already handled are not affected.  This is intended for uses
+// where any error invalidates the whole result.
+func FailFast() FailFastOption {
+	return FailFastOption{}
+}
+
+// WithMaxErrorsBeforeAbortOption is an [Option] implementation that
+// stops the iteration once too many page errors have occurred.
+type WithMaxErrorsBeforeAbortOption int
+
+// apply applies an option.
+func (o WithMaxErrorsBeforeAbortOption) apply(opts *options) {
+	opts.maxErrors = int(o)
+}
+
+// WithMaxErrorsBeforeAbort returns an [Option] which tolerates up to
+// n page retrieval errors, but stops the iteration, as [FailFast]
+// would, once the error after those is recorded.  This suits APIs
+// where a few flaky pages are acceptable, but many failures indicate
+// a systemic problem.  As with [FailFast], context errors are not
+// counted.  A value of 0 or less disables the limit; use [FailFast]
+// to stop on the first error.
+func WithMaxErrorsBeforeAbort(n int) WithMaxErrorsBeforeAbortOption {
+	return WithMaxErrorsBeforeAbortOption(n)
+}
+
+// WithDryRunOption is an [Option] implementation that plans the
+// pages to retrieve without retrieving them.
+type WithDryRunOption struct{}
+
+// apply applies an option.
+func (o WithDryRunOption) apply(opts *options) {
+	opts.dryRun = true
+}
+
+// WithDryRun returns an [Option] which plans the iteration without
+// retrieving any pages: [PageGetter.GetPage] is never called, and no
+// items are handled.  Instead, each page that would be retrieved is
+// recorded, and the remaining pages are requested as implied by the
+// total number of pages or, failing that, by the total number of
+// items and the number of items per page.  The planned pages may be
+// obtained with [Depaginator.PlannedPages] once [Depaginator.Wait]
+// has returned.  As the [PageGetter] is not consulted, the totals
+// must be provided through the [TotalPages], [TotalItems], and
+// [PerPage] options; otherwise, only the first page is planned.  This
+// is intended for validating the configuration of an iteration
+// without calling the API.
+func WithDryRun() WithDryRunOption {
+	return WithDryRunOption{}
+}
+
+// InOrderOption is an [Option] implementation that handles the pages
+// in strict page order.
+type InOrderOption struct{}
+
+// apply applies an option.
+func (o InOrderOption) apply(opts *options) {
+	opts.inOrder = true
+}
+
+// InOrder returns an [Option] which handles items in strict order of
+// their index.  By default, the items of each page are handled as soon
+// as the page is retrieved, so [Handler.Handle] may be called for the
+// items of page 3 before those of page 1.  With this option, the
+// daemon holds retrieved pages back, releasing a page for handling
+// only once the pages with lower indexes have been handled or have
+// failed to be retrieved, and handling one page at a time.  Note that
+// the pages held back remain in memory until released; if an early
+// page is slow to retrieve, every page retrieved meanwhile is
+// buffered, which may be considerable for large iterations.  Pages
+// are still retrieved concurrently, subject to [MaxConcurrency].
+func InOrder() InOrderOption {
+	return InOrderOption{}
+}
+
+// WithCheckpointOption is an [Option] implementation that resumes an
+// iteration from a checkpoint.
+type WithCheckpointOption struct {
+	checkpoint []byte
+}
+
+// apply applies an option.
+func (o WithCheckpointOption) apply(opts *options) {
+	opts.checkpoint = o.checkpoint
+	opts.recordCP = true
+}
+
+// WithCheckpoint returns an [Option] which resumes an interrupted
+// iteration from a checkpoint obtained from
+// [Depaginator.Checkpoint].  Pages whose items were handled by the
+// previous iteration are not requested again.  The first page is an
+// exception, as it is needed to discover the remaining pages; it is
+// still retrieved, but its items are not handled again.  Note that a
+// skipped page cannot request further pages, so the remaining pages
+// must be requested independently of the skipped ones: for example,
+// by reporting the total number of pages on the first page, or by
+// passing the totals to [Depaginate] with the [WithAutoStrategy]
+// option.  Combined with a [Handler] that stores the items by index
+// in a store persisted along with the checkpoint, this allows a large
+// iteration to survive interruption.  If the checkpoint cannot
+// be decoded, no pages are retrieved, and [Depaginator.Wait] returns
+// an error wrapping [ErrInvalidCheckpoint].  This option also enables
+// the recording of the pages handled, which [Depaginator.Checkpoint]
+// requires; pass a nil checkpoint to record them without resuming a
+// previous iteration.
+func WithCheckpoint(checkpoint []byte) WithCheckpointOption {
+	return WithCheckpointOption{
+		checkpoint: checkpoint,
+	}
+}
+
+// WithRequestCodecOption is an [Option] implementation that sets the
+// functions to encode and decode page requests in checkpoints.
+type WithRequestCodecOption struct {
+	encode func(req any) ([]byte, error)
+	decode func(data []byte) (any, error)
+}
+
+// apply applies an option.
+func (o WithRequestCodecOption) apply(opts *options) {
+	opts.encodeReq = o.encode
+	opts.decodeReq = o.decode
+	opts.recordCP = true
+}
+
+// WithRequestCodec returns an [Option] which sets the functions used
+// to encode and decode the Request field of [PageRequest] in
+// checkpoints.  Ordinarily, a checkpoint records only the indexes of
+// the pages whose items have been handled, so the remaining pages
+// must be rediscovered when the iteration is resumed.  With this
+// option, [Depaginator.Checkpoint] also records the encoded requests
+// of the pages that have been requested but not yet handled,
+// excluding those whose request is nil, and the iteration resumed
+// with [WithCheckpoint] requests those pages again with the decoded
+// requests.  This allows iterations over cursor-based APIs, where a
+// page can only be retrieved with data obtained from the previous
+// page, to be resumed.  A request that cannot be encoded is omitted
+// from the checkpoint; the encode function is called from the
+// goroutine that processes updates, so it should be quick.  If a
+// request in the checkpoint cannot be decoded, or the checkpoint
+// contains requests but no decode function is set, no pages are
+// retrieved, and [Depaginator.Wait] returns an error wrapping
+// [ErrInvalidCheckpoint].  As with [WithCheckpoint], this option
+// enables the recording of the pages handled.
+func WithRequestCodec(encode func(req any) ([]byte, error), decode func(data []byte) (any, error)) WithRequestCodecOption {
+	return WithRequestCodecOption{
+		encode: encode,
+		decode: decode,
+	}
+}
+
+// WithSummaryOption is an [Option] implementation that sets a
+// function to call with the summary of the iteration.
+type WithSummaryOption struct {
+	summary func(RunResult)
+}
+
+// apply applies an option.
+func (o WithSummaryOption) apply(opts *options) {
+	opts.summary = o.summary
+}
+
+// WithSummary returns an [Option] which sets a function to be called
+// once by [Depaginator.Wait] with the [RunResult] summarizing the
+// iteration.  This provides a single record of the iteration,
+// suitable for logging or metrics.  The function is called even if
+// errors were encountered.
+func WithSummary(summary func(RunResult)) WithSummaryOption {
+	return WithSummaryOption{
+		summary: summary,
+	}
+}
+
+// WithSchedulerOption is an [Option] implementation that sets the
+// [Scheduler] to use.
+type WithSchedulerOption struct {
+	scheduler Scheduler
+}
+
+// apply applies an option.
+func (o WithSchedulerOption) apply(opts *options) {
+	opts.scheduler = o.scheduler
+}
+
+// WithScheduler returns an [Option] which sets the [Scheduler] used
+// to run page retrievals and item handling.  By default, each such
+// task is run in its own goroutine.  This is primarily intended for
+// testing, where a deterministic [Scheduler] allows a particular
+// ordering of events to be reproduced reliably.
+func WithScheduler(scheduler Scheduler) WithSchedulerOption {
+	return WithSchedulerOption{
+		scheduler: scheduler,
+	}
+}
+
+// WithByteBudgetOption is an [Option] implementation that limits the
+// total number of bytes fetched.
+type WithByteBudgetOption[T any] struct {
+	budget int64                 // Maximum bytes to fetch
+	sizeOf func(items []T) int64 // Function to compute the size of a page
+}
+
+// apply applies an option.
+func (o WithByteBudgetOption[T]) apply(opts *options) {
+	opts.budget = o.budget
+	opts.sizeOf = o.sizeOf
+}
+
+// WithByteBudget returns an [Option] which limits the total number of
+// bytes fetched.  The sizeOf function is called with the items of
+// each page retrieved, and must return the size of the page in bytes;
+// once the cumulative size reaches maxBytes, no further pages are
+// requested and any in-flight page retrievals are canceled.  The
+// items of pages already retrieved are still handled.  Note that the
+// type parameter of WithByteBudget must match that of [Depaginate];
+// otherwise, no pages are requested, and [Depaginator.Wait] returns
+// an error wrapping [ErrConflictingOptions].
+func WithByteBudget[T any](maxBytes int64, sizeOf func(items []T) int64) WithByteBudgetOption[T] {
+	return WithByteBudgetOption[T]{
+		budget: maxBytes,
+		sizeOf: sizeOf,
+	}
+}
+
+// WithReadySignalOption is an [Option] implementation that sets a
+// channel to close once the iteration is running.
+type WithReadySignalOption struct {
+	ready chan<- struct{}
+}
+
+// apply applies an option.
+func (o WithReadySignalOption) apply(opts *options) {
+	opts.ready = o.ready
+}
+
+// WithReadySignal returns an [Option] which sets a channel that
+// [Depaginate] will close once the iteration is running; that is,
+// once the daemon has been started and the request for the first page
+// has been issued.  This allows an external coordinator to know when
+// an iteration has begun.  The channel is closed exactly once, so a
+// separate channel must be used for each call to [Depaginate].
+func WithReadySignal(ch chan<- struct{}) WithReadySignalOption {
+	return WithReadySignalOption{
+		ready: ch,
+	}
+}
+
+// WithPostPageHookOption is an [Option] implementation that sets a
+// function to call after each page is retrieved.
+type WithPostPageHookOption[T any] struct {
+	hook func(state State, req PageRequest, items []T)
+}
+
+// apply applies an option.
+func (o WithPostPageHookOption[T]) apply(opts *options) {
+	opts.postPage = o.hook
+}
+
+// WithPostPageHook returns an [Option] which sets a function to be
+// called after each page is successfully retrieved, before its items
+// are handled.  The function is passed the [State], allowing it to
+// update the total number of items or pages, or to request additional
+// pages, based on the contents of the page.  This allows the logic
+// for deriving metadata from a page to be kept separate from the
+// [PageGetter].  Note that the type parameter of WithPostPageHook must
+// match that of [Depaginate]; otherwise, no pages are requested, and
+// [Depaginator.Wait] returns an error wrapping [ErrConflictingOptions].
+func WithPostPageHook[T any](hook func(state State, req PageRequest, items []T)) WithPostPageHookOption[T] {
+	return WithPostPageHookOption[T]{
+		hook: hook,
+	}
+}
+
+// WithTrustInferenceOption is an [Option] implementation that sets
+// whether inferred totals take precedence over known totals.
+type WithTrustInferenceOption bool
+
+// apply applies an option.
+func (o WithTrustInferenceOption) apply(opts *options) {
+	if o {
+		opts.inference = inferAlways
+	} else {
+		opts.inference = inferNever
+	}
+}
+
+// WithTrustInference returns an [Option] which controls how the total
+// number of items and pages inferred from a short page interact with
+// totals that are already known, either from hints passed to
+// [Depaginate] or from calls to [State.Update].  The precedence rules
+// are as follows:
+//
+//   - If a total is not yet known, the inferred total is always used.
+//   - By default, the inferred total is used only if it is smaller
+//     than the known total.
+//   - If trust is true, the inferred total is used even if it is
+//     larger than the known total, unless that total was itself
+//     inferred from an earlier short page.
+//   - If trust is false, the inferred total is never used if a total
+//     is already known.
+func WithTrustInference(trust bool) WithTrustInferenceOption {
+	return WithTrustInferenceOption(trust)
+}
+
+// WithHandleConcurrencyOption is an [Option] implementation that
+// limits the number of pages whose items are handled concurrently.
+type WithHandleConcurrencyOption int
+
+// apply applies an option.
+func (o WithHandleConcurrencyOption) apply(opts *options) {
+	opts.workers = int(o)
+}
+
+// WithHandleConcurrency returns an [Option] which limits the number
+// of pages whose items are handled concurrently.  By default, the
+// items of each page are handled in a goroutine of their own, which
+// may consume excessive memory if the [Handler] is expensive and many
+// pages arrive at once.  With this option, the items are instead
+// handled by a pool of n worker goroutines, independent of the number
+// of page retrievals in flight, each worker handling the items of a
+// page in order; pages that arrive while all workers
+// are busy are queued for a worker to become free, without holding up
+// the processing of other updates, so handlers may safely make
+// requests or query the [Depaginator].  Once n pages are queued, page
+// retrievals that complete wait for room in the queue before
+// submitting their pages, so slow handlers apply backpressure to the
+// page retrievals rather than accumulating pages.  If a [Scheduler]
+// is set, the workers are instead run as its tasks, each exiting once
+// no pages are queued, and no more than n of them run at once.  A
+// value of 0 or less restores the default behavior.
+func WithHandleConcurrency(n int) WithHandleConcurrencyOption {
+	return WithHandleConcurrencyOption(n)
+}
+
+// WithOrderedCompletionOption is an [Option] implementation that sets
+// a function to be called as items complete, in index order.
+type WithOrderedCompletionOption struct {
+	fn func(idx int)
+}
+
+// apply applies an option.
+func (o WithOrderedCompletionOption) apply(opts *options) {
+	opts.completion = o.fn
+}
+
+// WithOrderedCompletion returns an [Option] which sets a function to
+// be called with the index of each item once it has been handled,
+// strictly in index order.  The items of different pages may be
+// handled concurrently, for instance by the workers of
+// [WithHandleConcurrency], and so complete in any order; each
+// completion is buffered until every item with a lower index has also
+// completed.  This suits pipelines that process items in parallel but
+// must acknowledge them in order, such as when committing offsets.
+// Items dropped by the handle gate or the limit are reported as
+// complete, but items of pages that were not retrieved, or whose
+// handling was skipped, are not, so no later completions are reported
+// after such a gap.  The function is never called concurrently.
+func WithOrderedCompletion(fn func(idx int)) WithOrderedCompletionOption {
+	return WithOrderedCompletionOption{
+		fn: fn,
+	}
+}
+
+// WithActivityTimeoutOption is an [Option] implementation that sets
+// the activity timeout.
+type WithActivityTimeoutOption time.Duration
+
+// apply applies an option.
+func (o WithActivityTimeoutOption) apply(opts *options) {
+	opts.activity = time.Duration(o)
+}
+
+// WithActivityTimeout returns an [Option] which cancels the iteration
+// if no page is successfully retrieved within the specified duration.
+// Unlike a deadline set on the context passed to [Depaginate], the
+// timeout is reset each time a page is retrieved, so a long iteration
+// is allowed to continue as long as it is making progress.  If the
+// timeout expires, [Depaginator.Wait] will return an error wrapping
+// [ErrStalled].  If a [Scheduler] is set, the iteration is canceled by
+// one of its tasks, started when the timeout expires, so the
+// cancellation is ordered along with the other tasks; it is skipped
+// if a page was retrieved in the meantime.  A duration of 0 or less
+// disables the timeout.
+func WithActivityTimeout(d time.Duration) WithActivityTimeoutOption {
+	return WithActivityTimeoutOption(d)
+}
+
+// WithHandleTimeoutOption is an [Option] implementation that sets a
+// timeout for handling each item.
+type WithHandleTimeoutOption time.Duration
+
+// apply applies an option.
+func (o WithHandleTimeoutOption) apply(opts *options) {
+	opts.timeout = time.Duration(o)
+}
+
+// WithHandleTimeout returns an [Option] which bounds the time taken to
+// handle each item.  Each call to [Handler.Handle] is passed a context
+// derived from that of the iteration, which expires after the
+// specified duration; a [Handler] that respects the context may then
+// abandon the item.  If the context has expired by the time the call
+// returns, an [ItemError] wrapping [context.DeadlineExceeded] is
+// recorded, and will be returned by [Depaginator.Wait].  A duration of
+// 0 or less disables the timeout.
+func WithHandleTimeout(d time.Duration) WithHandleTimeoutOption {
+	return WithHandleTimeoutOption(d)
+}
+
+// WithPageStreamOption is an [Option] implementation that enables
+// page streaming.
+type WithPageStreamOption struct{}
+
+// apply applies an option.
+func (o WithPageStreamOption) apply(opts *options) {
+	opts.stream = true
+}
+
+// WithPageStream returns an [Option] which enables page streaming.
+// In this mode, each page retrieved is delivered, once its items have
+// been handled, on the channel returned by [Depaginator.Pages].  This
+// allows pages to be consumed whole, avoiding the overhead of handling
+// each item individually; the [Handler] passed to [Depaginate] may be
+// nil in this mode.
+func WithPageStream() WithPageStreamOption {
+	return WithPageStreamOption{}
+}
+
+// WithRequestDecoratorOption is an [Option] implementation that sets
+// a function to decorate page requests.
+type WithRequestDecoratorOption struct {
+	decorator func(req PageRequest) PageRequest
+}
+
+// apply applies an option.
+func (o WithRequestDecoratorOption) apply(opts *options) {
+	opts.decorator = o.decorator
+}
+
+// WithRequestDecorator returns an [Option] which sets a function to
+// be called with each [PageRequest] immediately before it is passed
+// to [PageGetter.GetPage]; the page is retrieved using the
+// [PageRequest] the function returns.  This allows request data that
+// must be fresh for each page, such as a rotating authentication
+// token, to be supplied from a central source rather than managed by
+// the [PageGetter].  The PageIndex field of the returned
+// [PageRequest] should not be altered.  Note that errors and page
+// history report the original, undecorated request.
+func WithRequestDecorator(decorator func(req PageRequest) PageRequest) WithRequestDecoratorOption {
+	return WithRequestDecoratorOption{
+		decorator: decorator,
+	}
+}
+
+// WithOverlapHandlerOption is an [Option] implementation that sets a
+// function to resolve overlapping page requests.
+type WithOverlapHandlerOption struct {
+	handler func(existing, incoming PageRequest) PageRequest
+}
+
+// apply applies an option.
+func (o WithOverlapHandlerOption) apply(opts *options) {
+	opts.overlap = o.handler
+}
+
+// WithOverlapHandler returns an [Option] which sets a function to
+// resolve overlapping page requests.  Ordinarily, a request for a page
+// that has already been requested is ignored, even if it carries
+// different request data.  With this option, if the retrieval of the
+// page has not yet begun--for instance, because it is waiting for a
+// concurrency slot or the rate limiter--the function is called with
+// the existing and incoming requests, and the [PageRequest] it returns
+// replaces the existing one; it may return either request or merge
+// them.  The PageIndex field of the returned [PageRequest] is ignored.
+// Requests arriving once retrieval of the page has begun are ignored
+// as before.
+func WithOverlapHandler(handler func(existing, incoming PageRequest) PageRequest) WithOverlapHandlerOption {
+	return WithOverlapHandlerOption{
+		handler: handler,
+	}
+}
+
+// WithHealthGateOption is an [Option] implementation that sets a
+// function to check the health of the downstream.
+type WithHealthGateOption struct {
+	healthy func() bool
+}
+
+// apply applies an option.
+func (o WithHealthGateOption) apply(opts *options) {
+	opts.healthy = o.healthy
+}
+
+// WithHealthGate returns an [Option] which sets a function to be
+// consulted before each page is retrieved.  While the function
+// returns false, page retrievals are paused, with the function polled
+// at increasing intervals, starting at [HealthPollMin] and doubling
+// up to [HealthPollMax], until it returns true.  This allows the
+// iteration to throttle itself while a fragile downstream recovers,
+// without failing.  A paused page retrieval is abandoned if the
+// iteration is canceled.
+func WithHealthGate(healthy func() bool) WithHealthGateOption {
+	return WithHealthGateOption{
+		healthy: healthy,
+	}
+}
+
+// WithUpdateRecorderOption is an [Option] implementation that sets a
+// function to record the updates processed.
+type WithUpdateRecorderOption struct {
+	recorder func(updateType string)
+}
+
+// apply applies an option.
+func (o WithUpdateRecorderOption) apply(opts *options) {
+	opts.recorder = o.recorder
+}
+
+// WithUpdateRecorder returns an [Option] which sets a function to be
+// called with the name of the type of each update processed by the
+// [Depaginator], such as "pageRequest" or "itemHandler", in the order
+// in which they are processed.  This is a debugging aid, allowing the
+// ordering of events in a problematic iteration to be captured for
+// later analysis, possibly for reproduction using [WithScheduler].
+// The function is called from the goroutine processing the updates,
+// so it must be fast and must not call methods of the [Depaginator].
+func WithUpdateRecorder(recorder func(updateType string)) WithUpdateRecorderOption {
+	return WithUpdateRecorderOption{
+		recorder: recorder,
+	}
+}
+
+// WithLoggerOption is an [Option] implementation that sets a function
+// to log warnings.
+type WithLoggerOption struct {
+	logger func(msg string)
+}
+
+// apply applies an option.
+func (o WithLoggerOption) apply(opts *options) {
+	opts.logger = o.logger
+}
+
+// WithLogger returns an [Option] which sets a function to be called
+// with warnings about the iteration, such as when the [TotalItems],
+// [TotalPages], and [PerPage] hints are inconsistent with each other.
+// Warnings do not stop the iteration.
+func WithLogger(logger func(msg string)) WithLoggerOption {
+	return WithLoggerOption{
+		logger: logger,
+	}
+}
+
+// WithLifecycleLoggerOption is an [Option] implementation that sets
+// the [Logger].
+type WithLifecycleLoggerOption struct {
+	logger Logger
+}
+
+// apply applies an option.
+func (o WithLifecycleLoggerOption) apply(opts *options) {
+	opts.lifecycle = o.logger
+}
+
+// WithLifecycleLogger returns an [Option] which sets a [Logger] to
+// log the lifecycle of the iteration: each page request issued, each
+// page retrieved, the final totals, and, as warnings, each page
+// retrieval error and the warnings passed to the function set by
+// [WithLogger].  This provides visibility into a misbehaving
+// [PageGetter] without implementing [Starter], [Updater], or [Doner]
+// purely to log.
+func WithLifecycleLogger(logger Logger) WithLifecycleLoggerOption {
+	return WithLifecycleLoggerOption{
+		logger: logger,
+	}
+}
+
+// WithMetricsOption is an [Option] implementation that sets the
+// [Metrics].
+type WithMetricsOption struct {
+	metrics Metrics
+}
+
+// apply applies an option.
+func (o WithMetricsOption) apply(opts *options) {
+	opts.metrics = o.metrics
+}
+
+// WithMetrics returns an [Option] which sets a [Metrics] collector to
+// be fed as the iteration progresses: with the duration and outcome of
+// each call to [PageGetter.GetPage], with each item handled, and with
+// the final totals once the iteration is complete.  This allows an
+// iteration to be instrumented without a custom [Updater].
+func WithMetrics(metrics Metrics) WithMetricsOption {
+	return WithMetricsOption{
+		metrics: metrics,
+	}
+}
+
+// WithTracerOption is an [Option] implementation that sets the
+// [Tracer].
+type WithTracerOption struct {
+	tracer Tracer
+}
+
+// apply applies an option.
+func (o WithTracerOption) apply(opts *options) {
+	opts.tracer = o.tracer
+}
+
+// WithTracer returns an [Option] which sets a [Tracer] to trace the
+// retrieval of pages.  A span named [SpanName] is started for each
+// call to [PageGetter.GetPage], including each retry, and ended when
+// the call returns, with the error it returned; the context passed
+// to GetPage carries the span, so that the spans of any requests the
+// [PageGetter] makes nest beneath it.
+func WithTracer(tracer Tracer) WithTracerOption {
+	return WithTracerOption{
+		tracer: tracer,
+	}
+}
+
+// WithHandleGateOption is an [Option] implementation that sets a
+// function to decide whether each item is handled.
+type WithHandleGateOption struct {
+	gate func(idx int, totals Totals) bool
+}
+
+// apply applies an option.
+func (o WithHandleGateOption) apply(opts *options) {
+	opts.gate = o.gate
+}
+
+// WithHandleGate returns an [Option] which sets a function to be
+// called with the index of each item, immediately before the item is
+// handled, along with the most up-to-date [Totals] known to the
+// iteration.  If the function returns false, the item is dropped,
+// rather than passed to the [Handler].  This allows items beyond a
+// boundary that is only discovered during the iteration, such as a
+// total reported by the [PageGetter], to be skipped; it is more
+// flexible than the static limit set by [WithLimit].  The function is
+// called concurrently from the goroutines handling items, so it must
+// be safe for concurrent use.
+func WithHandleGate(gate func(idx int, totals Totals) bool) WithHandleGateOption {
+	return WithHandleGateOption{
+		gate: gate,
+	}
+}
+
+// errorHook describes a function to call with page errors.
+type errorHook func(ctx context.Context, req PageRequest, err error)
+
+// WithErrorHandlerOption is an [Option] implementation that sets a
+// function to call with each page error.
+type WithErrorHandlerOption struct {
+	handler   errorHook // Function to call with page errors
+	ctxErrors bool      // Call the handler for context errors too
+}
+
+// apply applies an option.
+func (o WithErrorHandlerOption) apply(opts *options) {
+	opts.onError = o.handler
+	opts.ctxErrors = o.ctxErrors
+}
+
+// WithErrorHandler returns an [Option] which sets a function to be
+// called with each error encountered while retrieving a page, as it
+// is encountered, rather than only once [Depaginator.Wait] returns.
+// This allows errors to be logged or counted as the iteration
+// progresses.  By default, errors caused by the cancellation of the
+// context are not passed to the function; if ctxErrors is true, they
+// are passed as well.  The function is called from the goroutine
+// processing the updates, so calls to it are serialized, but it must
+// be fast, and must not call methods of the [Depaginator] that wait
+// for the updates to be processed, such as [Depaginator.Progress].
+func WithErrorHandler(handler func(ctx context.Context, req PageRequest, err error), ctxErrors bool) WithErrorHandlerOption {
+	return WithErrorHandlerOption{
+		handler:   handler,
+		ctxErrors: ctxErrors,
+	}
+}
+
+// WithAllowEmptyPagesOption is an [Option] implementation that allows
+// empty pages before the final page.
+type WithAllowEmptyPagesOption struct{}
+
+// apply applies an option.
+func (o WithAllowEmptyPagesOption) apply(opts *options) {
+	opts.allowEmpty = true
+}
+
+// WithAllowEmptyPages returns an [Option] which allows pages other
+// than the final page to be empty.  By default, a page with fewer
+// items than the number of items per page is taken to be the final
+// page, and the total number of items and pages are inferred from it;
+// with this option, an empty page does not cause this inference, so
+// pages following it may still be retrieved.  Note that the automatic
+// fetch strategy enabled by [WithAutoStrategy] does not probe past an
+// empty page, so with this option, the [PageGetter] should either
+// report the total number of pages or request the following pages
+// itself.
+func WithAllowEmptyPages() WithAllowEmptyPagesOption {
+	return WithAllowEmptyPagesOption{}
+}
+
+// WithCompactPageMapOption is an [Option] implementation that enables
+// compaction of the map of requested pages.
+type WithCompactPageMapOption struct{}
+
+// apply applies an option.
+func (o WithCompactPageMapOption) apply(opts *options) {
+	opts.compact = true
+}
+
+// WithCompactPageMap returns an [Option] which bounds the memory used
+// to detect duplicate page requests.  By default, the [Depaginator]
+// remembers every page requested for the duration of the iteration,
+// which may use a significant amount of memory for iterations over
+// millions of pages.  With this option, pages more than
+// [CompactWindow] pages below the highest page requested are
+// forgotten, and so a request for such a page will cause it to be
+// retrieved again.  This is only suitable for iterations where pages
+// are requested roughly in order, and are never requested again
+// once retrieved.
+func WithCompactPageMap() WithCompactPageMapOption {
+	return WithCompactPageMapOption{}
+}
+
+// WithBatchedRequestsOption is an [Option] implementation that
+// bundles the page requests made during each page retrieval.
+type WithBatchedRequestsOption struct{}
+
+// apply applies an option.
+func (o WithBatchedRequestsOption) apply(opts *options) {
+	opts.batch = true
+}
+
+// WithBatchedRequests returns an [Option] which holds back the
+// updates and page requests made through the [State] passed to
+// [PageGetter.GetPage] until GetPage returns, then submits them to the
+// [Depaginator] together.  The State is then no longer the
+// [Depaginator] itself.  This considerably reduces the load on the [Depaginator]
+// when a page getter requests many pages one at a time, such as when
+// paging ahead, but the requested pages are not retrieved until the
+// requesting page has been retrieved.  Calls to [State.MarkLast] are
+// still submitted immediately, along with any requests held back.
+func WithBatchedRequests() WithBatchedRequestsOption {
+	return WithBatchedRequestsOption{}
+}
+
+// WithRateLimiterOption is an [Option] implementation that sets a
+// rate limiter for page retrievals.
+type WithRateLimiterOption struct {
+	limiter *rate.Limiter
+}
+
+// apply applies an option.
+func (o WithRateLimiterOption) apply(opts *options) {
+	opts.limiter = o.limiter
+}
+
+// WithRateLimiter returns an [Option] which sets a rate limiter to be
+// waited on before each call to [PageGetter.GetPage].  The limiter is
+// shared by all page retrievals, so the overall rate of requests to
+// the target API is bounded.  A page whose retrieval is canceled
+// while waiting on the limiter is treated as canceled.  This may be
+// combined with [MaxConcurrency] to bound both the rate of requests
+// and the number of requests in progress at once; the same limiter
+// may also be shared by several iterations.
+func WithRateLimiter(limiter *rate.Limiter) WithRateLimiterOption {
+	return WithRateLimiterOption{
+		limiter: limiter,
+	}
+}
+
+// WithResultCallbackOption is an [Option] implementation that sets a
+// function to call with all the items retrieved.
+type WithResultCallbackOption[T any] struct {
+	callback func(items []T)
+}
+
+// apply applies an option.
+func (o WithResultCallbackOption[T]) apply(opts *options) {
+	opts.result = o.callback
+}
+
+// WithResultCallback returns an [Option] which sets a function to be
+// called exactly once, by [Depaginator.Wait], with the complete list
+// of items retrieved, in order.  This is in addition to the [Handler]
+// passed to [Depaginate], which may be nil if only the complete list
+// is required.  Note that the type parameter of WithResultCallback
+// must match that of [Depaginate]; otherwise, the option is ignored.
+func WithResultCallback[T any](callback func(items []T)) WithResultCallbackOption[T] {
+	return WithResultCallbackOption[T]{
+		callback: callback,
+	}
+}
+
+// WithLimitOption is an [Option] implementation that limits the
+// number of items handled.
+type WithLimitOption int
+
+// apply applies an option.
+func (o WithLimitOption) apply(opts *options) {
+	opts.limit = int(o)
+}
+
+// WithLimit returns an [Option] which limits the iteration to the
+// first n items.  Items with an index of n or greater are not passed
+// to the [Handler], even if they belong to a page that is handled
+// concurrently with an earlier one, so exactly n items are handled if
+// that many are available; pages beginning at or beyond the limit
+// are not retrieved, once the number of items per page is known, and
+// the retrieval of any such pages requested earlier is canceled.  The
+// total number of items reported to the [Doner] is reduced to the
+// limit if required.  A value of 0 or less disables the limit.
+// [MaxItems] is equivalent.
+func WithLimit(n int) WithLimitOption {
+	return WithLimitOption(n)
+}
+
+// WithRetryOption is an [Option] implementation that enables retrying
+// failed page retrievals.
+type WithRetryOption struct {
+	attempts int                             // Maximum attempts to retrieve a page
+	backoff  func(attempt int) time.Duration // Function to compute retry delays
+}
+
+// apply applies an option.
+func (o WithRetryOption) apply(opts *options) {
+	opts.attempts = o.attempts
+	opts.backoff = o.backoff
+}
+
+// WithRetry returns an [Option] which retries failed page retrievals.
+// Each page is retrieved up to attempts times in total; after each
+// failed attempt, the backoff function, if not nil, is called with
+// the number of the failed attempt, starting at 1, and the retrieval
+// waits for the duration it returns before trying again.  Only the
+// error from the final attempt is reported; errors from earlier
+// attempts are discarded.  Errors caused by the cancellation of the
+// context are not retried, nor are errors implementing
+// [RetryableError] whose Retryable method returns false, allowing the
+// [PageGetter] to distinguish transient failures from permanent ones.
+// A retrieval waiting to retry is abandoned if the iteration is
+// canceled.  An attempts value of 1 or less disables retries.
+func WithRetry(attempts int, backoff func(attempt int) time.Duration) WithRetryOption {
+	return WithRetryOption{
+		attempts: attempts,
+		backoff:  backoff,
+	}
+}
+
+// WithRetryBudgetOption is an [Option] implementation that limits
+// the total number of retries.
+type WithRetryBudgetOption int
+
+// apply applies an option.
+func (o WithRetryBudgetOption) apply(opts *options) {
+	opts.retries = int(o)
+}
+
+// WithRetryBudget returns an [Option] which limits the number of
+// retries performed across all pages of the iteration to n.  It
+// complements [WithRetry], which limits the retries of each page:
+// a failed page is retried only while both limits permit, so once the
+// budget is nearly exhausted, a page may be retried fewer times than
+// [WithRetry] allows, and once it is exhausted, failed pages are not
+// retried at all.  A value of 0 or less disables the budget.
+func WithRetryBudget(n int) WithRetryBudgetOption {
+	return WithRetryBudgetOption(n)
+}
+
+// WithIntraPageOrderOption is an [Option] implementation that sets
+// the order in which the items of a page are handled.
+type WithIntraPageOrderOption func(pageLen int) []int
+
+// apply applies an option.
+func (o WithIntraPageOrderOption) apply(opts *options) {
+	opts.order = o
+}
+
+// WithIntraPageOrder returns an [Option] which sets the order in
+// which the items of each page are passed to the [Handler].  The
+// order function is called with the number of items in the page, and
+// returns the indexes of the items within the page in the order they
+// are to be handled; indexes outside the page are ignored, as are
+// items whose indexes are omitted.  The index passed to the [Handler]
+// is unaffected.  By default, items are handled in ascending order.
+// Note that this only affects the items within a page; pages are
+// still handled concurrently.
+func WithIntraPageOrder(order func(pageLen int) []int) WithIntraPageOrderOption {
+	return WithIntraPageOrderOption(order)
+}
+
+// WithConcurrencySamplerOption is an [Option] implementation that
+// sets a function to periodically sample the number of page
+// retrievals in progress.
+type WithConcurrencySamplerOption struct {
+	interval time.Duration    // Interval between samples
+	fn       func(active int) // Function to call with each sample
+}
+
+// apply applies an option.
+func (o WithConcurrencySamplerOption) apply(opts *options) {
+	opts.sampling = o.interval
+	opts.sampler = o.fn
+}
+
+// WithConcurrencySampler returns an [Option] which sets a function to
+// be called once per interval with the number of calls to
+// [PageGetter.GetPage] in progress at that moment.  This is intended
+// as an aid to tuning [MaxConcurrency], revealing whether the
+// configured limit is actually being reached.  The function is called
+// from a goroutine of its own, which is stopped by
+// [Depaginator.Wait]; it is not called once [Depaginator.Wait] has
+// returned.  An interval of 0 or less disables sampling.
+func WithConcurrencySampler(interval time.Duration, fn func(active int)) WithConcurrencySamplerOption {
+	return WithConcurrencySamplerOption{
+		interval: interval,
+		fn:       fn,
+	}
+}
+
+// OptionsOption is an [Option] implementation that bundles together
+// several options.
+type OptionsOption []Option
+
+// apply applies an option.
+func (o OptionsOption) apply(opts *options) {
+	for _, opt := range o {
+		opt.apply(opts)
+	}
+}
+
+// Options returns an [Option] which bundles together several other
+// options, applying each of them in order.  This allows a standard
+// configuration to be assembled once and reused for several calls to
+// [Depaginate].
+func Options(opts ...Option) OptionsOption {
+	return OptionsOption(opts)
+}
+
 // update describes an update to be processed by the [Depaginator]'s
 // daemon.  The daemon processes updates to metadata, such as the
 // total number of items, as well as issuing new page requests.
@@ -183,6 +1448,13 @@ type cancelerFor[T any] struct {
 
 // applyUpdate applies an update.
 func (u cancelerFor[T]) applyUpdate(depag *Depaginator[T]) {
+	// Cancel immediately if an error has stopped the iteration, or
+	// if the page is beyond the item limit
+	if depag.failed || depag.limit > 0 && depag.perPage > 0 && u.page*depag.perPage >= depag.limit {
+		u.cancelFn()
+		return
+	}
+
 	depag.cancelers[u.page] = u.cancelFn
 }
 
@@ -193,6 +1465,7 @@ type withdrawCanceler[T any] int
 // applyUpdate applies an update.
 func (u withdrawCanceler[T]) applyUpdate(depag *Depaginator[T]) {
 	delete(depag.cancelers, int(u))
+	delete(depag.overlaps, int(u))
 }
 
 // errorSaver is an [update] implementation that saves an error.
@@ -203,38 +1476,103 @@ type errorSaver[T any] struct {
 
 // applyUpdate applies an update.
 func (u errorSaver[T]) applyUpdate(depag *Depaginator[T]) {
-	// Skip context-related errors
+	// Release any later pages waiting to be handled in order
+	if depag.inOrder != nil {
+		depag.inOrder.Settle(u.req.PageIndex, nil)
+		depag.handleInOrder()
+	}
+
+	// Skip context-related errors, reporting them if requested
 	if errors.Is(u.err, context.Canceled) || errors.Is(u.err, context.DeadlineExceeded) {
+		if depag.onError != nil && depag.ctxErrors {
+			depag.onError(depag.ctx, u.req, u.err)
+		}
 		return
 	}
 
+	// Report the error
+	depag.warnf("page %d: %s", u.req.PageIndex, u.err)
+	if depag.onError != nil {
+		depag.onError(depag.ctx, u.req, u.err)
+	}
+
+	// Record the outcome of the page and the page in the history
+	if depag.pageErrs == nil {
+		depag.pageErrs = map[int]error{}
+	}
+	depag.pageErrs[u.req.PageIndex] = u.err
+	depag.history = append(depag.history, PageMeta{
+		Request:    u.req,
+		TotalItems: depag.totalItems,
+		TotalPages: depag.totalPages,
+		PerPage:    depag.perPage,
+		Err:        u.err,
+	})
+
 	// Save the error
 	depag.errors = append(depag.errors, PageError{
 		PageRequest: u.req,
 		Err:         u.err,
 	})
+
+	// Stop the iteration if failing fast, or if more errors have
+	// occurred than are tolerated
+	depag.failures++
+	if depag.failFast || depag.maxErrors > 0 && depag.failures > depag.maxErrors {
+		depag.failed = true
+		for _, canceler := range depag.cancelers {
+			canceler()
+		}
+	}
+}
+
+// itemErrorSaver is an [update] implementation that saves an error
+// handling an item.
+type itemErrorSaver[T any] struct {
+	idx int   // The index of the item
+	err error // The error that occurred
+}
+
+// applyUpdate applies an update.
+func (u itemErrorSaver[T]) applyUpdate(depag *Depaginator[T]) {
+	depag.errors = append(depag.errors, ItemError{
+		Index: u.idx,
+		Err:   u.err,
+	})
 }
 
 // itemHandler is an [update] implementation that handles a page of
 // items.  The items are handled in a separate goroutine.
 type itemHandler[T any] struct {
-	idx  int // Page index
-	page []T // The page of items to handle
+	idx  int  // Page index
+	req  any  // Request-specific data
+	page []T  // The page of items to handle
+	more bool // Further logical pages follow in the same fetch
 }
 
 // applyUpdate applies an update.
 func (u itemHandler[T]) applyUpdate(depag *Depaginator[T]) {
-	// Is this page short?
-	if len(u.page) < depag.perPage {
+	// Has an error stopped the iteration?
+	if depag.failed {
+		if depag.inOrder != nil {
+			depag.inOrder.Settle(u.idx, nil)
+			depag.handleInOrder()
+		}
+		return
+	}
+
+	// Is this page short, or the last page?
+	if u.isShort(depag) || u.isLast(depag) {
 		// Got the page count and item count now
 		totPages := u.idx + 1
 		totItems := depag.perPage*u.idx + len(u.page)
-		if depag.totalPages == 0 || depag.totalPages > totPages {
+		if depag.infer(depag.totalPages, totPages) {
 			depag.totalPages = totPages
 		}
-		if depag.totalItems == 0 || depag.totalItems > totItems {
+		if depag.infer(depag.totalItems, totItems) {
 			depag.totalItems = totItems
 		}
+		depag.inferred = true
 
 		// Cancel pages we no longer need
 		for page, canceler := range depag.cancelers {
@@ -244,17 +1582,187 @@ func (u itemHandler[T]) applyUpdate(depag *Depaginator[T]) {
 		}
 	}
 
+	// Cancel pages beyond the item limit once it has been reached
+	if depag.limit > 0 && depag.perPage > 0 && depag.perPage*u.idx+len(u.page) >= depag.limit {
+		for page, canceler := range depag.cancelers {
+			if page*depag.perPage >= depag.limit {
+				canceler()
+			}
+		}
+	}
+
+	// Reset the activity timeout
+	if depag.idle != nil {
+		depag.idle.Reset(depag.activity)
+		depag.resets.Add(1)
+	}
+
+	// Account for the size of the page
+	if depag.sizeOf != nil {
+		depag.spent += depag.sizeOf(u.page)
+		if depag.exhausted() {
+			for _, canceler := range depag.cancelers {
+				canceler()
+			}
+		}
+	}
+
+	// Keep track of the pages received for partial results
+	if depag.partial {
+		depag.received[u.idx] = len(u.page)
+	}
+
+	// Record the outcome of the page and the page in the history
+	depag.retrieved.CheckAndSet(u.idx)
+	depag.history = append(depag.history, PageMeta{
+		Request: PageRequest{
+			PageIndex: u.idx,
+			Request:   u.req,
+		},
+		ItemCount:  len(u.page),
+		TotalItems: depag.totalItems,
+		TotalPages: depag.totalPages,
+		PerPage:    depag.perPage,
+	})
+
+	// Request further pages if the automatic strategy is enabled
+	if depag.auto {
+		u.probe(depag)
+	}
+
+	// Skip the items of a page handled by a previous iteration
+	if depag.handledBefore(u.idx) {
+		if depag.inOrder != nil {
+			depag.inOrder.Settle(u.idx, nil)
+			depag.handleInOrder()
+		}
+		return
+	}
+
 	// Compute the base item index and handle the items
 	depag.wg.Add(1)
-	go u.handle(depag, depag.perPage*u.idx)
+	depag.buffered++
+	itemBase := depag.perPage * u.idx
+	task := func() {
+		u.handle(depag, itemBase)
+	}
+	if depag.inOrder != nil {
+		depag.inOrder.Settle(u.idx, task)
+		depag.handleInOrder()
+		return
+	}
+	depag.dispatch(task)
+}
+
+// isShort determines if the page has fewer items than the number of
+// items per page.  If empty pages are allowed, an empty page is not
+// considered to be short, nor is a logical page followed by others
+// from the same [CompoundPage].
+func (u itemHandler[T]) isShort(depag *Depaginator[T]) bool {
+	if u.more || len(u.page) == 0 && depag.sparse {
+		return false
+	}
+
+	return len(u.page) < depag.perPage
+}
+
+// isLast determines if the page is the one explicitly marked as the
+// last page.  If the number of items per page is not known, this is
+// only reported for the first page, as the total number of items
+// could not otherwise be computed.
+func (u itemHandler[T]) isLast(depag *Depaginator[T]) bool {
+	return depag.marked && u.idx+1 == depag.totalPages && (depag.perPage > 0 || u.idx == 0)
+}
+
+// probe implements the automatic fetch strategy.  If the total number
+// of pages is known, all remaining pages are requested at once;
+// otherwise, the next page is requested if this page was full.
+func (u itemHandler[T]) probe(depag *Depaginator[T]) {
+	switch {
+	case depag.totalPages > depag.fanout:
+		pages := depag.totalPages
+		if depag.maxPages > 0 && pages > depag.maxPages {
+			pages = depag.maxPages
+		}
+		for i := 1; i < pages; i++ {
+			pageRequest[T]{idx: i}.applyUpdate(depag)
+		}
+		depag.fanout = depag.totalPages
+
+	case depag.totalPages == 0 && len(u.page) > 0 && len(u.page) >= depag.perPage:
+		pageRequest[T]{idx: u.idx + 1}.applyUpdate(depag)
+	}
 }
 
 // handle handles each item in the page.
 func (u itemHandler[T]) handle(depag *Depaginator[T], itemBase int) {
-	defer depag.wg.Done()
+	// The daemon owns the count of buffered pages, and a
+	// StatefulHandler may request pages, so the wait group must be
+	// decremented by the daemon, after those requests are processed
+	defer depag.update(handleDone[T](u.idx))
+
+	if depag.order != nil {
+		for _, i := range depag.order(len(u.page)) {
+			if i >= 0 && i < len(u.page) {
+				u.handleItem(depag, itemBase+i, u.page[i])
+			}
+		}
+	} else {
+		for i, item := range u.page {
+			u.handleItem(depag, itemBase+i, item)
+		}
+	}
+
+	// Deliver the page if streaming
+	if depag.stream != nil {
+		depag.stream <- PageResult[T]{
+			Request: PageRequest{
+				PageIndex: u.idx,
+				Request:   u.req,
+			},
+			Items: u.page,
+		}
+	}
+}
+
+// handleItem passes a single item to the handler, subject to the
+// handle gate and the limit on the number of items.
+func (u itemHandler[T]) handleItem(depag *Depaginator[T], idx int, item T) {
+	if depag.completion != nil {
+		defer depag.completion.Complete(idx)
+	}
+	if depag.gate != nil && !depag.gate(idx, depag.published()) {
+		return
+	}
+	if depag.limit > 0 && (idx >= depag.limit || depag.claimed.Add(1) > int64(depag.limit)) {
+		return
+	}
 
-	for i, item := range u.page {
-		depag.handler.Handle(depag.ctx, itemBase+i, item)
+	// Bound the time taken to handle the item
+	ctx := depag.ctx
+	if depag.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(depag.ctx, depag.timeout)
+		defer cancel()
+	}
+
+	if depag.stateful != nil {
+		depag.stateful.Handle(ctx, idx, item, depag)
+	} else if depag.handler != nil {
+		depag.handler.Handle(ctx, idx, item)
+	}
+	if depag.timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		depag.update(itemErrorSaver[T]{
+			idx: idx,
+			err: ctx.Err(),
+		})
+	}
+	if depag.results != nil {
+		depag.results.Handle(depag.ctx, idx, item)
+	}
+	depag.handled.Add(1)
+	if depag.metrics != nil {
+		depag.metrics.ItemHandled()
 	}
 }
 
@@ -264,9 +1772,54 @@ type pageDone[T any] struct{}
 
 // applyUpdate applies an update.
 func (u pageDone[T]) applyUpdate(depag *Depaginator[T]) {
+	depag.fetched++
+	depag.wg.Done()
+}
+
+// handleDone is a sentinel [update] implementation that decrements
+// the wait group once the items of a page have been handled.  It
+// contains the page index.
+type handleDone[T any] int
+
+// applyUpdate applies an update.
+func (u handleDone[T]) applyUpdate(depag *Depaginator[T]) {
+	if depag.completed != nil {
+		depag.completed.CheckAndSet(int(u))
+	}
+	delete(depag.requests, int(u))
+	depag.buffered--
+	if depag.ahead > 0 {
+		depag.admit()
+	}
+	if depag.inOrder != nil {
+		depag.inOrder.Done()
+		depag.handleInOrder()
+	}
 	depag.wg.Done()
 }
 
+// stop is a sentinel [update] implementation that signals the daemon
+// to exit.  It is sent by [Depaginator.Wait] once all pages have been
+// retrieved and handled.
+type stop[T any] struct{}
+
+// applyUpdate applies an update.
+func (u stop[T]) applyUpdate(_ *Depaginator[T]) {}
+
+// snapshot is an [update] implementation that calls a function on the
+// daemon goroutine, allowing the state owned by the daemon to be read
+// safely.  The reply channel is closed once the function returns.
+type snapshot[T any] struct {
+	fn    func(depag *Depaginator[T]) // Function to call
+	reply chan struct{}               // Closed when the call completes
+}
+
+// applyUpdate applies an update.
+func (u snapshot[T]) applyUpdate(depag *Depaginator[T]) {
+	defer close(u.reply)
+	u.fn(depag)
+}
+
 // totalItems is an [update] that updates the total number of items to
 // expect.
 type totalItems[T any] int
@@ -284,11 +1837,32 @@ type totalPages[T any] int
 
 // applyUpdate applies an update.
 func (u totalPages[T]) applyUpdate(depag *Depaginator[T]) {
-	if int(u) > 0 {
+	if int(u) > 0 && !depag.marked {
 		depag.totalPages = int(u)
 	}
 }
 
+// lastPage is an [update] that marks a page as the final page.
+type lastPage[T any] int
+
+// applyUpdate applies an update.
+func (u lastPage[T]) applyUpdate(depag *Depaginator[T]) {
+	if int(u) < 0 {
+		return
+	}
+
+	// Save the total number of pages
+	depag.totalPages = int(u) + 1
+	depag.marked = true
+
+	// Cancel pages we no longer need
+	for page, canceler := range depag.cancelers {
+		if page > int(u) {
+			canceler()
+		}
+	}
+}
+
 // perPage is an [update] that updates the number of items to expect
 // in each page.
 type perPage[T any] int
@@ -300,6 +1874,48 @@ func (u perPage[T]) applyUpdate(depag *Depaginator[T]) {
 	}
 }
 
+// reserved is an [update] that wraps the update handling the items of
+// a page, releasing the room reserved for the page in the queue of the
+// item handling workers once it has been applied.
+type reserved[T any] struct {
+	update[T]
+}
+
+// applyUpdate applies an update.
+func (u reserved[T]) applyUpdate(depag *Depaginator[T]) {
+	defer depag.workers.Release()
+
+	u.update.applyUpdate(depag)
+}
+
+// admission is an [update] implementation that requests the admission
+// of a page retrieval paced by [Depaginator.pace].  The channel is
+// closed once the retrieval is admitted.
+type admission[T any] chan struct{}
+
+// applyUpdate applies an update.
+func (u admission[T]) applyUpdate(depag *Depaginator[T]) {
+	depag.waiting = append(depag.waiting, chan struct{}(u))
+	depag.admit()
+}
+
+// withdrawal is an [update] implementation that withdraws a page
+// retrieval admitted by an [admission], or still waiting for it.
+type withdrawal[T any] chan struct{}
+
+// applyUpdate applies an update.
+func (u withdrawal[T]) applyUpdate(depag *Depaginator[T]) {
+	for i, ticket := range depag.waiting {
+		if ticket == chan struct{}(u) {
+			depag.waiting = append(depag.waiting[:i], depag.waiting[i+1:]...)
+			return
+		}
+	}
+
+	depag.admitted--
+	depag.admit()
+}
+
 // bundle is an [update] that bundles together several updates.
 type bundle[T any] []update[T]
 
@@ -310,6 +1926,30 @@ func (u bundle[T]) applyUpdate(depag *Depaginator[T]) {
 	}
 }
 
+// plannedPage is an [update] implementation that records a page
+// planned by a dry run, then requests the remaining pages implied by
+// the totals.
+type plannedPage[T any] int
+
+// applyUpdate applies an update.
+func (u plannedPage[T]) applyUpdate(depag *Depaginator[T]) {
+	depag.planned = append(depag.planned, int(u))
+
+	// Determine the total number of pages
+	pages := depag.totalPages
+	if pages <= 0 && depag.totalItems > 0 && depag.perPage > 0 {
+		pages = (depag.totalItems + depag.perPage - 1) / depag.perPage
+	}
+
+	// Request the remaining pages
+	if depag.maxPages > 0 && pages > depag.maxPages {
+		pages = depag.maxPages
+	}
+	for i := int(u) + 1; i < pages; i++ {
+		pageRequest[T]{idx: i}.applyUpdate(depag)
+	}
+}
+
 // pageRequest is an [update] implementation that requests a page.
 type pageRequest[T any] struct {
 	idx int // Page index
@@ -323,15 +1963,121 @@ func (u pageRequest[T]) applyUpdate(depag *Depaginator[T]) {
 		return
 	}
 
-	// Has the page been requested already?
+	// Is the page beyond the page limit?
+	if depag.maxPages > 0 && u.idx >= depag.maxPages {
+		return
+	}
+
+	// Is the page beyond the item limit?
+	if depag.limit > 0 && depag.perPage > 0 && u.idx*depag.perPage >= depag.limit {
+		return
+	}
+
+	// Has the byte budget been exhausted, or has an error or
+	// cancellation stopped the iteration?
+	if depag.exhausted() || depag.failed || depag.runCtx != nil && depag.runCtx.Err() != nil {
+		return
+	}
+
+	// Were the items of the page handled by a previous iteration?
+	// The first page is always retrieved to discover the others
+	if u.idx > 0 && depag.handledBefore(u.idx) {
+		return
+	}
+
+	// Has the page been requested already?  Since this is only
+	// called from the daemon (or before the daemon starts), this is
+	// sufficient to coalesce concurrent requests for the same page;
+	// if retrieval of the page has not begun, the overlap handler
+	// may replace the existing request
 	if depag.pages.CheckAndSet(u.idx) {
+		if slot, ok := depag.overlaps[u.idx]; ok {
+			slot.Merge(PageRequest{
+				PageIndex: u.idx,
+				Request:   u.req,
+			}, depag.overlap)
+		}
 		return
 	}
 
+	// Track the highest page requested, forgetting pages far below
+	// it if requested
+	if u.idx > depag.frontier {
+		depag.frontier = u.idx
+		if depag.compact {
+			depag.pages.Compact(u.idx - CompactWindow)
+
+			// Only drop the pages handled that precede every page
+			// not yet handled, as the checkpoint must cover them
+			if depag.completed != nil {
+				floor := depag.completed.FirstUnset()
+				if floor > u.idx-CompactWindow {
+					floor = u.idx - CompactWindow
+				}
+				depag.completed.Compact(floor)
+			}
+		}
+	}
+
+	// Remember the request for the checkpoint
+	if depag.encodeReq != nil && u.req != nil {
+		if depag.requests == nil {
+			depag.requests = map[int]any{}
+		}
+		depag.requests[u.idx] = u.req
+	}
+
 	// Place the request
+	depag.debugf("requesting page %d", u.idx)
 	depag.wg.Add(1)
-	go depag.getPage(PageRequest{
+	if depag.inOrder != nil {
+		depag.inOrder.Fetch(u.idx)
+	}
+	req := PageRequest{
 		PageIndex: u.idx,
 		Request:   u.req,
+	}
+	var slot *overlapSlot
+	if depag.overlap != nil {
+		if depag.overlaps == nil {
+			depag.overlaps = map[int]*overlapSlot{}
+		}
+		slot = newOverlapSlot(req)
+		depag.overlaps[u.idx] = slot
+	}
+	depag.spawn(func() {
+		depag.getPage(req, slot)
 	})
 }
+
+// resume is an [update] implementation that requests the pages
+// recorded in the checkpoint of a previous iteration.  The wait group
+// is incremented when it is sent, so the iteration cannot complete
+// before it is applied.
+type resume[T any] []PageRequest
+
+// applyUpdate applies an update.
+func (u resume[T]) applyUpdate(depag *Depaginator[T]) {
+	defer depag.wg.Done()
+
+	for _, req := range u {
+		pageRequest[T]{
+			idx: req.PageIndex,
+			req: req.Request,
+		}.applyUpdate(depag)
+	}
+}
+
+// nextRequest is an [update] implementation that requests the page
+// following the highest page requested so far.
+type nextRequest[T any] struct {
+	req any // Request-specific data
+}
+
+// applyUpdate applies an update.
+func (u nextRequest[T]) applyUpdate(depag *Depaginator[T]) {
+	pageRequest[T]{
+		idx: depag.frontier + 1,
+		req: u.req,
+	}.applyUpdate(depag)
+}
@@ -18,11 +18,16 @@ package depaginator
 
 import (
 	"context"
+	"math/bits"
+	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 )
 
 type mockOption struct {
@@ -85,6 +90,45 @@ func TestCapacityApply(t *testing.T) {
 	assert.Equal(t, 5, opts.capacity)
 }
 
+func TestMaxConcurrencyImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), MaxConcurrency(0))
+}
+
+func TestMaxConcurrencyApply(t *testing.T) {
+	opts := options{}
+	obj := MaxConcurrency(5)
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 5, opts.maxActive)
+}
+
+func TestMaxPagesImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), MaxPages(0))
+}
+
+func TestMaxPagesApply(t *testing.T) {
+	opts := options{}
+	obj := MaxPages(5)
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 5, opts.maxPages)
+}
+
+func TestMaxItemsImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), MaxItems(0))
+}
+
+func TestMaxItemsApply(t *testing.T) {
+	opts := options{}
+	obj := MaxItems(5)
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 5, opts.limit)
+}
+
 func TestWithStarterOptionImplementsOption(t *testing.T) {
 	assert.Implements(t, (*Option)(nil), WithStarterOption{})
 }
@@ -163,6 +207,32 @@ func TestWithDoner(t *testing.T) {
 	}, result)
 }
 
+func TestWithCommitterOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithCommitterOption{})
+}
+
+func TestWithCommitterOptionApply(t *testing.T) {
+	committer := &mockCommitter{}
+	obj := WithCommitterOption{
+		committer: committer,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Same(t, committer, opts.committer)
+}
+
+func TestWithCommitter(t *testing.T) {
+	committer := &mockCommitter{}
+
+	result := WithCommitter(committer)
+
+	assert.Equal(t, WithCommitterOption{
+		committer: committer,
+	}, result)
+}
+
 func TestWithRequestOptionImplementsOption(t *testing.T) {
 	assert.Implements(t, (*Option)(nil), WithRequestOption{})
 }
@@ -186,294 +256,2447 @@ func TestWithRequest(t *testing.T) {
 	}, result)
 }
 
-type mockUpdate struct {
-	mock.Mock
+func TestWithAutoStrategyOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithAutoStrategyOption{})
 }
 
-func (m *mockUpdate) applyUpdate(depag *Depaginator[string]) { //nolint:unused
-	m.Called(depag)
+func TestWithAutoStrategyOptionApply(t *testing.T) {
+	obj := WithAutoStrategyOption{}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.True(t, opts.auto)
 }
 
-func TestCancelerForImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), cancelerFor[string]{})
+func TestWithAutoStrategy(t *testing.T) {
+	result := WithAutoStrategy()
+
+	assert.Equal(t, WithAutoStrategyOption{}, result)
 }
 
-func TestCancelerForApplyUpdate(t *testing.T) {
-	cancelFn := func() {}
-	obj := cancelerFor[string]{
-		page:     5,
-		cancelFn: cancelFn,
-	}
-	depag := &Depaginator[string]{
-		cancelers: map[int]context.CancelFunc{},
-	}
+func TestWithPartialResultsOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithPartialResultsOption{})
+}
 
-	obj.applyUpdate(depag)
+func TestWithPartialResultsOptionApply(t *testing.T) {
+	obj := WithPartialResultsOption{}
+	opts := options{}
 
-	assert.Contains(t, depag.cancelers, 5)
+	obj.apply(&opts)
+
+	assert.True(t, opts.partial)
 }
 
-func TestWithdrawCancelerImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), withdrawCanceler[string](0))
+func TestWithPartialResults(t *testing.T) {
+	result := WithPartialResults()
+
+	assert.Equal(t, WithPartialResultsOption{}, result)
 }
 
-func TestWithdrawCancelerApplyUpdate(t *testing.T) {
-	obj := withdrawCanceler[string](5)
-	depag := &Depaginator[string]{
-		cancelers: map[int]context.CancelFunc{
-			5: nil,
-		},
-	}
+func TestFailFastOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), FailFastOption{})
+}
 
-	obj.applyUpdate(depag)
+func TestFailFastOptionApply(t *testing.T) {
+	obj := FailFastOption{}
+	opts := options{}
 
-	assert.NotContains(t, depag.cancelers, 5)
+	obj.apply(&opts)
+
+	assert.True(t, opts.failFast)
 }
 
-func TestErrorSaverImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), errorSaver[string]{})
+func TestFailFast(t *testing.T) {
+	result := FailFast()
+
+	assert.Equal(t, FailFastOption{}, result)
 }
 
-func TestErrorSaverApplyUpdateBase(t *testing.T) {
-	obj := errorSaver[string]{
-		req: PageRequest{
-			PageIndex: 5,
-		},
-		err: assert.AnError,
-	}
-	depag := &Depaginator[string]{}
+func TestWithMaxErrorsBeforeAbortOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithMaxErrorsBeforeAbortOption(0))
+}
 
-	obj.applyUpdate(depag)
+func TestWithMaxErrorsBeforeAbortOptionApply(t *testing.T) {
+	obj := WithMaxErrorsBeforeAbortOption(2)
+	opts := options{}
 
-	assert.Equal(t, &Depaginator[string]{
-		errors: []error{
-			PageError{
-				PageRequest: PageRequest{
-					PageIndex: 5,
-				},
-				Err: assert.AnError,
-			},
-		},
-	}, depag)
+	obj.apply(&opts)
+
+	assert.Equal(t, 2, opts.maxErrors)
 }
 
-func TestErrorSaverApplyUpdateCanceled(t *testing.T) {
-	obj := errorSaver[string]{
-		req: PageRequest{
-			PageIndex: 5,
-		},
-		err: context.Canceled,
-	}
-	depag := &Depaginator[string]{}
+func TestWithMaxErrorsBeforeAbort(t *testing.T) {
+	result := WithMaxErrorsBeforeAbort(2)
 
-	obj.applyUpdate(depag)
+	assert.Equal(t, WithMaxErrorsBeforeAbortOption(2), result)
+}
 
-	assert.Equal(t, &Depaginator[string]{}, depag)
+func TestWithDryRunOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithDryRunOption{})
 }
 
-func TestErrorSaverApplyUpdateDeadlineExceeded(t *testing.T) {
-	obj := errorSaver[string]{
-		req: PageRequest{
-			PageIndex: 5,
-		},
-		err: context.DeadlineExceeded,
-	}
-	depag := &Depaginator[string]{}
+func TestWithDryRunOptionApply(t *testing.T) {
+	obj := WithDryRunOption{}
+	opts := options{}
 
-	obj.applyUpdate(depag)
+	obj.apply(&opts)
 
-	assert.Equal(t, &Depaginator[string]{}, depag)
+	assert.True(t, opts.dryRun)
 }
 
-func TestItemHandlerImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), itemHandler[string]{})
+func TestWithDryRun(t *testing.T) {
+	result := WithDryRun()
+
+	assert.Equal(t, WithDryRunOption{}, result)
 }
 
-func TestItemHandlerApplyupdateBase(t *testing.T) {
-	ctx := context.Background()
-	handler := &mockHandler{}
-	handler.On("Handle", ctx, 25, "foo")
-	handler.On("Handle", ctx, 26, "bar")
-	handler.On("Handle", ctx, 27, "baz")
-	cancel4 := &mockCancelFn{}
-	cancel6 := &mockCancelFn{}
-	cancel6.On("Cancel")
-	obj := itemHandler[string]{
-		idx:  5,
-		page: []string{"foo", "bar", "baz"},
-	}
-	depag := &Depaginator[string]{
-		ctx:     ctx,
-		perPage: 5,
-		handler: handler,
-		cancelers: map[int]context.CancelFunc{
-			4: cancel4.Cancel,
-			6: cancel6.Cancel,
-		},
-		wg: &sync.WaitGroup{},
-	}
+func TestInOrderOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), InOrderOption{})
+}
 
-	obj.applyUpdate(depag)
+func TestInOrderOptionApply(t *testing.T) {
+	obj := InOrderOption{}
+	opts := options{}
 
-	depag.wg.Wait()
-	assert.Equal(t, 6, depag.totalPages)
-	assert.Equal(t, 28, depag.totalItems)
-	cancel4.AssertExpectations(t)
-	cancel6.AssertExpectations(t)
-	handler.AssertExpectations(t)
+	obj.apply(&opts)
+
+	assert.True(t, opts.inOrder)
 }
 
-func TestItemHandlerApplyupdateMorePages(t *testing.T) {
-	ctx := context.Background()
-	handler := &mockHandler{}
-	handler.On("Handle", ctx, 25, "foo")
-	handler.On("Handle", ctx, 26, "bar")
-	handler.On("Handle", ctx, 27, "baz")
-	handler.On("Handle", ctx, 28, "bink")
-	handler.On("Handle", ctx, 29, "qux")
-	cancel4 := &mockCancelFn{}
-	cancel6 := &mockCancelFn{}
-	obj := itemHandler[string]{
-		idx:  5,
-		page: []string{"foo", "bar", "baz", "bink", "qux"},
-	}
-	depag := &Depaginator[string]{
-		ctx:     ctx,
-		perPage: 5,
-		handler: handler,
-		cancelers: map[int]context.CancelFunc{
-			4: cancel4.Cancel,
-			6: cancel6.Cancel,
-		},
-		wg: &sync.WaitGroup{},
-	}
+func TestInOrder(t *testing.T) {
+	result := InOrder()
 
-	obj.applyUpdate(depag)
+	assert.Equal(t, InOrderOption{}, result)
+}
 
-	depag.wg.Wait()
-	assert.Equal(t, 0, depag.totalPages)
-	assert.Equal(t, 0, depag.totalItems)
-	cancel4.AssertExpectations(t)
-	cancel6.AssertExpectations(t)
-	handler.AssertExpectations(t)
+func TestWithCheckpointOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithCheckpointOption{})
 }
 
-func TestItemHandlerHandle(t *testing.T) {
-	ctx := context.Background()
-	handler := &mockHandler{}
-	handler.On("Handle", ctx, 25, "foo")
-	handler.On("Handle", ctx, 26, "bar")
-	handler.On("Handle", ctx, 27, "baz")
-	obj := itemHandler[string]{
-		idx:  5,
-		page: []string{"foo", "bar", "baz"},
-	}
-	depag := &Depaginator[string]{
-		ctx:     ctx,
-		handler: handler,
-		wg:      &sync.WaitGroup{},
+func TestWithCheckpointOptionApply(t *testing.T) {
+	obj := WithCheckpointOption{
+		checkpoint: []byte{1, 2, 3},
 	}
-	depag.wg.Add(1)
+	opts := options{}
 
-	obj.handle(depag, 25)
+	obj.apply(&opts)
 
-	depag.wg.Wait()
-	handler.AssertExpectations(t)
+	assert.Equal(t, []byte{1, 2, 3}, opts.checkpoint)
+	assert.True(t, opts.recordCP)
 }
 
-func TestPageDoneImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), pageDone[string]{})
+func TestWithCheckpoint(t *testing.T) {
+	result := WithCheckpoint([]byte{1, 2, 3})
+
+	assert.Equal(t, WithCheckpointOption{
+		checkpoint: []byte{1, 2, 3},
+	}, result)
 }
 
-func TestPageDoneApplyUpdate(_ *testing.T) {
-	obj := pageDone[string]{}
-	depag := &Depaginator[string]{
-		wg: &sync.WaitGroup{},
+func TestWithRequestCodecOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithRequestCodecOption{})
+}
+
+func TestWithRequestCodecOptionApply(t *testing.T) {
+	obj := WithRequestCodecOption{
+		encode: func(req any) ([]byte, error) {
+			return []byte(req.(string)), nil
+		},
+		decode: func(data []byte) (any, error) {
+			return string(data), nil
+		},
 	}
-	depag.wg.Add(1)
+	opts := options{}
 
-	obj.applyUpdate(depag)
+	obj.apply(&opts)
 
-	depag.wg.Wait()
-	// Passes if the waitgroup doesn't wait
+	require.NotNil(t, opts.encodeReq)
+	require.NotNil(t, opts.decodeReq)
+	assert.True(t, opts.recordCP)
+	data, err := opts.encodeReq("req")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("req"), data)
+	req, err := opts.decodeReq(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "req", req)
 }
 
-func TestTotalItemsImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), totalItems[string](0))
+func TestWithRequestCodec(t *testing.T) {
+	result := WithRequestCodec(func(req any) ([]byte, error) {
+		return nil, nil
+	}, func(data []byte) (any, error) {
+		return nil, nil
+	})
+
+	assert.NotNil(t, result.encode)
+	assert.NotNil(t, result.decode)
 }
 
-func TestTotalItemsApplyUpdateBase(t *testing.T) {
-	obj := totalItems[string](5)
-	depag := &Depaginator[string]{
-		totalItems: 3,
+func TestWithSummaryOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithSummaryOption{})
+}
+
+func TestWithSummaryOptionApply(t *testing.T) {
+	var called RunResult
+	obj := WithSummaryOption{
+		summary: func(result RunResult) {
+			called = result
+		},
 	}
+	opts := options{}
 
-	obj.applyUpdate(depag)
+	obj.apply(&opts)
 
-	assert.Equal(t, 5, depag.totalItems)
+	require.NotNil(t, opts.summary)
+	opts.summary(RunResult{TotalItems: 5})
+	assert.Equal(t, RunResult{TotalItems: 5}, called)
 }
 
-func TestTotalItemsApplyUpdateZero(t *testing.T) {
-	obj := totalItems[string](0)
-	depag := &Depaginator[string]{
-		totalItems: 3,
-	}
+func TestWithSummary(t *testing.T) {
+	var called RunResult
 
-	obj.applyUpdate(depag)
+	result := WithSummary(func(result RunResult) {
+		called = result
+	})
 
-	assert.Equal(t, 3, depag.totalItems)
+	require.NotNil(t, result.summary)
+	result.summary(RunResult{TotalItems: 5})
+	assert.Equal(t, RunResult{TotalItems: 5}, called)
 }
 
-func TestTotalPagesImplementsUpdate(t *testing.T) {
+func TestWithSchedulerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithSchedulerOption{})
+}
+
+func TestWithSchedulerOptionApply(t *testing.T) {
+	scheduler := &stepScheduler{}
+	obj := WithSchedulerOption{
+		scheduler: scheduler,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Same(t, scheduler, opts.scheduler)
+}
+
+func TestWithScheduler(t *testing.T) {
+	scheduler := &stepScheduler{}
+
+	result := WithScheduler(scheduler)
+
+	assert.Equal(t, WithSchedulerOption{
+		scheduler: scheduler,
+	}, result)
+}
+
+func TestWithByteBudgetOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithByteBudgetOption[string]{})
+}
+
+func TestWithByteBudgetOptionApply(t *testing.T) {
+	obj := WithByteBudgetOption[string]{
+		budget: 100,
+		sizeOf: func(items []string) int64 {
+			return int64(len(items))
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, int64(100), opts.budget)
+	require.IsType(t, func([]string) int64 { return 0 }, opts.sizeOf)
+	assert.Equal(t, int64(3), opts.sizeOf.(func([]string) int64)([]string{"a", "b", "c"}))
+}
+
+func TestWithByteBudget(t *testing.T) {
+	result := WithByteBudget(100, func(items []string) int64 {
+		return int64(len(items))
+	})
+
+	assert.Equal(t, int64(100), result.budget)
+	require.NotNil(t, result.sizeOf)
+	assert.Equal(t, int64(3), result.sizeOf([]string{"a", "b", "c"}))
+}
+
+func TestWithReadySignalOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithReadySignalOption{})
+}
+
+func TestWithReadySignalOptionApply(t *testing.T) {
+	ready := make(chan struct{})
+	obj := WithReadySignalOption{
+		ready: ready,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, (chan<- struct{})(ready), opts.ready)
+}
+
+func TestWithReadySignal(t *testing.T) {
+	ready := make(chan struct{})
+
+	result := WithReadySignal(ready)
+
+	assert.Equal(t, WithReadySignalOption{
+		ready: ready,
+	}, result)
+}
+
+func TestWithPostPageHookOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithPostPageHookOption[string]{})
+}
+
+func TestWithPostPageHookOptionApply(t *testing.T) {
+	called := false
+	obj := WithPostPageHookOption[string]{
+		hook: func(State, PageRequest, []string) {
+			called = true
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.IsType(t, func(State, PageRequest, []string) {}, opts.postPage)
+	opts.postPage.(func(State, PageRequest, []string))(nil, PageRequest{}, nil)
+	assert.True(t, called)
+}
+
+func TestWithPostPageHook(t *testing.T) {
+	called := false
+
+	result := WithPostPageHook(func(State, PageRequest, []string) {
+		called = true
+	})
+
+	require.NotNil(t, result.hook)
+	result.hook(nil, PageRequest{}, nil)
+	assert.True(t, called)
+}
+
+func TestWithTrustInferenceOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithTrustInferenceOption(false))
+}
+
+func TestWithTrustInferenceOptionApplyTrue(t *testing.T) {
+	obj := WithTrustInferenceOption(true)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, inferAlways, opts.inference)
+}
+
+func TestWithTrustInferenceOptionApplyFalse(t *testing.T) {
+	obj := WithTrustInferenceOption(false)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, inferNever, opts.inference)
+}
+
+func TestWithTrustInference(t *testing.T) {
+	result := WithTrustInference(true)
+
+	assert.Equal(t, WithTrustInferenceOption(true), result)
+}
+
+func TestOptionsConfigBase(t *testing.T) {
+	opts := &options{
+		totalItems: 100,
+		totalPages: 10,
+		perPage:    10,
+		capacity:   50,
+		maxActive:  4,
+		workers:    2,
+		attempts:   3,
+		retries:    4,
+		limit:      75,
+		auto:       true,
+		failFast:   true,
+	}
+
+	result := opts.config()
+
+	assert.Equal(t, Config{
+		TotalItems:        100,
+		TotalPages:        10,
+		PerPage:           10,
+		Capacity:          50,
+		MaxConcurrency:    4,
+		HandleConcurrency: 2,
+		RetryAttempts:     3,
+		RetryBudget:       4,
+		Limit:             75,
+		AutoStrategy:      true,
+		FailFast:          true,
+	}, result)
+}
+
+func TestOptionsConfigNoRetry(t *testing.T) {
+	opts := &options{}
+
+	result := opts.config()
+
+	assert.Equal(t, 1, result.RetryAttempts)
+}
+
+func TestOptionsReconcileConsistent(t *testing.T) {
+	var logged []string
+	opts := &options{
+		totalItems: 25,
+		totalPages: 3,
+		perPage:    10,
+		logger: func(msg string) {
+			logged = append(logged, msg)
+		},
+	}
+
+	items, pages := opts.reconcile()
+
+	assert.Equal(t, 25, items)
+	assert.Equal(t, 3, pages)
+	assert.Empty(t, logged)
+}
+
+func TestOptionsReconcileTooManyItems(t *testing.T) {
+	var logged []string
+	opts := &options{
+		totalItems: 100,
+		totalPages: 3,
+		perPage:    10,
+		logger: func(msg string) {
+			logged = append(logged, msg)
+		},
+	}
+
+	items, pages := opts.reconcile()
+
+	assert.Equal(t, 30, items)
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"inconsistent hints: 100 items do not fit in 3 pages of 10 items; assuming 30 items"}, logged)
+}
+
+func TestOptionsReconcileTooManyPages(t *testing.T) {
+	var logged []string
+	opts := &options{
+		totalItems: 15,
+		totalPages: 5,
+		perPage:    10,
+		logger: func(msg string) {
+			logged = append(logged, msg)
+		},
+	}
+
+	items, pages := opts.reconcile()
+
+	assert.Equal(t, 15, items)
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, []string{"inconsistent hints: 15 items fill only 2 of 5 pages of 10 items; assuming 2 pages"}, logged)
+}
+
+func TestOptionsReconcilePartial(t *testing.T) {
+	opts := &options{
+		totalItems: 100,
+		perPage:    10,
+	}
+
+	items, pages := opts.reconcile()
+
+	assert.Equal(t, 100, items)
+	assert.Equal(t, 0, pages)
+}
+
+func TestOptionsReconcileNoLogger(t *testing.T) {
+	opts := &options{
+		totalItems: 100,
+		totalPages: 3,
+		perPage:    10,
+	}
+
+	items, pages := opts.reconcile()
+
+	assert.Equal(t, 30, items)
+	assert.Equal(t, 3, pages)
+}
+
+func TestOptionsValidateBase(t *testing.T) {
+	obj := &options{
+		workers:   2,
+		activity:  time.Second,
+		maxErrors: 3,
+		stream:    true,
+		attempts:  3,
+		retries:   5,
+	}
+
+	err := obj.validate()
+
+	assert.NoError(t, err)
+}
+
+func TestOptionsValidateSchedulerHandleConcurrency(t *testing.T) {
+	obj := &options{
+		scheduler: SchedulerFunc(func(task func()) {}),
+		workers:   2,
+	}
+
+	err := obj.validate()
+
+	assert.NoError(t, err)
+}
+
+func TestOptionsValidateSchedulerActivityTimeout(t *testing.T) {
+	obj := &options{
+		scheduler: SchedulerFunc(func(task func()) {}),
+		activity:  time.Second,
+	}
+
+	err := obj.validate()
+
+	assert.NoError(t, err)
+}
+
+func TestOptionsValidateConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		first  string
+		second string
+	}{
+		{
+			name:   "fail-fast-max-errors",
+			opts:   []Option{FailFast(), WithMaxErrorsBeforeAbort(3)},
+			first:  "FailFast",
+			second: "WithMaxErrorsBeforeAbort",
+		},
+		{
+			name:   "dry-run-page-stream",
+			opts:   []Option{WithDryRun(), WithPageStream()},
+			first:  "WithDryRun",
+			second: "WithPageStream",
+		},
+		{
+			name:   "dry-run-retry",
+			opts:   []Option{WithDryRun(), WithRetry(3, nil)},
+			first:  "WithDryRun",
+			second: "WithRetry",
+		},
+		{
+			name:   "dry-run-retry-budget",
+			opts:   []Option{WithDryRun(), WithRetryBudget(5)},
+			first:  "WithDryRun",
+			second: "WithRetryBudget",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, opts := range [][]Option{test.opts, {test.opts[1], test.opts[0]}} {
+				obj := &options{}
+				for _, opt := range opts {
+					opt.apply(obj)
+				}
+
+				err := obj.validate()
+
+				assert.ErrorIs(t, err, ErrConflictingOptions)
+				assert.ErrorContains(t, err, test.first+" may not be combined with "+test.second)
+			}
+
+			// Each option is valid on its own
+			for _, opt := range test.opts {
+				obj := &options{}
+				opt.apply(obj)
+
+				assert.NoError(t, obj.validate())
+			}
+		})
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	err := mismatch[string]("WithByteBudget", func(items []int) int64 { return 0 })
+
+	assert.ErrorIs(t, err, ErrConflictingOptions)
+	assert.EqualError(t, err, "conflicting options: WithByteBudget function func([]int) int64 does not accept items of type []string")
+}
+
+func TestOptionsValidateRetryDisabled(t *testing.T) {
+	obj := &options{}
+	WithDryRun().apply(obj)
+	WithRetry(1, nil).apply(obj)
+
+	err := obj.validate()
+
+	assert.NoError(t, err)
+}
+
+func TestWithHandleConcurrencyOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithHandleConcurrencyOption(0))
+}
+
+func TestWithHandleConcurrencyOptionApply(t *testing.T) {
+	obj := WithHandleConcurrencyOption(3)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 3, opts.workers)
+}
+
+func TestWithHandleConcurrency(t *testing.T) {
+	result := WithHandleConcurrency(3)
+
+	assert.Equal(t, WithHandleConcurrencyOption(3), result)
+}
+
+func TestWithOrderedCompletionOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithOrderedCompletionOption{})
+}
+
+func TestWithOrderedCompletionOptionApply(t *testing.T) {
+	var called int
+	obj := WithOrderedCompletionOption{
+		fn: func(idx int) {
+			called = idx
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.completion)
+	opts.completion(3)
+	assert.Equal(t, 3, called)
+}
+
+func TestWithOrderedCompletion(t *testing.T) {
+	result := WithOrderedCompletion(func(idx int) {})
+
+	assert.NotNil(t, result.fn)
+}
+
+func TestWithActivityTimeoutOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithActivityTimeoutOption(0))
+}
+
+func TestWithActivityTimeoutOptionApply(t *testing.T) {
+	obj := WithActivityTimeoutOption(time.Second)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, time.Second, opts.activity)
+}
+
+func TestWithActivityTimeout(t *testing.T) {
+	result := WithActivityTimeout(time.Second)
+
+	assert.Equal(t, WithActivityTimeoutOption(time.Second), result)
+}
+
+func TestWithHandleTimeoutOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithHandleTimeoutOption(0))
+}
+
+func TestWithHandleTimeoutOptionApply(t *testing.T) {
+	obj := WithHandleTimeoutOption(time.Second)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, time.Second, opts.timeout)
+}
+
+func TestWithHandleTimeout(t *testing.T) {
+	result := WithHandleTimeout(time.Second)
+
+	assert.Equal(t, WithHandleTimeoutOption(time.Second), result)
+}
+
+func TestWithPageStreamOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithPageStreamOption{})
+}
+
+func TestWithPageStreamOptionApply(t *testing.T) {
+	obj := WithPageStreamOption{}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.True(t, opts.stream)
+}
+
+func TestWithPageStream(t *testing.T) {
+	result := WithPageStream()
+
+	assert.Equal(t, WithPageStreamOption{}, result)
+}
+
+func TestWithRequestDecoratorOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithRequestDecoratorOption{})
+}
+
+func TestWithRequestDecoratorOptionApply(t *testing.T) {
+	obj := WithRequestDecoratorOption{
+		decorator: func(req PageRequest) PageRequest {
+			req.Request = "decorated"
+			return req
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.decorator)
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "decorated"}, opts.decorator(PageRequest{PageIndex: 3}))
+}
+
+func TestWithRequestDecorator(t *testing.T) {
+	result := WithRequestDecorator(func(req PageRequest) PageRequest {
+		return req
+	})
+
+	assert.NotNil(t, result.decorator)
+}
+
+func TestWithOverlapHandlerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithOverlapHandlerOption{})
+}
+
+func TestWithOverlapHandlerOptionApply(t *testing.T) {
+	obj := WithOverlapHandlerOption{
+		handler: func(existing, incoming PageRequest) PageRequest {
+			return incoming
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.overlap)
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "two"}, opts.overlap(PageRequest{PageIndex: 3, Request: "one"}, PageRequest{PageIndex: 3, Request: "two"}))
+}
+
+func TestWithOverlapHandler(t *testing.T) {
+	result := WithOverlapHandler(func(existing, incoming PageRequest) PageRequest {
+		return existing
+	})
+
+	assert.NotNil(t, result.handler)
+}
+
+func TestWithHealthGateOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithHealthGateOption{})
+}
+
+func TestWithHealthGateOptionApply(t *testing.T) {
+	obj := WithHealthGateOption{
+		healthy: func() bool {
+			return true
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.healthy)
+	assert.True(t, opts.healthy())
+}
+
+func TestWithHealthGate(t *testing.T) {
+	result := WithHealthGate(func() bool {
+		return true
+	})
+
+	assert.NotNil(t, result.healthy)
+}
+
+func TestWithUpdateRecorderOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithUpdateRecorderOption{})
+}
+
+func TestWithUpdateRecorderOptionApply(t *testing.T) {
+	var recorded []string
+	obj := WithUpdateRecorderOption{
+		recorder: func(updateType string) {
+			recorded = append(recorded, updateType)
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.recorder)
+	opts.recorder("bundle")
+	assert.Equal(t, []string{"bundle"}, recorded)
+}
+
+func TestWithUpdateRecorder(t *testing.T) {
+	result := WithUpdateRecorder(func(string) {})
+
+	assert.NotNil(t, result.recorder)
+}
+
+func TestWithLoggerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithLoggerOption{})
+}
+
+func TestWithLoggerOptionApply(t *testing.T) {
+	var logged []string
+	obj := WithLoggerOption{
+		logger: func(msg string) {
+			logged = append(logged, msg)
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.logger)
+	opts.logger("warning")
+	assert.Equal(t, []string{"warning"}, logged)
+}
+
+func TestWithLogger(t *testing.T) {
+	result := WithLogger(func(string) {})
+
+	assert.NotNil(t, result.logger)
+}
+
+func TestWithLifecycleLoggerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithLifecycleLoggerOption{})
+}
+
+func TestWithLifecycleLoggerOptionApply(t *testing.T) {
+	logger := &fakeLogger{}
+	obj := WithLifecycleLoggerOption{
+		logger: logger,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Same(t, logger, opts.lifecycle)
+}
+
+func TestWithLifecycleLogger(t *testing.T) {
+	logger := &fakeLogger{}
+
+	result := WithLifecycleLogger(logger)
+
+	assert.Equal(t, WithLifecycleLoggerOption{
+		logger: logger,
+	}, result)
+}
+
+func TestOptionsWarn(t *testing.T) {
+	var logged []string
+	logger := &fakeLogger{}
+	opts := &options{
+		logger: func(msg string) {
+			logged = append(logged, msg)
+		},
+		lifecycle: logger,
+	}
+
+	opts.warn("100% wrong")
+
+	assert.Equal(t, []string{"100% wrong"}, logged)
+	assert.Equal(t, []string{"100% wrong"}, logger.warn)
+}
+
+func TestOptionsWarnNoLogger(t *testing.T) {
+	opts := &options{}
+
+	assert.NotPanics(t, func() {
+		opts.warn("warning")
+	})
+}
+
+func TestWithMetricsOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithMetricsOption{})
+}
+
+func TestWithMetricsOptionApply(t *testing.T) {
+	metrics := &fakeMetrics{}
+	obj := WithMetricsOption{
+		metrics: metrics,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Same(t, metrics, opts.metrics)
+}
+
+func TestWithMetrics(t *testing.T) {
+	metrics := &fakeMetrics{}
+
+	result := WithMetrics(metrics)
+
+	assert.Equal(t, WithMetricsOption{
+		metrics: metrics,
+	}, result)
+}
+
+func TestWithTracerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithTracerOption{})
+}
+
+func TestWithTracerOptionApply(t *testing.T) {
+	tracer := &fakeTracer{}
+	obj := WithTracerOption{
+		tracer: tracer,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Same(t, tracer, opts.tracer)
+}
+
+func TestWithTracer(t *testing.T) {
+	tracer := &fakeTracer{}
+
+	result := WithTracer(tracer)
+
+	assert.Equal(t, WithTracerOption{
+		tracer: tracer,
+	}, result)
+}
+
+func TestWithHandleGateOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithHandleGateOption{})
+}
+
+func TestWithHandleGateOptionApply(t *testing.T) {
+	obj := WithHandleGateOption{
+		gate: func(idx int, totals Totals) bool {
+			return idx < totals.TotalItems
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.gate)
+	assert.True(t, opts.gate(2, Totals{TotalItems: 3}))
+	assert.False(t, opts.gate(3, Totals{TotalItems: 3}))
+}
+
+func TestWithHandleGate(t *testing.T) {
+	result := WithHandleGate(func(idx int, totals Totals) bool {
+		return true
+	})
+
+	assert.NotNil(t, result.gate)
+}
+
+func TestWithErrorHandlerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithErrorHandlerOption{})
+}
+
+func TestWithErrorHandlerOptionApply(t *testing.T) {
+	var reported error
+	obj := WithErrorHandlerOption{
+		handler: func(ctx context.Context, req PageRequest, err error) {
+			reported = err
+		},
+		ctxErrors: true,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.onError)
+	opts.onError(context.Background(), PageRequest{}, assert.AnError)
+	assert.Same(t, assert.AnError, reported)
+	assert.True(t, opts.ctxErrors)
+}
+
+func TestWithErrorHandler(t *testing.T) {
+	result := WithErrorHandler(func(ctx context.Context, req PageRequest, err error) {}, true)
+
+	assert.NotNil(t, result.handler)
+	assert.True(t, result.ctxErrors)
+}
+
+func TestWithAllowEmptyPagesOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithAllowEmptyPagesOption{})
+}
+
+func TestWithAllowEmptyPagesOptionApply(t *testing.T) {
+	obj := WithAllowEmptyPagesOption{}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.True(t, opts.allowEmpty)
+}
+
+func TestWithAllowEmptyPages(t *testing.T) {
+	result := WithAllowEmptyPages()
+
+	assert.Equal(t, WithAllowEmptyPagesOption{}, result)
+}
+
+func TestWithCompactPageMapOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithCompactPageMapOption{})
+}
+
+func TestWithCompactPageMapOptionApply(t *testing.T) {
+	obj := WithCompactPageMapOption{}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.True(t, opts.compact)
+}
+
+func TestWithCompactPageMap(t *testing.T) {
+	result := WithCompactPageMap()
+
+	assert.Equal(t, WithCompactPageMapOption{}, result)
+}
+
+func TestWithBatchedRequestsOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithBatchedRequestsOption{})
+}
+
+func TestWithBatchedRequestsOptionApply(t *testing.T) {
+	obj := WithBatchedRequestsOption{}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.True(t, opts.batch)
+}
+
+func TestWithBatchedRequests(t *testing.T) {
+	result := WithBatchedRequests()
+
+	assert.Equal(t, WithBatchedRequestsOption{}, result)
+}
+
+func TestWithRateLimiterOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithRateLimiterOption{})
+}
+
+func TestWithRateLimiterOptionApply(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	obj := WithRateLimiterOption{
+		limiter: limiter,
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Same(t, limiter, opts.limiter)
+}
+
+func TestWithRateLimiter(t *testing.T) {
+	limiter := rate.NewLimiter(rate.Inf, 1)
+
+	result := WithRateLimiter(limiter)
+
+	assert.Equal(t, WithRateLimiterOption{
+		limiter: limiter,
+	}, result)
+}
+
+func TestWithResultCallbackOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithResultCallbackOption[string]{})
+}
+
+func TestWithResultCallbackOptionApply(t *testing.T) {
+	var result []string
+	obj := WithResultCallbackOption[string]{
+		callback: func(items []string) {
+			result = items
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	callback, ok := opts.result.(func(items []string))
+	require.True(t, ok)
+	callback([]string{"foo"})
+	assert.Equal(t, []string{"foo"}, result)
+}
+
+func TestWithResultCallback(t *testing.T) {
+	result := WithResultCallback(func(items []string) {})
+
+	assert.NotNil(t, result.callback)
+}
+
+func TestWithLimitOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithLimitOption(0))
+}
+
+func TestWithLimitOptionApply(t *testing.T) {
+	obj := WithLimitOption(5)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 5, opts.limit)
+}
+
+func TestWithLimit(t *testing.T) {
+	result := WithLimit(5)
+
+	assert.Equal(t, WithLimitOption(5), result)
+}
+
+func TestWithRetryOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithRetryOption{})
+}
+
+func TestWithRetryOptionApply(t *testing.T) {
+	obj := WithRetryOption{
+		attempts: 3,
+		backoff: func(attempt int) time.Duration {
+			return time.Duration(attempt) * time.Second
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 3, opts.attempts)
+	require.NotNil(t, opts.backoff)
+	assert.Equal(t, 2*time.Second, opts.backoff(2))
+}
+
+func TestWithRetry(t *testing.T) {
+	result := WithRetry(3, func(attempt int) time.Duration {
+		return time.Second
+	})
+
+	assert.Equal(t, 3, result.attempts)
+	assert.NotNil(t, result.backoff)
+}
+
+func TestWithRetryBudgetOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithRetryBudgetOption(0))
+}
+
+func TestWithRetryBudgetOptionApply(t *testing.T) {
+	obj := WithRetryBudgetOption(4)
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, 4, opts.retries)
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	result := WithRetryBudget(4)
+
+	assert.Equal(t, WithRetryBudgetOption(4), result)
+}
+
+func TestWithIntraPageOrderOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithIntraPageOrderOption(nil))
+}
+
+func TestWithIntraPageOrderOptionApply(t *testing.T) {
+	obj := WithIntraPageOrderOption(func(pageLen int) []int {
+		return []int{pageLen}
+	})
+	opts := options{}
+
+	obj.apply(&opts)
+
+	require.NotNil(t, opts.order)
+	assert.Equal(t, []int{3}, opts.order(3))
+}
+
+func TestWithIntraPageOrder(t *testing.T) {
+	result := WithIntraPageOrder(func(pageLen int) []int {
+		return []int{pageLen}
+	})
+
+	require.NotNil(t, result)
+	assert.Equal(t, []int{3}, result(3))
+}
+
+func TestWithConcurrencySamplerOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), WithConcurrencySamplerOption{})
+}
+
+func TestWithConcurrencySamplerOptionApply(t *testing.T) {
+	var sampled []int
+	obj := WithConcurrencySamplerOption{
+		interval: time.Second,
+		fn: func(active int) {
+			sampled = append(sampled, active)
+		},
+	}
+	opts := options{}
+
+	obj.apply(&opts)
+
+	assert.Equal(t, time.Second, opts.sampling)
+	require.NotNil(t, opts.sampler)
+	opts.sampler(3)
+	assert.Equal(t, []int{3}, sampled)
+}
+
+func TestWithConcurrencySampler(t *testing.T) {
+	result := WithConcurrencySampler(time.Second, func(active int) {})
+
+	assert.Equal(t, time.Second, result.interval)
+	assert.NotNil(t, result.fn)
+}
+
+func TestOptionsOptionImplementsOption(t *testing.T) {
+	assert.Implements(t, (*Option)(nil), OptionsOption{})
+}
+
+func TestOptionsOptionApply(t *testing.T) {
+	opts := options{}
+	o1 := &mockOption{}
+	o1.On("apply", &opts)
+	o2 := &mockOption{}
+	o2.On("apply", &opts)
+	obj := OptionsOption{o1, o2}
+
+	obj.apply(&opts)
+
+	o1.AssertExpectations(t)
+	o2.AssertExpectations(t)
+}
+
+func TestOptions(t *testing.T) {
+	result := Options(PerPage(5), Capacity(10))
+
+	assert.Equal(t, OptionsOption{PerPage(5), Capacity(10)}, result)
+}
+
+func TestOptionsAppliesAll(t *testing.T) {
+	opts := options{}
+	obj := Options(PerPage(5), TotalPages(3), WithAutoStrategy())
+
+	obj.apply(&opts)
+
+	assert.Equal(t, options{
+		perPage:    5,
+		totalPages: 3,
+		auto:       true,
+	}, opts)
+}
+
+type mockUpdate struct {
+	mock.Mock
+}
+
+func (m *mockUpdate) applyUpdate(depag *Depaginator[string]) { //nolint:unused
+	m.Called(depag)
+}
+
+func TestCancelerForImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), cancelerFor[string]{})
+}
+
+func TestCancelerForApplyUpdate(t *testing.T) {
+	cancelFn := func() {}
+	obj := cancelerFor[string]{
+		page:     5,
+		cancelFn: cancelFn,
+	}
+	depag := &Depaginator[string]{
+		cancelers: map[int]context.CancelFunc{},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Contains(t, depag.cancelers, 5)
+}
+
+func TestCancelerForApplyUpdateFailed(t *testing.T) {
+	cancel := &mockCancelFn{}
+	cancel.On("Cancel")
+	obj := cancelerFor[string]{
+		page:     5,
+		cancelFn: cancel.Cancel,
+	}
+	depag := &Depaginator[string]{
+		failed:    true,
+		cancelers: map[int]context.CancelFunc{},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.NotContains(t, depag.cancelers, 5)
+	cancel.AssertExpectations(t)
+}
+
+func TestCancelerForApplyUpdateBeyondLimit(t *testing.T) {
+	cancel := &mockCancelFn{}
+	cancel.On("Cancel")
+	obj := cancelerFor[string]{
+		page:     5,
+		cancelFn: cancel.Cancel,
+	}
+	depag := &Depaginator[string]{
+		perPage:   3,
+		limit:     15,
+		cancelers: map[int]context.CancelFunc{},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.NotContains(t, depag.cancelers, 5)
+	cancel.AssertExpectations(t)
+}
+
+func TestWithdrawCancelerImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), withdrawCanceler[string](0))
+}
+
+func TestWithdrawCancelerApplyUpdate(t *testing.T) {
+	obj := withdrawCanceler[string](5)
+	depag := &Depaginator[string]{
+		cancelers: map[int]context.CancelFunc{
+			5: nil,
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.NotContains(t, depag.cancelers, 5)
+}
+
+func TestWithdrawCancelerApplyUpdateOverlap(t *testing.T) {
+	obj := withdrawCanceler[string](5)
+	depag := &Depaginator[string]{
+		cancelers: map[int]context.CancelFunc{},
+		overlaps: map[int]*overlapSlot{
+			5: newOverlapSlot(PageRequest{PageIndex: 5}),
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.NotContains(t, depag.overlaps, 5)
+}
+
+func TestErrorSaverImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), errorSaver[string]{})
+}
+
+func TestErrorSaverApplyUpdateBase(t *testing.T) {
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: assert.AnError,
+	}
+	depag := &Depaginator[string]{}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, &Depaginator[string]{
+		errors: []error{
+			PageError{
+				PageRequest: PageRequest{
+					PageIndex: 5,
+				},
+				Err: assert.AnError,
+			},
+		},
+		history: []PageMeta{
+			{
+				Request: PageRequest{
+					PageIndex: 5,
+				},
+				Err: assert.AnError,
+			},
+		},
+		pageErrs: map[int]error{
+			5: assert.AnError,
+		},
+		failures: 1,
+	}, depag)
+}
+
+func TestErrorSaverApplyUpdateCanceled(t *testing.T) {
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: context.Canceled,
+	}
+	depag := &Depaginator[string]{}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, &Depaginator[string]{}, depag)
+}
+
+func TestErrorSaverApplyUpdateDeadlineExceeded(t *testing.T) {
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: context.DeadlineExceeded,
+	}
+	depag := &Depaginator[string]{}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, &Depaginator[string]{}, depag)
+}
+
+func TestErrorSaverApplyUpdateErrorHandler(t *testing.T) {
+	ctx := context.Background()
+	var reported []error
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: assert.AnError,
+	}
+	depag := &Depaginator[string]{
+		ctx: ctx,
+		onError: func(ctx context.Context, req PageRequest, err error) {
+			assert.Equal(t, 5, req.PageIndex)
+			reported = append(reported, err)
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, []error{assert.AnError}, reported)
+	assert.Len(t, depag.errors, 1)
+}
+
+func TestErrorSaverApplyUpdateErrorHandlerCanceled(t *testing.T) {
+	ctx := context.Background()
+	var reported []error
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: context.Canceled,
+	}
+	depag := &Depaginator[string]{
+		ctx: ctx,
+		onError: func(ctx context.Context, req PageRequest, err error) {
+			reported = append(reported, err)
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Empty(t, reported)
+	assert.Empty(t, depag.errors)
+}
+
+func TestErrorSaverApplyUpdateErrorHandlerContextErrors(t *testing.T) {
+	ctx := context.Background()
+	var reported []error
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: context.Canceled,
+	}
+	depag := &Depaginator[string]{
+		ctx: ctx,
+		onError: func(ctx context.Context, req PageRequest, err error) {
+			reported = append(reported, err)
+		},
+		ctxErrors: true,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, []error{context.Canceled}, reported)
+	assert.Empty(t, depag.errors)
+}
+
+func TestErrorSaverApplyUpdateFailFast(t *testing.T) {
+	cancel6 := &mockCancelFn{}
+	cancel6.On("Cancel")
+	cancel7 := &mockCancelFn{}
+	cancel7.On("Cancel")
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: assert.AnError,
+	}
+	depag := &Depaginator[string]{
+		failFast: true,
+		cancelers: map[int]context.CancelFunc{
+			6: cancel6.Cancel,
+			7: cancel7.Cancel,
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.True(t, depag.failed)
+	assert.Len(t, depag.errors, 1)
+	cancel6.AssertExpectations(t)
+	cancel7.AssertExpectations(t)
+}
+
+func TestErrorSaverApplyUpdateMaxErrorsTolerated(t *testing.T) {
+	cancel6 := &mockCancelFn{}
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: assert.AnError,
+	}
+	depag := &Depaginator[string]{
+		maxErrors: 2,
+		failures:  1,
+		cancelers: map[int]context.CancelFunc{
+			6: cancel6.Cancel,
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.False(t, depag.failed)
+	assert.Equal(t, 2, depag.failures)
+	cancel6.AssertExpectations(t)
+}
+
+func TestErrorSaverApplyUpdateMaxErrorsExceeded(t *testing.T) {
+	cancel6 := &mockCancelFn{}
+	cancel6.On("Cancel")
+	obj := errorSaver[string]{
+		req: PageRequest{
+			PageIndex: 5,
+		},
+		err: assert.AnError,
+	}
+	depag := &Depaginator[string]{
+		maxErrors: 2,
+		failures:  2,
+		cancelers: map[int]context.CancelFunc{
+			6: cancel6.Cancel,
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.True(t, depag.failed)
+	assert.Equal(t, 3, depag.failures)
+	cancel6.AssertExpectations(t)
+}
+
+func TestItemHandlerIsShort(t *testing.T) {
+	depag := &Depaginator[string]{
+		perPage: 3,
+	}
+
+	assert.False(t, itemHandler[string]{page: []string{"a", "b", "c"}}.isShort(depag))
+	assert.True(t, itemHandler[string]{page: []string{"a"}}.isShort(depag))
+	assert.True(t, itemHandler[string]{}.isShort(depag))
+}
+
+func TestItemHandlerIsShortSparse(t *testing.T) {
+	depag := &Depaginator[string]{
+		perPage: 3,
+		sparse:  true,
+	}
+
+	assert.False(t, itemHandler[string]{page: []string{"a", "b", "c"}}.isShort(depag))
+	assert.True(t, itemHandler[string]{page: []string{"a"}}.isShort(depag))
+	assert.False(t, itemHandler[string]{}.isShort(depag))
+}
+
+// handleDaemon stands in for the daemon, applying the handleDone
+// updates sent as pages finish being handled.
+func handleDaemon[T any](t *testing.T, depag *Depaginator[T]) {
+	depag.updates = make(chan update[T])
+	t.Cleanup(func() {
+		close(depag.updates)
+	})
+	go func() {
+		for u := range depag.updates {
+			if done, ok := u.(handleDone[T]); ok {
+				done.applyUpdate(depag)
+			}
+		}
+	}()
+}
+
+func TestItemErrorSaverImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), itemErrorSaver[string]{})
+}
+
+func TestItemErrorSaverApplyUpdate(t *testing.T) {
+	obj := itemErrorSaver[string]{
+		idx: 5,
+		err: assert.AnError,
+	}
+	depag := &Depaginator[string]{}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, []error{
+		ItemError{
+			Index: 5,
+			Err:   assert.AnError,
+		},
+	}, depag.errors)
+}
+
+func TestItemHandlerImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), itemHandler[string]{})
+}
+
+func TestItemHandlerApplyupdateBase(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	handler.On("Handle", ctx, 27, "baz")
+	cancel4 := &mockCancelFn{}
+	cancel6 := &mockCancelFn{}
+	cancel6.On("Cancel")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		perPage: 5,
+		handler: handler,
+		cancelers: map[int]context.CancelFunc{
+			4: cancel4.Cancel,
+			6: cancel6.Cancel,
+		},
+		wg: &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, 6, depag.totalPages)
+	assert.Equal(t, 28, depag.totalItems)
+	assert.True(t, depag.retrieved.IsSet(5))
+	cancel4.AssertExpectations(t)
+	cancel6.AssertExpectations(t)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateMorePages(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	handler.On("Handle", ctx, 27, "baz")
+	handler.On("Handle", ctx, 28, "bink")
+	handler.On("Handle", ctx, 29, "qux")
+	cancel4 := &mockCancelFn{}
+	cancel6 := &mockCancelFn{}
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz", "bink", "qux"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		perPage: 5,
+		handler: handler,
+		cancelers: map[int]context.CancelFunc{
+			4: cancel4.Cancel,
+			6: cancel6.Cancel,
+		},
+		wg: &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, 0, depag.totalPages)
+	assert.Equal(t, 0, depag.totalItems)
+	cancel4.AssertExpectations(t)
+	cancel6.AssertExpectations(t)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateHistory(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	obj := itemHandler[string]{
+		idx:  5,
+		req:  "five",
+		page: []string{"foo", "bar"},
+	}
+	depag := &Depaginator[string]{
+		ctx:       ctx,
+		perPage:   5,
+		handler:   handler,
+		cancelers: map[int]context.CancelFunc{},
+		wg:        &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, []PageMeta{
+		{
+			Request: PageRequest{
+				PageIndex: 5,
+				Request:   "five",
+			},
+			ItemCount:  2,
+			TotalItems: 27,
+			TotalPages: 6,
+			PerPage:    5,
+		},
+	}, depag.history)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdatePartial(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar"},
+	}
+	depag := &Depaginator[string]{
+		ctx:       ctx,
+		perPage:   5,
+		partial:   true,
+		received:  map[int]int{},
+		handler:   handler,
+		cancelers: map[int]context.CancelFunc{},
+		wg:        &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, map[int]int{5: 2}, depag.received)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateByteBudget(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 15, "foo")
+	handler.On("Handle", ctx, 16, "bar")
+	handler.On("Handle", ctx, 17, "baz")
+	cancel6 := &mockCancelFn{}
+	cancel6.On("Cancel")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		perPage: 3,
+		budget:  20,
+		spent:   15,
+		sizeOf: func(items []string) int64 {
+			return int64(len(items) * 3)
+		},
+		handler: handler,
+		cancelers: map[int]context.CancelFunc{
+			6: cancel6.Cancel,
+		},
+		wg: &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, int64(24), depag.spent)
+	cancel6.AssertExpectations(t)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateLimitReached(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 3, "foo")
+	handler.On("Handle", ctx, 4, "bar")
+	handler.On("Handle", ctx, 5, "baz")
+	cancel0 := &mockCancelFn{}
+	cancel2 := &mockCancelFn{}
+	cancel2.On("Cancel")
+	obj := itemHandler[string]{
+		idx:  1,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		perPage: 3,
+		limit:   6,
+		handler: handler,
+		cancelers: map[int]context.CancelFunc{
+			0: cancel0.Cancel,
+			2: cancel2.Cancel,
+		},
+		wg: &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	cancel0.AssertExpectations(t)
+	cancel2.AssertExpectations(t)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateFailed(t *testing.T) {
+	handler := &mockHandler{}
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		perPage: 3,
+		failed:  true,
+		handler: handler,
+		wg:      &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Empty(t, depag.history)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateCompleted(t *testing.T) {
+	handler := &mockHandler{}
+	obj := itemHandler[string]{
+		idx:  0,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		perPage:   3,
+		handler:   handler,
+		completed: &pageMap{},
+		wg:        &sync.WaitGroup{},
+	}
+	depag.completed.CheckAndSet(0)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Len(t, depag.history, 1)
+	assert.Equal(t, 0, depag.buffered)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerApplyupdateMarkedLast(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	handler.On("Handle", ctx, 27, "baz")
+	handler.On("Handle", ctx, 28, "bink")
+	handler.On("Handle", ctx, 29, "qux")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz", "bink", "qux"},
+	}
+	depag := &Depaginator[string]{
+		ctx:        ctx,
+		totalPages: 6,
+		perPage:    5,
+		marked:     true,
+		handler:    handler,
+		cancelers:  map[int]context.CancelFunc{},
+		wg:         &sync.WaitGroup{},
+	}
+	handleDaemon(t, depag)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, 6, depag.totalPages)
+	assert.Equal(t, 30, depag.totalItems)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	handler.On("Handle", ctx, 27, "baz")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		handler: handler,
+		wg:      &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+	handleDaemon(t, depag)
+
+	obj.handle(depag, 25)
+
+	depag.wg.Wait()
+	assert.Equal(t, int64(3), depag.handled.Load())
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerHandleStateful(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockStatefulHandler{}
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar"},
+	}
+	depag := &Depaginator[string]{
+		ctx:      ctx,
+		handler:  Stateful[string](handler),
+		stateful: handler,
+		wg:       &sync.WaitGroup{},
+		updates:  make(chan update[string], DefaultCapacity),
+	}
+	handler.On("Handle", ctx, 25, "foo", depag)
+	handler.On("Handle", ctx, 26, "bar", depag)
+	depag.wg.Add(1)
+
+	obj.handle(depag, 25)
+
+	close(depag.updates)
+	updates := []update[string]{}
+	for u := range depag.updates {
+		updates = append(updates, u)
+	}
+	assert.Equal(t, []update[string]{handleDone[string](5)}, updates)
+	assert.Equal(t, int64(2), depag.handled.Load())
+	handler.AssertExpectations(t)
+}
+
+func TestHandleDoneImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), handleDone[string](0))
+}
+
+func TestHandleDoneApplyUpdate(t *testing.T) {
+	obj := handleDone[string](5)
+	depag := &Depaginator[string]{
+		buffered:  1,
+		completed: &pageMap{},
+		wg:        &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, 0, depag.fetched)
+	assert.Equal(t, 0, depag.buffered)
+	assert.True(t, depag.completed.IsSet(5))
+}
+
+func TestHandleDoneApplyUpdateAdmits(t *testing.T) {
+	obj := handleDone[string](5)
+	waiting := make(chan struct{})
+	depag := &Depaginator[string]{
+		buffered: 1,
+		ahead:    1,
+		waiting:  []chan struct{}{waiting},
+		wg:       &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, 1, depag.admitted)
+	assert.Empty(t, depag.waiting)
+}
+
+func TestHandleDoneApplyUpdateRequests(t *testing.T) {
+	obj := handleDone[string](5)
+	depag := &Depaginator[string]{
+		buffered:  1,
+		completed: &pageMap{},
+		requests: map[int]any{
+			5: "five",
+			6: "six",
+		},
+		wg: &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, map[int]any{6: "six"}, depag.requests)
+}
+
+func TestItemHandlerHandleLimit(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		handler: handler,
+		limit:   30,
+		wg:      &sync.WaitGroup{},
+	}
+	depag.claimed.Store(29)
+	depag.wg.Add(1)
+	handleDaemon(t, depag)
+
+	obj.handle(depag, 25)
+
+	depag.wg.Wait()
+	assert.Equal(t, int64(1), depag.handled.Load())
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerHandleMetrics(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 27, "baz")
+	metrics := &fakeMetrics{}
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		handler: handler,
+		gate: func(idx int, _ Totals) bool {
+			return idx != 26
+		},
+		metrics: metrics,
+		wg:      &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+	handleDaemon(t, depag)
+
+	obj.handle(depag, 25)
+
+	depag.wg.Wait()
+	assert.Equal(t, 2, metrics.handled)
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerHandleOrder(t *testing.T) {
+	ctx := context.Background()
+	var handled []int
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handled = append(handled, args.Int(1))
+	})
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		handler: handler,
+		order: func(pageLen int) []int {
+			return []int{2, -1, 0, 3}
+		},
+		wg: &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+	handleDaemon(t, depag)
+
+	obj.handle(depag, 25)
+
+	depag.wg.Wait()
+	assert.Equal(t, []int{27, 25}, handled)
+	assert.Equal(t, int64(2), depag.handled.Load())
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerHandleTimeout(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", mock.Anything, 25, "foo").Run(func(args mock.Arguments) {
+		<-args[0].(context.Context).Done()
+	})
+	handler.On("Handle", mock.Anything, 26, "bar")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		runCtx:  ctx,
+		handler: handler,
+		timeout: time.Millisecond,
+		wg:      &sync.WaitGroup{},
+		updates: make(chan update[string], DefaultCapacity),
+	}
+	depag.wg.Add(1)
+
+	obj.handle(depag, 25)
+
+	close(depag.updates)
+	updates := []update[string]{}
+	for u := range depag.updates {
+		updates = append(updates, u)
+	}
+	assert.Equal(t, []update[string]{
+		itemErrorSaver[string]{
+			idx: 25,
+			err: context.DeadlineExceeded,
+		},
+		handleDone[string](5),
+	}, updates)
+	assert.Equal(t, int64(2), depag.handled.Load())
+	handler.AssertExpectations(t)
+}
+
+func TestItemHandlerHandleGate(t *testing.T) {
+	ctx := context.Background()
+	handler := &mockHandler{}
+	handler.On("Handle", ctx, 25, "foo")
+	handler.On("Handle", ctx, 26, "bar")
+	obj := itemHandler[string]{
+		idx:  5,
+		page: []string{"foo", "bar", "baz"},
+	}
+	depag := &Depaginator[string]{
+		ctx:     ctx,
+		handler: handler,
+		gate: func(idx int, totals Totals) bool {
+			return idx < totals.TotalItems
+		},
+		wg: &sync.WaitGroup{},
+	}
+	depag.totals.Store(&Totals{TotalItems: 27})
+	depag.wg.Add(1)
+	handleDaemon(t, depag)
+
+	obj.handle(depag, 25)
+
+	depag.wg.Wait()
+	assert.Equal(t, int64(2), depag.handled.Load())
+	handler.AssertExpectations(t)
+}
+
+func TestPageDoneImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), pageDone[string]{})
+}
+
+func TestPageDoneApplyUpdate(t *testing.T) {
+	obj := pageDone[string]{}
+	depag := &Depaginator[string]{
+		wg: &sync.WaitGroup{},
+	}
+	depag.wg.Add(1)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.Equal(t, 1, depag.fetched)
+}
+
+func TestStopImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), stop[string]{})
+}
+
+func TestSnapshotImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), snapshot[string]{})
+}
+
+func TestSnapshotApplyUpdate(t *testing.T) {
+	depag := &Depaginator[string]{}
+	var called *Depaginator[string]
+	obj := snapshot[string]{
+		fn: func(depag *Depaginator[string]) {
+			called = depag
+		},
+		reply: make(chan struct{}),
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Same(t, depag, called)
+	select {
+	case <-obj.reply:
+	default:
+		assert.Fail(t, "snapshot failed to close reply channel")
+	}
+}
+
+func TestTotalItemsImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), totalItems[string](0))
+}
+
+func TestTotalItemsApplyUpdateBase(t *testing.T) {
+	obj := totalItems[string](5)
+	depag := &Depaginator[string]{
+		totalItems: 3,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 5, depag.totalItems)
+}
+
+func TestTotalItemsApplyUpdateZero(t *testing.T) {
+	obj := totalItems[string](0)
+	depag := &Depaginator[string]{
+		totalItems: 3,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 3, depag.totalItems)
+}
+
+func TestTotalPagesImplementsUpdate(t *testing.T) {
 	assert.Implements(t, (*update[string])(nil), totalPages[string](0))
 }
 
 func TestTotalPagesApplyUpdateBase(t *testing.T) {
 	obj := totalPages[string](5)
 	depag := &Depaginator[string]{
-		totalPages: 3,
+		totalPages: 3,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 5, depag.totalPages)
+}
+
+func TestTotalPagesApplyUpdateZero(t *testing.T) {
+	obj := totalPages[string](0)
+	depag := &Depaginator[string]{
+		totalPages: 3,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 3, depag.totalPages)
+}
+
+func TestTotalPagesApplyUpdateMarked(t *testing.T) {
+	obj := totalPages[string](5)
+	depag := &Depaginator[string]{
+		totalPages: 3,
+		marked:     true,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 3, depag.totalPages)
+}
+
+func TestLastPageImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), lastPage[string](0))
+}
+
+func TestLastPageApplyUpdateBase(t *testing.T) {
+	cancel2 := &mockCancelFn{}
+	cancel3 := &mockCancelFn{}
+	cancel4 := &mockCancelFn{}
+	cancel4.On("Cancel")
+	obj := lastPage[string](3)
+	depag := &Depaginator[string]{
+		totalPages: 10,
+		cancelers: map[int]context.CancelFunc{
+			2: cancel2.Cancel,
+			3: cancel3.Cancel,
+			4: cancel4.Cancel,
+		},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 4, depag.totalPages)
+	assert.True(t, depag.marked)
+	cancel2.AssertExpectations(t)
+	cancel3.AssertExpectations(t)
+	cancel4.AssertExpectations(t)
+}
+
+func TestLastPageApplyUpdateNegative(t *testing.T) {
+	obj := lastPage[string](-1)
+	depag := &Depaginator[string]{
+		totalPages: 10,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 10, depag.totalPages)
+	assert.False(t, depag.marked)
+}
+
+func TestPerPageImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), perPage[string](0))
+}
+
+func TestPerPageApplyUpdateBase(t *testing.T) {
+	obj := perPage[string](5)
+	depag := &Depaginator[string]{
+		perPage: 3,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 5, depag.perPage)
+}
+
+func TestPerPageApplyUpdateZero(t *testing.T) {
+	obj := perPage[string](0)
+	depag := &Depaginator[string]{
+		perPage: 3,
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 3, depag.perPage)
+}
+
+func TestReservedImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), reserved[string]{})
+}
+
+func TestReservedApplyUpdate(t *testing.T) {
+	depag := &Depaginator[string]{
+		workers: newTaskQueue(1),
+	}
+	depag.workers.Reserve()
+	u := &mockUpdate{}
+	u.On("applyUpdate", depag)
+	obj := reserved[string]{u}
+
+	obj.applyUpdate(depag)
+
+	u.AssertExpectations(t)
+	assert.Equal(t, 0, depag.workers.reserved)
+}
+
+func TestAdmissionImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), admission[string](nil))
+}
+
+func TestAdmissionApplyUpdateAdmitted(t *testing.T) {
+	depag := &Depaginator[string]{
+		ahead:    2,
+		admitted: 1,
+	}
+	obj := admission[string](make(chan struct{}))
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 2, depag.admitted)
+	assert.Empty(t, depag.waiting)
+	select {
+	case <-obj:
+	default:
+		assert.Fail(t, "retrieval not admitted")
+	}
+}
+
+func TestAdmissionApplyUpdateWaiting(t *testing.T) {
+	depag := &Depaginator[string]{
+		ahead:    2,
+		admitted: 1,
+		buffered: 1,
 	}
+	obj := admission[string](make(chan struct{}))
 
 	obj.applyUpdate(depag)
 
-	assert.Equal(t, 5, depag.totalPages)
+	assert.Equal(t, 1, depag.admitted)
+	assert.Equal(t, []chan struct{}{obj}, depag.waiting)
+	select {
+	case <-obj:
+		assert.Fail(t, "retrieval admitted")
+	default:
+	}
 }
 
-func TestTotalPagesApplyUpdateZero(t *testing.T) {
-	obj := totalPages[string](0)
+func TestAdmissionApplyUpdateInOrder(t *testing.T) {
 	depag := &Depaginator[string]{
-		totalPages: 3,
+		ahead:    2,
+		admitted: 1,
+		buffered: 2,
+		inOrder:  newPageOrder(),
 	}
+	depag.inOrder.queued[5] = func() {}
+	depag.inOrder.queued[6] = func() {}
+	obj := admission[string](make(chan struct{}))
 
 	obj.applyUpdate(depag)
 
-	assert.Equal(t, 3, depag.totalPages)
+	assert.Equal(t, 2, depag.admitted)
+	assert.Empty(t, depag.waiting)
 }
 
-func TestPerPageImplementsUpdate(t *testing.T) {
-	assert.Implements(t, (*update[string])(nil), perPage[string](0))
+func TestWithdrawalImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), withdrawal[string](nil))
 }
 
-func TestPerPageApplyUpdateBase(t *testing.T) {
-	obj := perPage[string](5)
+func TestWithdrawalApplyUpdateAdmitted(t *testing.T) {
+	waiting := make(chan struct{})
 	depag := &Depaginator[string]{
-		perPage: 3,
+		ahead:    1,
+		admitted: 1,
+		waiting:  []chan struct{}{waiting},
 	}
+	obj := withdrawal[string](make(chan struct{}))
 
 	obj.applyUpdate(depag)
 
-	assert.Equal(t, 5, depag.perPage)
+	assert.Equal(t, 1, depag.admitted)
+	assert.Empty(t, depag.waiting)
+	select {
+	case <-waiting:
+	default:
+		assert.Fail(t, "waiting retrieval not admitted")
+	}
 }
 
-func TestPerPageApplyUpdateZero(t *testing.T) {
-	obj := perPage[string](0)
+func TestWithdrawalApplyUpdateWaiting(t *testing.T) {
+	ticket := make(chan struct{})
+	other := make(chan struct{})
 	depag := &Depaginator[string]{
-		perPage: 3,
+		ahead:    1,
+		admitted: 1,
+		waiting:  []chan struct{}{ticket, other},
 	}
+	obj := withdrawal[string](ticket)
 
 	obj.applyUpdate(depag)
 
-	assert.Equal(t, 3, depag.perPage)
+	assert.Equal(t, 1, depag.admitted)
+	assert.Equal(t, []chan struct{}{other}, depag.waiting)
 }
 
 func TestBundleImplementsUpdate(t *testing.T) {
@@ -501,6 +2724,74 @@ func TestPageRequestImplementsUpdate(t *testing.T) {
 	assert.Implements(t, (*update[string])(nil), pageRequest[string]{})
 }
 
+func TestPlannedPageImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), plannedPage[string](0))
+}
+
+// planPage applies a [plannedPage] update to a dry run [Depaginator]
+// with the specified totals, returning the pages then requested.
+func planPage(idx, items, pages, per int) (*Depaginator[string], []int) {
+	depag := &Depaginator[string]{
+		totalItems: items,
+		totalPages: pages,
+		perPage:    per,
+		dryRun:     true,
+		pages:      &pageMap{},
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string], DefaultCapacity),
+	}
+
+	plannedPage[string](idx).applyUpdate(depag)
+
+	requested := []int{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for u := range depag.updates {
+			if p, ok := u.(plannedPage[string]); ok {
+				requested = append(requested, int(p))
+			}
+			if _, ok := u.(pageDone[string]); ok {
+				depag.wg.Done()
+			}
+		}
+	}()
+	depag.wg.Wait()
+	close(depag.updates)
+	<-done
+	sort.Ints(requested)
+
+	return depag, requested
+}
+
+func TestPlannedPageApplyUpdateTotalPages(t *testing.T) {
+	depag, requested := planPage(0, 0, 4, 0)
+
+	assert.Equal(t, []int{0}, depag.planned)
+	assert.Equal(t, []int{1, 2, 3}, requested)
+}
+
+func TestPlannedPageApplyUpdateTotalItems(t *testing.T) {
+	depag, requested := planPage(0, 11, 0, 3)
+
+	assert.Equal(t, []int{0}, depag.planned)
+	assert.Equal(t, []int{1, 2, 3}, requested)
+}
+
+func TestPlannedPageApplyUpdateLaterPage(t *testing.T) {
+	depag, requested := planPage(2, 0, 4, 0)
+
+	assert.Equal(t, []int{2}, depag.planned)
+	assert.Equal(t, []int{3}, requested)
+}
+
+func TestPlannedPageApplyUpdateNoTotals(t *testing.T) {
+	depag, requested := planPage(0, 11, 0, 0)
+
+	assert.Equal(t, []int{0}, depag.planned)
+	assert.Empty(t, requested)
+}
+
 func TestPageRequestApplyUpdateBase(t *testing.T) {
 	ctx := context.Background()
 	pager := &mockPageGetter{}
@@ -510,6 +2801,7 @@ func TestPageRequestApplyUpdateBase(t *testing.T) {
 	}
 	depag := &Depaginator[string]{
 		ctx:        ctx,
+		runCtx:     ctx,
 		totalPages: 5,
 		pager:      pager,
 		pages:      &pageMap{},
@@ -538,6 +2830,194 @@ func TestPageRequestApplyUpdateBase(t *testing.T) {
 	pager.AssertExpectations(t)
 }
 
+func TestPageRequestApplyUpdateFrontier(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := pageRequest[string]{
+		idx: 3,
+	}
+	depag := &Depaginator[string]{
+		pager:    pager,
+		pages:    &pageMap{},
+		frontier: 5,
+		wg:       &sync.WaitGroup{},
+		updates:  make(chan update[string], DefaultCapacity),
+	}
+	depag.pages.CheckAndSet(3)
+
+	obj.applyUpdate(depag)
+
+	assert.Equal(t, 5, depag.frontier)
+	close(depag.updates)
+}
+
+func TestPageRequestApplyUpdateCompactCompleted(t *testing.T) {
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		completed: &pageMap{bits: []uint{^uint(0), 1}},
+		compact:   true,
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) {}),
+	}
+
+	pageRequest[string]{idx: CompactWindow + 4*bits.UintSize}.applyUpdate(depag)
+
+	assert.Equal(t, &pageMap{bits: []uint{1}, base: 1}, depag.completed)
+	assert.True(t, depag.handledBefore(0))
+	assert.True(t, depag.handledBefore(bits.UintSize))
+	assert.False(t, depag.handledBefore(bits.UintSize+1))
+}
+
+func TestPageRequestApplyUpdateRequests(t *testing.T) {
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) {}),
+		encodeReq: func(req any) ([]byte, error) {
+			return nil, nil
+		},
+	}
+
+	pageRequest[string]{idx: 3, req: "three"}.applyUpdate(depag)
+	pageRequest[string]{idx: 4}.applyUpdate(depag)
+
+	assert.Equal(t, map[int]any{3: "three"}, depag.requests)
+}
+
+func TestResumeImplementsUpdate(t *testing.T) {
+	assert.Implements(t, (*update[string])(nil), resume[string]{})
+}
+
+func TestResumeApplyUpdate(t *testing.T) {
+	var tasks []func()
+	obj := resume[string]{
+		{PageIndex: 2, Request: "two"},
+		{PageIndex: 4, Request: "four"},
+	}
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) { tasks = append(tasks, task) }),
+		encodeReq: func(req any) ([]byte, error) {
+			return nil, nil
+		},
+	}
+	depag.wg.Add(1)
+
+	obj.applyUpdate(depag)
+
+	assert.Len(t, tasks, 2)
+	assert.True(t, depag.pages.IsSet(2))
+	assert.True(t, depag.pages.IsSet(4))
+	assert.Equal(t, map[int]any{2: "two", 4: "four"}, depag.requests)
+	depag.wg.Add(-2)
+	depag.wg.Wait()
+}
+
+func TestNextRequestApplyUpdateBase(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := nextRequest[string]{
+		req: "next",
+	}
+	ctx := context.Background()
+	depag := &Depaginator[string]{
+		ctx:        ctx,
+		runCtx:     ctx,
+		totalPages: 5,
+		pager:      pager,
+		pages:      &pageMap{},
+		frontier:   2,
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string], DefaultCapacity),
+	}
+	pager.On("GetPage", mock.Anything, depag, PageRequest{
+		PageIndex: 3,
+		Request:   "next",
+	}).Return([]string{}, nil)
+	pager.On("GetPage", mock.Anything, depag, PageRequest{
+		PageIndex: 4,
+		Request:   "next",
+	}).Return([]string{}, nil)
+	depag.pages.CheckAndSet(2)
+
+	obj.applyUpdate(depag)
+	obj.applyUpdate(depag)
+
+	go func() {
+		for u := range depag.updates {
+			if _, ok := u.(pageDone[string]); ok {
+				depag.wg.Done()
+			}
+		}
+	}()
+	depag.wg.Wait()
+	close(depag.updates)
+	assert.Equal(t, 4, depag.frontier)
+	assert.True(t, depag.pages.IsSet(3))
+	assert.True(t, depag.pages.IsSet(4))
+	pager.AssertExpectations(t)
+}
+
+func TestNextRequestApplyUpdateNoMorePages(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := nextRequest[string]{
+		req: "next",
+	}
+	depag := &Depaginator[string]{
+		totalPages: 5,
+		pager:      pager,
+		pages:      &pageMap{},
+		frontier:   4,
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string], DefaultCapacity),
+	}
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	close(depag.updates)
+	assert.Equal(t, 4, depag.frontier)
+	assert.False(t, depag.pages.IsSet(5))
+	pager.AssertExpectations(t)
+}
+
+func TestPageRequestApplyUpdateOverlap(t *testing.T) {
+	var tasks []func()
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) { tasks = append(tasks, task) }),
+		overlap: func(existing, incoming PageRequest) PageRequest {
+			return incoming
+		},
+	}
+
+	pageRequest[string]{idx: 3, req: "one"}.applyUpdate(depag)
+	pageRequest[string]{idx: 3, req: "two"}.applyUpdate(depag)
+
+	assert.Len(t, tasks, 1)
+	require.Contains(t, depag.overlaps, 3)
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "two"}, depag.overlaps[3].Take(PageRequest{}))
+}
+
+func TestPageRequestApplyUpdateOverlapStarted(t *testing.T) {
+	calls := 0
+	depag := &Depaginator[string]{
+		pages:     &pageMap{},
+		wg:        &sync.WaitGroup{},
+		scheduler: SchedulerFunc(func(task func()) {}),
+		overlap: func(existing, incoming PageRequest) PageRequest {
+			calls++
+			return incoming
+		},
+	}
+
+	pageRequest[string]{idx: 3, req: "one"}.applyUpdate(depag)
+	depag.overlaps[3].Take(PageRequest{})
+	pageRequest[string]{idx: 3, req: "two"}.applyUpdate(depag)
+
+	assert.Equal(t, 0, calls)
+}
+
 func TestPageRequestApplyUpdatePageVisited(t *testing.T) {
 	pager := &mockPageGetter{}
 	obj := pageRequest[string]{
@@ -565,6 +3045,145 @@ func TestPageRequestApplyUpdatePageVisited(t *testing.T) {
 	pager.AssertExpectations(t)
 }
 
+func TestPageRequestApplyUpdateCompleted(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := pageRequest[string]{
+		idx: 3,
+		req: "three",
+	}
+	depag := &Depaginator[string]{
+		totalPages: 5,
+		pager:      pager,
+		pages:      &pageMap{},
+		completed:  &pageMap{},
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string], DefaultCapacity),
+	}
+	depag.completed.CheckAndSet(3)
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	close(depag.updates)
+	updates := []update[string]{}
+	for u := range depag.updates {
+		updates = append(updates, u)
+	}
+	assert.Equal(t, []update[string]{}, updates)
+	assert.False(t, depag.pages.IsSet(3))
+	pager.AssertExpectations(t)
+}
+
+func TestPageRequestApplyUpdateBudgetExhausted(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := pageRequest[string]{
+		idx: 3,
+		req: "three",
+	}
+	depag := &Depaginator[string]{
+		budget: 20,
+		spent:  20,
+		sizeOf: func(items []string) int64 {
+			return int64(len(items))
+		},
+		pager: pager,
+		pages: &pageMap{},
+		wg:    &sync.WaitGroup{},
+	}
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.False(t, depag.pages.IsSet(3))
+	pager.AssertExpectations(t)
+}
+
+func TestPageRequestApplyUpdateFailed(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := pageRequest[string]{
+		idx: 3,
+		req: "three",
+	}
+	depag := &Depaginator[string]{
+		failed: true,
+		pager:  pager,
+		pages:  &pageMap{},
+		wg:     &sync.WaitGroup{},
+	}
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.False(t, depag.pages.IsSet(3))
+	pager.AssertExpectations(t)
+}
+
+func TestPageRequestApplyUpdateCanceled(t *testing.T) {
+	runCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pager := &mockPageGetter{}
+	obj := pageRequest[string]{
+		idx: 3,
+		req: "three",
+	}
+	depag := &Depaginator[string]{
+		runCtx: runCtx,
+		pager:  pager,
+		pages:  &pageMap{},
+		wg:     &sync.WaitGroup{},
+	}
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	assert.False(t, depag.pages.IsSet(3))
+	pager.AssertExpectations(t)
+}
+
+func TestPageRequestApplyUpdateBeyondLimit(t *testing.T) {
+	obj := pageRequest[string]{
+		idx: 2,
+	}
+	depag := &Depaginator[string]{
+		perPage: 3,
+		limit:   6,
+		pages:   &pageMap{},
+		wg:      &sync.WaitGroup{},
+	}
+
+	obj.applyUpdate(depag)
+
+	assert.False(t, depag.pages.IsSet(2))
+}
+
+func TestPageRequestApplyUpdateMaxPages(t *testing.T) {
+	pager := &mockPageGetter{}
+	obj := pageRequest[string]{
+		idx: 3,
+		req: "three",
+	}
+	depag := &Depaginator[string]{
+		totalPages: 100,
+		maxPages:   3,
+		pager:      pager,
+		pages:      &pageMap{},
+		wg:         &sync.WaitGroup{},
+		updates:    make(chan update[string], DefaultCapacity),
+	}
+
+	obj.applyUpdate(depag)
+
+	depag.wg.Wait()
+	close(depag.updates)
+	updates := []update[string]{}
+	for u := range depag.updates {
+		updates = append(updates, u)
+	}
+	assert.Equal(t, []update[string]{}, updates)
+	assert.False(t, depag.pages.IsSet(3))
+	pager.AssertExpectations(t)
+}
+
 func TestPageRequestApplyUpdateNoMorePages(t *testing.T) {
 	pager := &mockPageGetter{}
 	obj := pageRequest[string]{
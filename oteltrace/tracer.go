@@ -0,0 +1,85 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+// Package oteltrace adapts an OpenTelemetry [trace.Tracer] for use
+// as a [depaginator.Tracer], so that the retrieval of each page by a
+// [depaginator.Depaginator] is traced by an OpenTelemetry span.
+package oteltrace
+
+import (
+	"context"
+
+	"github.com/tmobile/depaginator"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PageIndexKey is the key of the span attribute recording the index
+// of the page being retrieved.
+const PageIndexKey = attribute.Key("page.index")
+
+// Tracer is an implementation of [depaginator.Tracer] which starts
+// its spans using an OpenTelemetry [trace.Tracer].  Each span carries
+// the [PageIndexKey] attribute, and, if the page could not be
+// retrieved, records the error and has its status set to
+// [codes.Error].
+type Tracer struct {
+	tracer trace.Tracer // The OpenTelemetry tracer
+}
+
+// NewTracer constructs a [Tracer] which starts its spans using the
+// specified OpenTelemetry tracer.
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{
+		tracer: tracer,
+	}
+}
+
+// WithTracer returns a [depaginator.Option] which traces the
+// retrieval of pages using the specified OpenTelemetry tracer.  It is
+// equivalent to passing the result of [NewTracer] to
+// [depaginator.WithTracer].
+func WithTracer(tracer trace.Tracer) depaginator.WithTracerOption {
+	return depaginator.WithTracer(NewTracer(tracer))
+}
+
+// Start starts a span with the specified name for the retrieval of
+// the page with the specified index.  It returns a context carrying
+// the span, along with the span.
+func (t *Tracer) Start(ctx context.Context, name string, pageIndex int) (context.Context, depaginator.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(PageIndexKey.Int(pageIndex)))
+
+	return ctx, Span{
+		span: span,
+	}
+}
+
+// Span is an implementation of [depaginator.Span] which wraps an
+// OpenTelemetry [trace.Span].
+type Span struct {
+	span trace.Span // The OpenTelemetry span
+}
+
+// End ends the span.  If err is not nil, it is recorded on the span,
+// and the status of the span is set to [codes.Error].
+func (s Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
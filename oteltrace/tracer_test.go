@@ -0,0 +1,175 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package oteltrace
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tmobile/depaginator"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var errNoSpan = errors.New("no span in context")
+
+type fakeSpan struct {
+	noop.Span
+
+	name   string
+	attrs  []attribute.KeyValue
+	errs   []error
+	code   codes.Code
+	desc   string
+	ended  bool
+	parent context.Context
+}
+
+func (s *fakeSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *fakeSpan) SetStatus(code codes.Code, desc string) {
+	s.code = code
+	s.desc = desc
+}
+
+func (s *fakeSpan) End(_ ...trace.SpanEndOption) {
+	s.ended = true
+}
+
+type fakeTracer struct {
+	noop.Tracer
+	sync.Mutex
+
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.Lock()
+	defer t.Unlock()
+
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &fakeSpan{
+		name:   name,
+		attrs:  cfg.Attributes(),
+		parent: ctx,
+	}
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func TestTracerImplementsTracer(t *testing.T) {
+	assert.Implements(t, (*depaginator.Tracer)(nil), &Tracer{})
+}
+
+func TestSpanImplementsSpan(t *testing.T) {
+	assert.Implements(t, (*depaginator.Span)(nil), Span{})
+}
+
+func TestNewTracer(t *testing.T) {
+	tracer := &fakeTracer{}
+
+	result := NewTracer(tracer)
+
+	assert.Equal(t, &Tracer{
+		tracer: tracer,
+	}, result)
+}
+
+func TestWithTracer(t *testing.T) {
+	tracer := &fakeTracer{}
+
+	result := WithTracer(tracer)
+
+	assert.Equal(t, depaginator.WithTracer(NewTracer(tracer)), result)
+}
+
+func TestTracerStart(t *testing.T) {
+	ctx := context.Background()
+	tracer := &fakeTracer{}
+	obj := NewTracer(tracer)
+
+	spanCtx, span := obj.Start(ctx, depaginator.SpanName, 5)
+
+	assert.Len(t, tracer.spans, 1)
+	assert.Equal(t, depaginator.SpanName, tracer.spans[0].name)
+	assert.Equal(t, []attribute.KeyValue{PageIndexKey.Int(5)}, tracer.spans[0].attrs)
+	assert.Equal(t, ctx, tracer.spans[0].parent)
+	assert.Same(t, tracer.spans[0], trace.SpanFromContext(spanCtx))
+	assert.Equal(t, Span{span: tracer.spans[0]}, span)
+}
+
+func TestSpanEndBase(t *testing.T) {
+	span := &fakeSpan{}
+	obj := Span{span: span}
+
+	obj.End(nil)
+
+	assert.True(t, span.ended)
+	assert.Empty(t, span.errs)
+	assert.Equal(t, codes.Unset, span.code)
+}
+
+func TestSpanEndError(t *testing.T) {
+	span := &fakeSpan{}
+	obj := Span{span: span}
+
+	obj.End(assert.AnError)
+
+	assert.True(t, span.ended)
+	assert.Equal(t, []error{assert.AnError}, span.errs)
+	assert.Equal(t, codes.Error, span.code)
+	assert.Equal(t, assert.AnError.Error(), span.desc)
+}
+
+func TestDepaginateTraced(t *testing.T) {
+	ctx := context.Background()
+	pager := depaginator.PageGetterFunc[string](func(ctx context.Context, depag depaginator.State, req depaginator.PageRequest) ([]string, error) {
+		if _, ok := trace.SpanFromContext(ctx).(*fakeSpan); !ok {
+			return nil, errNoSpan
+		}
+		if req.PageIndex == 0 {
+			depag.Update(depaginator.TotalPages(2), depaginator.PerPage(2))
+			depag.Request(1, nil)
+			return []string{"a", "b"}, nil
+		}
+		return nil, assert.AnError
+	})
+	tracer := &fakeTracer{}
+
+	d := depaginator.Depaginate[string](ctx, pager, &depaginator.ListHandler[string]{}, WithTracer(tracer))
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NotErrorIs(t, err, errNoSpan)
+	spans := map[int64]*fakeSpan{}
+	for _, span := range tracer.spans {
+		assert.Equal(t, depaginator.SpanName, span.name)
+		assert.True(t, span.ended)
+		spans[span.attrs[0].Value.AsInt64()] = span
+	}
+	assert.Len(t, spans, 2)
+	assert.Empty(t, spans[0].errs)
+	assert.Equal(t, []error{assert.AnError}, spans[1].errs)
+	assert.Equal(t, codes.Error, spans[1].code)
+}
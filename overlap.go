@@ -0,0 +1,65 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "sync/atomic"
+
+// overlapSlot holds the request for a page whose retrieval has not yet
+// begun, so that an overlapping request for the same page may replace
+// it.  The daemon replaces the request; the goroutine retrieving the
+// page takes it once retrieval is about to begin, after which the
+// slot is empty and overlapping requests are ignored.
+type overlapSlot struct {
+	req atomic.Pointer[PageRequest] // The pending request
+}
+
+// newOverlapSlot constructs a new overlapSlot holding the request.
+func newOverlapSlot(req PageRequest) *overlapSlot {
+	slot := &overlapSlot{}
+	slot.req.Store(&req)
+
+	return slot
+}
+
+// Merge resolves an overlapping request for the page using the
+// overlap handler.  It returns false if retrieval of the page has
+// already begun, in which case the incoming request is ignored.
+func (s *overlapSlot) Merge(incoming PageRequest, handler func(existing, incoming PageRequest) PageRequest) bool {
+	existing := s.req.Load()
+	if existing == nil {
+		return false
+	}
+
+	winner := handler(*existing, incoming)
+	winner.PageIndex = existing.PageIndex
+
+	return s.req.CompareAndSwap(existing, &winner)
+}
+
+// Take empties the slot, returning the request that won any
+// overlapping requests.  If the slot is nil or already empty, the
+// request passed in is returned.
+func (s *overlapSlot) Take(req PageRequest) PageRequest {
+	if s == nil {
+		return req
+	}
+	if winner := s.req.Swap(nil); winner != nil {
+		return *winner
+	}
+
+	return req
+}
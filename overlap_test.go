@@ -0,0 +1,83 @@
+// Copyright 2021 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOverlapSlot(t *testing.T) {
+	result := newOverlapSlot(PageRequest{PageIndex: 3, Request: "one"})
+
+	assert.Equal(t, &PageRequest{PageIndex: 3, Request: "one"}, result.req.Load())
+}
+
+func TestOverlapSlotMergeBase(t *testing.T) {
+	obj := newOverlapSlot(PageRequest{PageIndex: 3, Request: "one"})
+	var existing, incoming PageRequest
+
+	result := obj.Merge(PageRequest{PageIndex: 3, Request: "two"}, func(e, i PageRequest) PageRequest {
+		existing, incoming = e, i
+		return PageRequest{PageIndex: 5, Request: "merged"}
+	})
+
+	assert.True(t, result)
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "one"}, existing)
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "two"}, incoming)
+	assert.Equal(t, &PageRequest{PageIndex: 3, Request: "merged"}, obj.req.Load())
+}
+
+func TestOverlapSlotMergeTaken(t *testing.T) {
+	obj := newOverlapSlot(PageRequest{PageIndex: 3, Request: "one"})
+	obj.Take(PageRequest{})
+
+	result := obj.Merge(PageRequest{PageIndex: 3, Request: "two"}, func(e, i PageRequest) PageRequest {
+		assert.Fail(t, "handler called after retrieval began")
+		return i
+	})
+
+	assert.False(t, result)
+	assert.Nil(t, obj.req.Load())
+}
+
+func TestOverlapSlotTakeBase(t *testing.T) {
+	obj := newOverlapSlot(PageRequest{PageIndex: 3, Request: "one"})
+
+	result := obj.Take(PageRequest{PageIndex: 3})
+
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "one"}, result)
+	assert.Nil(t, obj.req.Load())
+}
+
+func TestOverlapSlotTakeEmpty(t *testing.T) {
+	obj := newOverlapSlot(PageRequest{PageIndex: 3, Request: "one"})
+	obj.Take(PageRequest{})
+
+	result := obj.Take(PageRequest{PageIndex: 3, Request: "fallback"})
+
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "fallback"}, result)
+}
+
+func TestOverlapSlotTakeNil(t *testing.T) {
+	var obj *overlapSlot
+
+	result := obj.Take(PageRequest{PageIndex: 3, Request: "fallback"})
+
+	assert.Equal(t, PageRequest{PageIndex: 3, Request: "fallback"}, result)
+}
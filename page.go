@@ -0,0 +1,55 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+// PageMeta describes the metadata observed for a single page.  A
+// list of PageMeta records, one for each page that completed, is
+// available from [Depaginator.PageHistory] once [Depaginator.Wait]
+// has returned, allowing an application to audit what each page
+// reported.  A [PageGetterMeta] also returns a PageMeta with each
+// page, reporting the totals and the additional pages to request.
+type PageMeta struct {
+	Request    PageRequest   // The request for the page
+	ItemCount  int           // Number of items in the page
+	TotalItems int           // Total number of items known at completion
+	TotalPages int           // Total number of pages known at completion
+	PerPage    int           // Items per page known at completion
+	Err        error         // Error encountered retrieving the page
+	Requests   []PageRequest // Additional pages to request, for a PageGetterMeta
+}
+
+// AddRequest adds a request for the page with the specified index to
+// the additional pages to request.  The request is optional, and can
+// contain any page-specific data, such as a page link.
+func (pm *PageMeta) AddRequest(idx int, req any) {
+	pm.Requests = append(pm.Requests, PageRequest{
+		PageIndex: idx,
+		Request:   req,
+	})
+}
+
+// Page describes a page of items returned by a [PageGetterMeta].
+type Page[T any] struct {
+	Items []T // The items in the page
+}
+
+// PageResult describes a page of items delivered by the channel
+// returned by [Depaginator.Pages].
+type PageResult[T any] struct {
+	Request PageRequest // The request for the page
+	Items   []T         // The items in the page
+}
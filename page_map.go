@@ -16,19 +16,34 @@
 
 package depaginator
 
-import "math/bits"
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// checkpointVersion is the version of the checkpoint format produced
+// by [pageMap.Checkpoint].
+const checkpointVersion = 1
 
 // pageMap is a bitmap used to represent which pages have been
 // handled.  This is a deduplication method used to ensure we don't
 // get the same page twice.
 type pageMap struct {
 	bits []uint // The container for the bits
+	base uint   // Index of the word at the start of bits
 }
 
 // CheckAndSet checks to see if the specific page is set.  It returns
 // true if it is.  Either way, it sets the bit for the specific page.
+// Pages whose bits have been dropped by [pageMap.Compact] are
+// reported as not set, and are not recorded.
 func (pm *pageMap) CheckAndSet(page int) (result bool) {
 	idx, bit := bits.Div(0, uint(page), bits.UintSize)
+	if idx < pm.base {
+		return false
+	}
+	idx -= pm.base
 	if idx >= uint(len(pm.bits)) {
 		newMap := make([]uint, idx+1)
 		copy(newMap, pm.bits)
@@ -41,3 +56,95 @@ func (pm *pageMap) CheckAndSet(page int) (result bool) {
 
 	return
 }
+
+// IsSet checks if the bit corresponding to the specified page is set.
+func (pm *pageMap) IsSet(page int) bool {
+	idx, bit := bits.Div(0, uint(page), bits.UintSize)
+	if idx < pm.base || idx-pm.base >= uint(len(pm.bits)) {
+		return false
+	}
+	idx -= pm.base
+
+	return pm.bits[idx]&(1<<bit) != 0
+}
+
+// Compact drops the bits for pages below the specified page, allowing
+// the memory used to be reclaimed.  Bits are dropped a word at a
+// time, so the bits for some pages below the specified page may be
+// retained.
+func (pm *pageMap) Compact(page int) {
+	if page <= 0 {
+		return
+	}
+
+	idx := uint(page) / bits.UintSize
+	if idx <= pm.base {
+		return
+	}
+
+	drop := idx - pm.base
+	if drop >= uint(len(pm.bits)) {
+		pm.bits = nil
+	} else {
+		pm.bits = pm.bits[drop:]
+	}
+	pm.base = idx
+}
+
+// Compacted checks if the bit corresponding to the specified page has
+// been dropped by [pageMap.Compact].
+func (pm *pageMap) Compacted(page int) bool {
+	return page >= 0 && uint(page)/bits.UintSize < pm.base
+}
+
+// FirstUnset returns the lowest page whose bit is not set, ignoring
+// the bits dropped by [pageMap.Compact].
+func (pm *pageMap) FirstUnset() int {
+	for i, word := range pm.bits {
+		if word != ^uint(0) {
+			return int(pm.base+uint(i))*bits.UintSize + bits.TrailingZeros(^word)
+		}
+	}
+
+	return int(pm.base+uint(len(pm.bits))) * bits.UintSize
+}
+
+// Checkpoint serializes the page map.  The checkpoint consists of a
+// header containing the format version and the word size, followed
+// by the index of the first word and the words themselves, each as a
+// little-endian 64-bit integer.
+func (pm *pageMap) Checkpoint() []byte {
+	data := make([]byte, 2, 2+8*(len(pm.bits)+1))
+	data[0] = checkpointVersion
+	data[1] = bits.UintSize
+	data = binary.LittleEndian.AppendUint64(data, uint64(pm.base))
+	for _, word := range pm.bits {
+		data = binary.LittleEndian.AppendUint64(data, uint64(word))
+	}
+
+	return data
+}
+
+// Restore replaces the contents of the page map with those serialized
+// by [pageMap.Checkpoint].  It returns an error wrapping
+// [ErrInvalidCheckpoint] if the checkpoint cannot be decoded.
+func (pm *pageMap) Restore(data []byte) error {
+	switch {
+	case len(data) < 10 || (len(data)-2)%8 != 0:
+		return fmt.Errorf("%w: bad length %d", ErrInvalidCheckpoint, len(data))
+
+	case data[0] != checkpointVersion:
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidCheckpoint, data[0])
+
+	case data[1] != bits.UintSize:
+		return fmt.Errorf("%w: unsupported word size %d", ErrInvalidCheckpoint, data[1])
+	}
+
+	pm.base = uint(binary.LittleEndian.Uint64(data[2:]))
+	pm.bits = nil
+	for i := 10; i < len(data); i += 8 {
+		pm.bits = append(pm.bits, uint(binary.LittleEndian.Uint64(data[i:])))
+	}
+
+	return nil
+}
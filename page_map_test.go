@@ -17,6 +17,7 @@
 package depaginator
 
 import (
+	"math/bits"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,3 +58,238 @@ func TestPageMapCheckAndSetHighBit(t *testing.T) {
 
 	assert.True(t, result2)
 }
+
+func TestPageMapIsSetBase(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2},
+	}
+
+	result := obj.IsSet(1)
+
+	assert.True(t, result)
+}
+
+func TestPageMapIsSetUnset(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2},
+	}
+
+	result := obj.IsSet(0)
+
+	assert.False(t, result)
+}
+
+func TestPageMapIsSetHighBit(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2},
+	}
+
+	result := obj.IsSet(256)
+
+	assert.False(t, result)
+	assert.Equal(t, &pageMap{
+		bits: []uint{2},
+	}, obj)
+}
+
+func TestPageMapCheckAndSetCompacted(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2},
+		base: 2,
+	}
+
+	result := obj.CheckAndSet(1)
+
+	assert.False(t, result)
+	assert.Equal(t, &pageMap{
+		bits: []uint{2},
+		base: 2,
+	}, obj)
+}
+
+func TestPageMapCheckAndSetWithBase(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2},
+		base: 2,
+	}
+
+	result1 := obj.CheckAndSet(2*bits.UintSize + 1)
+	result2 := obj.CheckAndSet(3*bits.UintSize + 1)
+
+	assert.True(t, result1)
+	assert.False(t, result2)
+	assert.Equal(t, &pageMap{
+		bits: []uint{2, 2},
+		base: 2,
+	}, obj)
+}
+
+func TestPageMapIsSetWithBase(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2},
+		base: 2,
+	}
+
+	assert.False(t, obj.IsSet(1))
+	assert.True(t, obj.IsSet(2*bits.UintSize+1))
+	assert.False(t, obj.IsSet(3*bits.UintSize+1))
+}
+
+func TestPageMapCompactBase(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{1, 2, 4},
+	}
+
+	obj.Compact(bits.UintSize + 5)
+
+	assert.Equal(t, &pageMap{
+		bits: []uint{2, 4},
+		base: 1,
+	}, obj)
+}
+
+func TestPageMapCompactAll(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{1, 2, 4},
+	}
+
+	obj.Compact(5 * bits.UintSize)
+
+	assert.Equal(t, &pageMap{
+		base: 5,
+	}, obj)
+}
+
+func TestPageMapCompactBackwards(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2, 4},
+		base: 1,
+	}
+
+	obj.Compact(5)
+
+	assert.Equal(t, &pageMap{
+		bits: []uint{2, 4},
+		base: 1,
+	}, obj)
+}
+
+func TestPageMapCompacted(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2, 4},
+		base: 1,
+	}
+
+	assert.False(t, obj.Compacted(-1))
+	assert.True(t, obj.Compacted(0))
+	assert.True(t, obj.Compacted(bits.UintSize-1))
+	assert.False(t, obj.Compacted(bits.UintSize))
+}
+
+func TestPageMapFirstUnsetEmpty(t *testing.T) {
+	obj := &pageMap{}
+
+	result := obj.FirstUnset()
+
+	assert.Equal(t, 0, result)
+}
+
+func TestPageMapFirstUnsetPartial(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{^uint(0), 7},
+		base: 1,
+	}
+
+	result := obj.FirstUnset()
+
+	assert.Equal(t, 2*bits.UintSize+3, result)
+}
+
+func TestPageMapFirstUnsetFull(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{^uint(0)},
+		base: 1,
+	}
+
+	result := obj.FirstUnset()
+
+	assert.Equal(t, 2*bits.UintSize, result)
+}
+
+func TestPageMapCheckpoint(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{2, 4},
+		base: 1,
+	}
+
+	result := obj.Checkpoint()
+
+	assert.Equal(t, []byte{
+		checkpointVersion, bits.UintSize,
+		1, 0, 0, 0, 0, 0, 0, 0,
+		2, 0, 0, 0, 0, 0, 0, 0,
+		4, 0, 0, 0, 0, 0, 0, 0,
+	}, result)
+}
+
+func TestPageMapRestoreBase(t *testing.T) {
+	obj := &pageMap{
+		bits: []uint{8},
+	}
+
+	err := obj.Restore([]byte{
+		checkpointVersion, bits.UintSize,
+		1, 0, 0, 0, 0, 0, 0, 0,
+		2, 0, 0, 0, 0, 0, 0, 0,
+		4, 0, 0, 0, 0, 0, 0, 0,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &pageMap{
+		bits: []uint{2, 4},
+		base: 1,
+	}, obj)
+}
+
+func TestPageMapRestoreRoundTrip(t *testing.T) {
+	obj := &pageMap{}
+	obj.CheckAndSet(0)
+	obj.CheckAndSet(3)
+	obj.CheckAndSet(200)
+	restored := &pageMap{}
+
+	err := restored.Restore(obj.Checkpoint())
+
+	assert.NoError(t, err)
+	assert.Equal(t, obj, restored)
+}
+
+func TestPageMapRestoreBadLength(t *testing.T) {
+	obj := &pageMap{}
+
+	err := obj.Restore([]byte{checkpointVersion, bits.UintSize, 1, 0, 0})
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+}
+
+func TestPageMapRestoreBadVersion(t *testing.T) {
+	obj := &pageMap{}
+
+	err := obj.Restore([]byte{
+		checkpointVersion + 1, bits.UintSize,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+}
+
+func TestPageMapRestoreBadWordSize(t *testing.T) {
+	obj := &pageMap{}
+
+	err := obj.Restore([]byte{
+		checkpointVersion, bits.UintSize / 2,
+		0, 0, 0, 0, 0, 0, 0, 0,
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidCheckpoint)
+}
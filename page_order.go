@@ -0,0 +1,88 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+// pageOrder tracks the pages awaiting handling when the items are to
+// be handled in strict page order.  A retrieved page is released for
+// handling only once no page with a lower index is still being
+// retrieved or handled.  It is only accessed by the daemon.
+type pageOrder struct {
+	fetching map[int]bool   // Pages being retrieved
+	queued   map[int]func() // Handling tasks of retrieved pages
+	busy     bool           // A page is being handled
+}
+
+// newPageOrder constructs a new pageOrder.
+func newPageOrder() *pageOrder {
+	return &pageOrder{
+		fetching: map[int]bool{},
+		queued:   map[int]func(){},
+	}
+}
+
+// Fetch records that a page is being retrieved.
+func (po *pageOrder) Fetch(idx int) {
+	po.fetching[idx] = true
+}
+
+// Settle records that the retrieval of a page has completed.  If the
+// page is to be handled, task is the task handling its items;
+// otherwise, it is nil.
+func (po *pageOrder) Settle(idx int, task func()) {
+	delete(po.fetching, idx)
+	if task != nil {
+		po.queued[idx] = task
+	}
+}
+
+// Queued returns the number of retrieved pages waiting to be handled.
+func (po *pageOrder) Queued() int {
+	return len(po.queued)
+}
+
+// Done records that the handling of a page has completed.
+func (po *pageOrder) Done() {
+	po.busy = false
+}
+
+// Next returns the task handling the next page, if that page may be
+// released for handling; otherwise, it returns nil.
+func (po *pageOrder) Next() func() {
+	if po.busy || len(po.queued) == 0 {
+		return nil
+	}
+
+	// Find the lowest page retrieved
+	lowest := -1
+	for idx := range po.queued {
+		if lowest < 0 || idx < lowest {
+			lowest = idx
+		}
+	}
+
+	// Is a lower page still being retrieved?
+	for idx := range po.fetching {
+		if idx < lowest {
+			return nil
+		}
+	}
+
+	task := po.queued[lowest]
+	delete(po.queued, lowest)
+	po.busy = true
+	return task
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageOrder(t *testing.T) {
+	result := newPageOrder()
+
+	assert.Equal(t, &pageOrder{
+		fetching: map[int]bool{},
+		queued:   map[int]func(){},
+	}, result)
+}
+
+func TestPageOrderFetch(t *testing.T) {
+	obj := newPageOrder()
+
+	obj.Fetch(3)
+
+	assert.Equal(t, map[int]bool{3: true}, obj.fetching)
+}
+
+func TestPageOrderSettleTask(t *testing.T) {
+	obj := newPageOrder()
+	obj.Fetch(3)
+
+	obj.Settle(3, func() {})
+
+	assert.Empty(t, obj.fetching)
+	assert.Contains(t, obj.queued, 3)
+}
+
+func TestPageOrderSettleNoTask(t *testing.T) {
+	obj := newPageOrder()
+	obj.Fetch(3)
+
+	obj.Settle(3, nil)
+
+	assert.Empty(t, obj.fetching)
+	assert.Empty(t, obj.queued)
+}
+
+func TestPageOrderQueued(t *testing.T) {
+	obj := newPageOrder()
+	obj.queued[3] = func() {}
+	obj.queued[5] = func() {}
+
+	result := obj.Queued()
+
+	assert.Equal(t, 2, result)
+}
+
+func TestPageOrderDone(t *testing.T) {
+	obj := newPageOrder()
+	obj.busy = true
+
+	obj.Done()
+
+	assert.False(t, obj.busy)
+}
+
+func TestPageOrderNextBase(t *testing.T) {
+	var called []int
+	obj := newPageOrder()
+	obj.Settle(2, func() { called = append(called, 2) })
+	obj.Settle(1, func() { called = append(called, 1) })
+
+	result := obj.Next()
+
+	assert.NotNil(t, result)
+	result()
+	assert.Equal(t, []int{1}, called)
+	assert.True(t, obj.busy)
+	assert.Len(t, obj.queued, 1)
+}
+
+func TestPageOrderNextBusy(t *testing.T) {
+	obj := newPageOrder()
+	obj.Settle(1, func() {})
+	obj.busy = true
+
+	result := obj.Next()
+
+	assert.Nil(t, result)
+	assert.Len(t, obj.queued, 1)
+}
+
+func TestPageOrderNextEmpty(t *testing.T) {
+	obj := newPageOrder()
+
+	result := obj.Next()
+
+	assert.Nil(t, result)
+	assert.False(t, obj.busy)
+}
+
+func TestPageOrderNextLowerFetching(t *testing.T) {
+	obj := newPageOrder()
+	obj.Fetch(1)
+	obj.Settle(2, func() {})
+
+	result := obj.Next()
+
+	assert.Nil(t, result)
+	assert.False(t, obj.busy)
+}
+
+func TestPageOrderNextHigherFetching(t *testing.T) {
+	obj := newPageOrder()
+	obj.Fetch(3)
+	obj.Settle(2, func() {})
+
+	result := obj.Next()
+
+	assert.NotNil(t, result)
+	assert.True(t, obj.busy)
+}
@@ -0,0 +1,164 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ProgressBarWidth is the number of characters in the bar rendered by
+// [ProgressBarWriter].
+const ProgressBarWidth = 20
+
+// spinner contains the frames of the indicator rendered by
+// [ProgressBarWriter] while the total number of items is not known.
+var spinner = []byte{'|', '/', '-', '\\'}
+
+// Progress describes the progress of an iteration.  It is returned
+// by [Depaginator.Progress], and provides a standard means of
+// reporting how far along the iteration is.
+type Progress struct {
+	Handled int // Number of items handled so far
+	Total   int // Total number of items; 0 if not known
+}
+
+// Fraction returns the fraction of the items that have been handled,
+// as a number between 0 and 1.  If the total number of items is not
+// yet known, returns -1.
+func (p Progress) Fraction() float64 {
+	if p.Total <= 0 {
+		return -1
+	}
+
+	return float64(p.Handled) / float64(p.Total)
+}
+
+// String returns a human-readable description of the progress, such
+// as "42% (84/200)".  If the total number of items is not yet known,
+// the percentage is omitted and the total is reported as "?".
+func (p Progress) String() string {
+	if p.Total <= 0 {
+		return fmt.Sprintf("%d/?", p.Handled)
+	}
+
+	return fmt.Sprintf("%d%% (%d/%d)", p.Handled*100/p.Total, p.Handled, p.Total)
+}
+
+// ProgressBarWriter is an [Updater] and [Doner] which renders the
+// progress of an iteration to an [io.Writer] as a textual bar, such
+// as "[########            ]  42% (84/200)", updated in place using
+// carriage returns.  While the total number of items is not known, a
+// spinner is rendered instead.  As the [Updater] is only called when
+// the totals change, the handler must report each item handled by
+// calling [ProgressBarWriter.Add].  A ProgressBarWriter is safe for
+// concurrent use.
+type ProgressBarWriter struct {
+	sync.Mutex
+
+	w       io.Writer // Writer to render the bar to
+	handled int       // Number of items handled so far
+	total   int       // Total number of items; 0 if not known
+	percent int       // Percentage last rendered; -1 if none
+	frame   int       // Next frame of the spinner
+	width   int       // Width of the line last rendered
+}
+
+// ProgressBar returns a [ProgressBarWriter] rendering the progress of
+// an iteration to the specified writer.  Pass it to [Depaginate] with
+// the [WithUpdater] and [WithDoner] options, and call
+// [ProgressBarWriter.Add] from the [Handler].
+func ProgressBar(w io.Writer) *ProgressBarWriter {
+	return &ProgressBarWriter{
+		w:       w,
+		percent: -1,
+	}
+}
+
+// Add records that n more items have been handled, rendering the bar
+// if the percentage has changed, or advancing the spinner if the total
+// number of items is not known.
+func (pb *ProgressBarWriter) Add(n int) {
+	pb.Lock()
+	defer pb.Unlock()
+
+	pb.handled += n
+	pb.render(false)
+}
+
+// Update is called with the new values of total items, total pages,
+// and items per page.  It renders the bar with the new total.
+func (pb *ProgressBarWriter) Update(_ context.Context, totalItems, _, _ int) {
+	pb.Lock()
+	defer pb.Unlock()
+
+	pb.total = totalItems
+	pb.render(false)
+}
+
+// Done is called with the final values of total items, total pages,
+// and items per page.  It renders the bar a final time and ends the
+// line.
+func (pb *ProgressBarWriter) Done(_ context.Context, totalItems, _, _ int) {
+	pb.Lock()
+	defer pb.Unlock()
+
+	pb.total = totalItems
+	pb.render(true)
+	fmt.Fprintln(pb.w)
+}
+
+// render renders the bar, overwriting the line last rendered.  Unless
+// forced, the bar is only rendered if the percentage has changed.
+func (pb *ProgressBarWriter) render(force bool) {
+	p := Progress{
+		Handled: pb.handled,
+		Total:   pb.total,
+	}
+
+	var line string
+	if p.Total > 0 {
+		handled := p.Handled
+		if handled > p.Total {
+			handled = p.Total
+		}
+		percent := handled * 100 / p.Total
+		if percent == pb.percent && !force {
+			return
+		}
+		pb.percent = percent
+
+		filled := handled * ProgressBarWidth / p.Total
+		line = fmt.Sprintf("[%s%s] %3d%% (%d/%d)",
+			strings.Repeat("#", filled), strings.Repeat(" ", ProgressBarWidth-filled),
+			percent, p.Handled, p.Total)
+	} else {
+		line = fmt.Sprintf("[%c] %s", spinner[pb.frame%len(spinner)], p)
+		pb.frame++
+	}
+
+	// Pad the line to erase the remains of the last one
+	pad := pb.width - len(line)
+	if pad < 0 {
+		pad = 0
+	}
+	pb.width = len(line)
+	fmt.Fprintf(pb.w, "\r%s%s", line, strings.Repeat(" ", pad))
+}
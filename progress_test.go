@@ -0,0 +1,152 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressFractionBase(t *testing.T) {
+	obj := Progress{
+		Handled: 84,
+		Total:   200,
+	}
+
+	result := obj.Fraction()
+
+	assert.InDelta(t, 0.42, result, 0.0001)
+}
+
+func TestProgressFractionComplete(t *testing.T) {
+	obj := Progress{
+		Handled: 200,
+		Total:   200,
+	}
+
+	result := obj.Fraction()
+
+	assert.InDelta(t, 1.0, result, 0.0001)
+}
+
+func TestProgressFractionUnknown(t *testing.T) {
+	obj := Progress{
+		Handled: 84,
+	}
+
+	result := obj.Fraction()
+
+	assert.Equal(t, -1.0, result)
+}
+
+func TestProgressStringBase(t *testing.T) {
+	obj := Progress{
+		Handled: 84,
+		Total:   200,
+	}
+
+	result := obj.String()
+
+	assert.Equal(t, "42% (84/200)", result)
+}
+
+func TestProgressStringUnknown(t *testing.T) {
+	obj := Progress{
+		Handled: 84,
+	}
+
+	result := obj.String()
+
+	assert.Equal(t, "84/?", result)
+}
+
+func TestProgressBar(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	result := ProgressBar(buf)
+
+	assert.Equal(t, &ProgressBarWriter{
+		w:       buf,
+		percent: -1,
+	}, result)
+}
+
+func TestProgressBarWriterImplementsUpdater(t *testing.T) {
+	assert.Implements(t, (*Updater)(nil), &ProgressBarWriter{})
+}
+
+func TestProgressBarWriterImplementsDoner(t *testing.T) {
+	assert.Implements(t, (*Doner)(nil), &ProgressBarWriter{})
+}
+
+func TestProgressBarWriterBase(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	obj := ProgressBar(buf)
+
+	obj.Update(ctx, 200, 20, 10)
+	obj.Add(84)
+	obj.Add(1)
+	obj.Add(100)
+	obj.Add(15)
+	obj.Done(ctx, 200, 20, 10)
+
+	assert.Equal(t, ""+
+		"\r[                    ]   0% (0/200)"+
+		"\r[########            ]  42% (84/200)"+
+		"\r[##################  ]  92% (185/200)"+
+		"\r[####################] 100% (200/200)"+
+		"\r[####################] 100% (200/200)\n", buf.String())
+}
+
+func TestProgressBarWriterUnknownTotal(t *testing.T) {
+	ctx := context.Background()
+	buf := &bytes.Buffer{}
+	obj := ProgressBar(buf)
+
+	obj.Add(1)
+	obj.Add(1)
+	obj.Add(1)
+	obj.Add(1)
+	obj.Add(1)
+	obj.Done(ctx, 5, 1, 10)
+
+	assert.Equal(t, ""+
+		"\r[|] 1/?"+
+		"\r[/] 2/?"+
+		"\r[-] 3/?"+
+		"\r[\\] 4/?"+
+		"\r[|] 5/?"+
+		"\r[####################] 100% (5/5)\n", buf.String())
+}
+
+func TestProgressBarWriterShorterLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	obj := &ProgressBarWriter{
+		w:       buf,
+		total:   200,
+		percent: -1,
+		width:   40,
+	}
+
+	obj.render(false)
+
+	assert.Equal(t, "\r[                    ]   0% (0/200)     ", buf.String())
+}
@@ -0,0 +1,149 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "sync"
+
+// taskQueue is a queue of item handling tasks drained by the item
+// handling workers.  Adding a task never blocks, so the daemon may
+// queue tasks without waiting on busy workers.  Instead, page
+// retrievals reserve room in the queue before submitting their pages,
+// which bounds the number of pages awaiting a worker without spawning
+// a goroutine for each.
+type taskQueue struct {
+	sync.Mutex
+
+	cond     *sync.Cond // Signaled when room or tasks become available
+	tasks    []func()   // Tasks awaiting a worker
+	reserved int        // Room reserved for tasks not yet added
+	limit    int        // Number of tasks the queue has room for
+	running  int        // Number of workers started by Offer running
+	closed   bool       // The queue has been closed
+}
+
+// newTaskQueue constructs a new taskQueue with room for the specified
+// number of tasks.
+func newTaskQueue(limit int) *taskQueue {
+	q := &taskQueue{
+		limit: limit,
+	}
+	q.cond = sync.NewCond(q)
+
+	return q
+}
+
+// Put adds a task to the queue.  It never blocks, even if the queue
+// has no room.
+func (q *taskQueue) Put(task func()) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.tasks = append(q.tasks, task)
+	q.cond.Broadcast()
+}
+
+// Offer adds a task to the queue for workers run as tasks of a
+// [Scheduler], which exit once the queue is empty rather than waiting
+// for more tasks.  It reports whether a new worker must be started,
+// which is the case while fewer workers are running than the queue
+// has room for.
+func (q *taskQueue) Offer(task func()) bool {
+	q.Lock()
+	defer q.Unlock()
+
+	q.tasks = append(q.tasks, task)
+	q.cond.Broadcast()
+	if q.running >= q.limit {
+		return false
+	}
+	q.running++
+
+	return true
+}
+
+// Next removes the oldest task from the queue for a worker started
+// following [taskQueue.Offer].  If the queue is empty, it returns
+// false, and the worker must exit.
+func (q *taskQueue) Next() (func(), bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	if len(q.tasks) == 0 {
+		q.running--
+		return nil, false
+	}
+	task := q.tasks[0]
+	q.tasks[0] = nil
+	q.tasks = q.tasks[1:]
+	q.cond.Broadcast()
+
+	return task, true
+}
+
+// Get removes the oldest task from the queue, waiting for one to be
+// added if the queue is empty.  It returns false once the queue has
+// been closed and emptied.
+func (q *taskQueue) Get() (func(), bool) {
+	q.Lock()
+	defer q.Unlock()
+
+	for len(q.tasks) == 0 {
+		if q.closed {
+			return nil, false
+		}
+		q.cond.Wait()
+	}
+	task := q.tasks[0]
+	q.tasks[0] = nil
+	q.tasks = q.tasks[1:]
+	q.cond.Broadcast()
+
+	return task, true
+}
+
+// Reserve waits for the queue to have room for another task, or to be
+// closed, then reserves the room.  The reservation must be released
+// with [taskQueue.Release].
+func (q *taskQueue) Reserve() {
+	q.Lock()
+	defer q.Unlock()
+
+	for len(q.tasks)+q.reserved >= q.limit && !q.closed {
+		q.cond.Wait()
+	}
+	q.reserved++
+}
+
+// Release releases room reserved by [taskQueue.Reserve], once the
+// task has been added or turns out not to be needed.
+func (q *taskQueue) Release() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.reserved--
+	q.cond.Broadcast()
+}
+
+// Close closes the queue.  Workers drain the tasks remaining in the
+// queue before [taskQueue.Get] reports it closed.
+func (q *taskQueue) Close() {
+	q.Lock()
+	defer q.Unlock()
+
+	q.closed = true
+	q.cond.Broadcast()
+}
@@ -0,0 +1,159 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTaskQueue(t *testing.T) {
+	result := newTaskQueue(3)
+
+	assert.Equal(t, 3, result.limit)
+	assert.NotNil(t, result.cond)
+	assert.Empty(t, result.tasks)
+	assert.False(t, result.closed)
+}
+
+func TestTaskQueuePutGet(t *testing.T) {
+	obj := newTaskQueue(1)
+	var order []int
+
+	obj.Put(func() { order = append(order, 1) })
+	obj.Put(func() { order = append(order, 2) })
+	for i := 0; i < 2; i++ {
+		task, ok := obj.Get()
+		assert.True(t, ok)
+		task()
+	}
+
+	assert.Equal(t, []int{1, 2}, order)
+	assert.Empty(t, obj.tasks)
+}
+
+func TestTaskQueueOfferNext(t *testing.T) {
+	obj := newTaskQueue(1)
+	var order []int
+
+	first := obj.Offer(func() { order = append(order, 1) })
+	second := obj.Offer(func() { order = append(order, 2) })
+	for i := 0; i < 2; i++ {
+		task, ok := obj.Next()
+		assert.True(t, ok)
+		task()
+	}
+	_, ok := obj.Next()
+
+	assert.True(t, first)
+	assert.False(t, second)
+	assert.False(t, ok)
+	assert.Equal(t, []int{1, 2}, order)
+	assert.Equal(t, 0, obj.running)
+	assert.True(t, obj.Offer(func() {}))
+}
+
+func TestTaskQueueGetWaits(t *testing.T) {
+	obj := newTaskQueue(1)
+	got := make(chan bool)
+	go func() {
+		_, ok := obj.Get()
+		got <- ok
+	}()
+
+	select {
+	case <-got:
+		t.Fatal("Get returned from an empty queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+	obj.Put(func() {})
+
+	assert.True(t, <-got)
+}
+
+func TestTaskQueueGetClosed(t *testing.T) {
+	obj := newTaskQueue(1)
+	obj.Put(func() {})
+
+	obj.Close()
+	_, first := obj.Get()
+	_, second := obj.Get()
+
+	assert.True(t, first)
+	assert.False(t, second)
+}
+
+func TestTaskQueueReserve(t *testing.T) {
+	obj := newTaskQueue(2)
+	obj.Put(func() {})
+
+	obj.Reserve()
+
+	assert.Equal(t, 1, obj.reserved)
+}
+
+func TestTaskQueueReserveWaitsForTask(t *testing.T) {
+	obj := newTaskQueue(1)
+	obj.Put(func() {})
+	reserved := make(chan struct{})
+	go func() {
+		defer close(reserved)
+		obj.Reserve()
+	}()
+
+	select {
+	case <-reserved:
+		t.Fatal("Reserve returned from a full queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+	_, ok := obj.Get()
+
+	assert.True(t, ok)
+	<-reserved
+}
+
+func TestTaskQueueReserveWaitsForRelease(t *testing.T) {
+	obj := newTaskQueue(1)
+	obj.Reserve()
+	reserved := make(chan struct{})
+	go func() {
+		defer close(reserved)
+		obj.Reserve()
+	}()
+
+	select {
+	case <-reserved:
+		t.Fatal("Reserve returned from a fully reserved queue")
+	case <-time.After(10 * time.Millisecond):
+	}
+	obj.Release()
+
+	<-reserved
+	assert.Equal(t, 1, obj.reserved)
+}
+
+func TestTaskQueueReserveClosed(t *testing.T) {
+	obj := newTaskQueue(1)
+	obj.Put(func() {})
+
+	obj.Close()
+	obj.Reserve()
+
+	assert.Equal(t, 1, obj.reserved)
+}
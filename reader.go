@@ -0,0 +1,84 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"io"
+)
+
+// ReaderHandler is an implementation of [Handler] that makes the
+// retrieved items available as a stream of bytes through an
+// [io.Reader].  Each item is formatted using a mapper function, such
+// as [encoding/json.Marshal], and followed by a newline, producing,
+// for instance, newline-delimited JSON.  Items are written to the
+// stream as they are handled, which means that they are not
+// guaranteed to be in index order.  Once [ReaderHandler.Done] is
+// called (which is called by [Depaginator.Wait]), reads from the
+// stream will return [io.EOF].
+//
+// Note that writes to the stream block until the data is read, so
+// the application must read from the ReaderHandler concurrently with
+// [Depaginator.Wait], or the iteration will never complete.
+type ReaderHandler[T any] struct {
+	mapper func(item T) ([]byte, error) // Function to format items
+	reader *io.PipeReader               // Reading end of the stream
+	writer *io.PipeWriter               // Writing end of the stream
+}
+
+// NewReaderHandler constructs a new [ReaderHandler] which uses the
+// specified mapper function to format each item.  The mapper must not
+// include the trailing newline.  If the mapper returns an error,
+// reads from the stream will return that error once the data
+// preceding it has been read.
+func NewReaderHandler[T any](mapper func(item T) ([]byte, error)) *ReaderHandler[T] {
+	reader, writer := io.Pipe()
+
+	return &ReaderHandler[T]{
+		mapper: mapper,
+		reader: reader,
+		writer: writer,
+	}
+}
+
+// Read reads formatted items from the stream.  It blocks until more
+// items are available, and returns [io.EOF] once the iteration is
+// complete.
+func (rh *ReaderHandler[T]) Read(p []byte) (int, error) {
+	return rh.reader.Read(p)
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (rh *ReaderHandler[T]) Handle(_ context.Context, _ int, item T) {
+	data, err := rh.mapper(item)
+	if err != nil {
+		_ = rh.writer.CloseWithError(err)
+		return
+	}
+
+	// Write the line in a single call, so that concurrent lines are
+	// not interleaved
+	_, _ = rh.writer.Write(append(data, '\n'))
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (rh *ReaderHandler[T]) Done(_ context.Context, _, _, _ int) {
+	_ = rh.writer.Close()
+}
@@ -0,0 +1,115 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &ReaderHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &ReaderHandler[string]{})
+	assert.Implements(t, (*io.Reader)(nil), &ReaderHandler[string]{})
+}
+
+func TestNewReaderHandler(t *testing.T) {
+	result := NewReaderHandler(func(item string) ([]byte, error) {
+		return []byte(item), nil
+	})
+
+	assert.NotNil(t, result.mapper)
+	assert.NotNil(t, result.reader)
+	assert.NotNil(t, result.writer)
+}
+
+func TestReaderHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	obj := NewReaderHandler(func(item string) ([]byte, error) {
+		return []byte(item), nil
+	})
+	go func() {
+		obj.Handle(ctx, 0, "foo")
+		obj.Handle(ctx, 1, "bar")
+		obj.Done(ctx, 2, 1, 2)
+	}()
+
+	result, err := io.ReadAll(obj)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo\nbar\n", string(result))
+}
+
+func TestReaderHandlerHandleError(t *testing.T) {
+	ctx := context.Background()
+	obj := NewReaderHandler(func(item string) ([]byte, error) {
+		if item == "bad" {
+			return nil, assert.AnError
+		}
+		return []byte(item), nil
+	})
+	go func() {
+		obj.Handle(ctx, 0, "foo")
+		obj.Handle(ctx, 1, "bad")
+		obj.Handle(ctx, 2, "bar")
+		obj.Done(ctx, 3, 1, 3)
+	}()
+
+	result, err := io.ReadAll(obj)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, "foo\n", string(result))
+}
+
+func TestReaderHandlerStream(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:   3,
+		pageAhead: 5,
+	}
+	obj := NewReaderHandler(func(item string) ([]byte, error) {
+		return json.Marshal(map[string]string{"id": item})
+	})
+
+	d := Depaginate[string](ctx, data, obj)
+	waited := make(chan error)
+	go func() {
+		waited <- d.Wait()
+	}()
+	result, err := io.ReadAll(obj)
+
+	require.NoError(t, err)
+	assert.NoError(t, <-waited)
+	lines := strings.Split(strings.TrimSuffix(string(result), "\n"), "\n")
+	sort.Strings(lines)
+	expected := []string{}
+	for _, item := range data.data {
+		expected = append(expected, `{"id":"`+item+`"}`)
+	}
+	sort.Strings(expected)
+	assert.Equal(t, expected, lines)
+}
@@ -0,0 +1,265 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Capture is a record of a single call to [PageGetter.GetPage],
+// captured by a [RecordingPager] and served by a [ReplayPager].  It
+// includes the metadata and page requests submitted to the [State]
+// during the call, as well as the items or error returned.  Captures
+// may be stored using [encoding/json]; note that the Request field of
+// each [PageRequest] is only preserved if it survives a round trip
+// through [encoding/json], and that errors are preserved only as
+// their messages.
+type Capture[T any] struct {
+	Page       PageRequest   `json:"page"`                  // The page requested
+	Items      []T           `json:"items,omitempty"`       // The items returned
+	Err        string        `json:"error,omitempty"`       // Message of the error returned
+	TotalItems int           `json:"total_items,omitempty"` // Total number of items reported
+	TotalPages int           `json:"total_pages,omitempty"` // Total number of pages reported
+	PerPage    int           `json:"per_page,omitempty"`    // Items per page reported
+	LastPage   *int          `json:"last_page,omitempty"`   // Index of the page marked as last
+	Requests   []PageRequest `json:"requests,omitempty"`    // Additional pages requested
+}
+
+// replay replays the calls to the [State] recorded in the capture,
+// returning the items and error.  Metadata updates are replayed
+// before the final page is marked, which is in turn replayed before
+// any page requests.
+func (c Capture[T]) replay(depag State) ([]T, error) {
+	updates := []any{}
+	if c.TotalItems > 0 {
+		updates = append(updates, TotalItems(c.TotalItems))
+	}
+	if c.TotalPages > 0 {
+		updates = append(updates, TotalPages(c.TotalPages))
+	}
+	if c.PerPage > 0 {
+		updates = append(updates, PerPage(c.PerPage))
+	}
+	if len(updates) > 0 {
+		depag.Update(updates...)
+	}
+	if c.LastPage != nil {
+		depag.MarkLast(*c.LastPage)
+	}
+	for _, req := range c.Requests {
+		depag.Request(req.PageIndex, req.Request)
+	}
+
+	if c.Err != "" {
+		return nil, errors.New(c.Err)
+	}
+	items := make([]T, len(c.Items))
+	copy(items, c.Items)
+	return items, nil
+}
+
+// RecordingPager is an implementation of [PageGetter] that wraps
+// another [PageGetter], capturing each call made to it.  Once the
+// iteration is complete, [RecordingPager.Captures] returns the
+// captures, which may be stored and later served by a [ReplayPager].
+// This allows an interaction with a real API to be replayed, for
+// instance in regression tests, without network access.  Calls that
+// fail because the context was canceled are not captured.
+type RecordingPager[T any] struct {
+	sync.Mutex
+
+	pager    PageGetter[T] // The wrapped page getter
+	captures []Capture[T]  // The captured calls
+}
+
+// NewRecordingPager constructs a new [RecordingPager] which wraps the
+// specified [PageGetter].
+func NewRecordingPager[T any](pager PageGetter[T]) *RecordingPager[T] {
+	return &RecordingPager[T]{
+		pager: pager,
+	}
+}
+
+// GetPage is a page retriever function.  It calls the wrapped
+// [PageGetter] and captures the call.
+func (rp *RecordingPager[T]) GetPage(ctx context.Context, depag State, req PageRequest) ([]T, error) {
+	state := &recordingState[T]{
+		State: depag,
+		capture: Capture[T]{
+			Page: req,
+		},
+	}
+	items, err := rp.pager.GetPage(ctx, state, req)
+
+	// Don't capture canceled calls
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return items, err
+	}
+
+	// Save the capture
+	state.Lock()
+	capture := state.capture
+	state.Unlock()
+	if err != nil {
+		capture.Err = err.Error()
+	} else {
+		capture.Items = make([]T, len(items))
+		copy(capture.Items, items)
+	}
+	rp.Lock()
+	rp.captures = append(rp.captures, capture)
+	rp.Unlock()
+
+	return items, err
+}
+
+// Captures returns the captured calls, ordered by page index; calls
+// for the same page are in the order in which they completed.
+func (rp *RecordingPager[T]) Captures() []Capture[T] {
+	rp.Lock()
+	defer rp.Unlock()
+
+	captures := make([]Capture[T], len(rp.captures))
+	copy(captures, rp.captures)
+	sort.SliceStable(captures, func(i, j int) bool {
+		return captures[i].Page.PageIndex < captures[j].Page.PageIndex
+	})
+
+	return captures
+}
+
+// recordingState is an implementation of [State] that records the
+// calls made to it in a [Capture] before passing them on to the
+// wrapped [State].
+type recordingState[T any] struct {
+	State
+	sync.Mutex
+
+	capture Capture[T] // The capture being recorded
+}
+
+// Update allows updating the total number of items, total number of
+// pages, or the items per page.
+func (rs *recordingState[T]) Update(updates ...any) {
+	rs.Lock()
+	for _, u := range updates {
+		switch update := u.(type) {
+		case TotalItems:
+			rs.capture.TotalItems = int(update)
+		case TotalPages:
+			rs.capture.TotalPages = int(update)
+		case PerPage:
+			rs.capture.PerPage = int(update)
+		}
+	}
+	rs.Unlock()
+
+	rs.State.Update(updates...)
+}
+
+// Request requests the [Depaginator] retrieve a page.
+func (rs *recordingState[T]) Request(idx int, req any) {
+	rs.Lock()
+	rs.capture.Requests = append(rs.capture.Requests, PageRequest{
+		PageIndex: idx,
+		Request:   req,
+	})
+	rs.Unlock()
+
+	rs.State.Request(idx, req)
+}
+
+// RequestRange requests the [Depaginator] retrieve the pages with
+// indexes from start up to, but not including, end.
+func (rs *recordingState[T]) RequestRange(start, end int, reqFn func(idx int) any) {
+	reqs := make([]any, 0, end-start)
+	rs.Lock()
+	for idx := start; idx < end; idx++ {
+		var req any
+		if reqFn != nil {
+			req = reqFn(idx)
+		}
+		reqs = append(reqs, req)
+		rs.capture.Requests = append(rs.capture.Requests, PageRequest{
+			PageIndex: idx,
+			Request:   req,
+		})
+	}
+	rs.Unlock()
+
+	rs.State.RequestRange(start, end, func(idx int) any {
+		return reqs[idx-start]
+	})
+}
+
+// MarkLast declares that the page with the specified index is the
+// final page.
+func (rs *recordingState[T]) MarkLast(idx int) {
+	rs.Lock()
+	rs.capture.LastPage = &idx
+	rs.Unlock()
+
+	rs.State.MarkLast(idx)
+}
+
+// ReplayPager is an implementation of [PageGetter] that serves the
+// captures recorded by a [RecordingPager].  Each call for a page
+// replays the calls made to the [State] when the page was captured,
+// then returns the captured items or error.  If a page was captured
+// several times, for instance because it was retried, the captures
+// are served in order, with the last capture being served for any
+// further calls.  A call for a page that was not captured returns
+// [ErrNotCaptured].
+type ReplayPager[T any] struct {
+	sync.Mutex
+
+	captures map[int][]Capture[T] // Captures for each page index
+}
+
+// NewReplayPager constructs a new [ReplayPager] which serves the
+// specified captures.
+func NewReplayPager[T any](captures []Capture[T]) *ReplayPager[T] {
+	rp := &ReplayPager[T]{
+		captures: map[int][]Capture[T]{},
+	}
+	for _, capture := range captures {
+		rp.captures[capture.Page.PageIndex] = append(rp.captures[capture.Page.PageIndex], capture)
+	}
+
+	return rp
+}
+
+// GetPage is a page retriever function.  It serves the next capture
+// for the requested page.
+func (rp *ReplayPager[T]) GetPage(_ context.Context, depag State, req PageRequest) ([]T, error) {
+	rp.Lock()
+	captures, ok := rp.captures[req.PageIndex]
+	if !ok || len(captures) == 0 {
+		rp.Unlock()
+		return nil, ErrNotCaptured
+	}
+	capture := captures[0]
+	if len(captures) > 1 {
+		rp.captures[req.PageIndex] = captures[1:]
+	}
+	rp.Unlock()
+
+	return capture.replay(depag)
+}
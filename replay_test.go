@@ -0,0 +1,285 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockState struct {
+	mock.Mock
+}
+
+func (m *mockState) Update(updates ...any) {
+	m.Called(updates...)
+}
+
+func (m *mockState) Request(idx int, req any) {
+	m.Called(idx, req)
+}
+
+func (m *mockState) RequestRange(start, end int, reqFn func(idx int) any) {
+	reqs := []any{}
+	for idx := start; idx < end; idx++ {
+		reqs = append(reqs, reqFn(idx))
+	}
+	m.Called(start, end, reqs)
+}
+
+func (m *mockState) RequestNext(req any) {
+	m.Called(req)
+}
+
+func (m *mockState) MarkLast(idx int) {
+	m.Called(idx)
+}
+
+func (m *mockState) PerPage() int {
+	args := m.Called()
+
+	return args.Int(0)
+}
+
+func TestCaptureReplayBase(t *testing.T) {
+	last := 4
+	obj := Capture[string]{
+		Items:      []string{"foo", "bar"},
+		TotalItems: 10,
+		PerPage:    2,
+		LastPage:   &last,
+		Requests: []PageRequest{
+			{PageIndex: 1, Request: "one"},
+		},
+	}
+	state := &mockState{}
+	state.On("Update", TotalItems(10), PerPage(2))
+	state.On("MarkLast", 4)
+	state.On("Request", 1, "one")
+
+	result, err := obj.replay(state)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, result)
+	state.AssertExpectations(t)
+}
+
+func TestCaptureReplayError(t *testing.T) {
+	obj := Capture[string]{
+		Err: "some error",
+	}
+	state := &mockState{}
+
+	result, err := obj.replay(state)
+
+	assert.EqualError(t, err, "some error")
+	assert.Nil(t, result)
+	state.AssertExpectations(t)
+}
+
+func TestRecordingPagerImplementsPageGetter(t *testing.T) {
+	assert.Implements(t, (*PageGetter[string])(nil), &RecordingPager[string]{})
+}
+
+func TestNewRecordingPager(t *testing.T) {
+	pager := &mockPageGetter{}
+
+	result := NewRecordingPager[string](pager)
+
+	assert.Same(t, pager, result.pager)
+}
+
+func TestRecordingPagerGetPageBase(t *testing.T) {
+	ctx := context.Background()
+	state := &mockState{}
+	state.On("Update", TotalPages(5), PerPage(2))
+	state.On("Request", 3, "three")
+	state.On("RequestRange", 1, 3, []any{1, 2})
+	state.On("MarkLast", 4)
+	obj := NewRecordingPager[string](PageGetterFunc[string](func(ctx context.Context, depag State, req PageRequest) ([]string, error) {
+		depag.Update(TotalPages(5), PerPage(2))
+		depag.Request(3, "three")
+		depag.RequestRange(1, 3, func(idx int) any {
+			return idx
+		})
+		depag.MarkLast(4)
+		return []string{"foo", "bar"}, nil
+	}))
+	req := PageRequest{PageIndex: 0, Request: "zero"}
+
+	result, err := obj.GetPage(ctx, state, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, result)
+	last := 4
+	assert.Equal(t, []Capture[string]{
+		{
+			Page:       req,
+			Items:      []string{"foo", "bar"},
+			TotalPages: 5,
+			PerPage:    2,
+			LastPage:   &last,
+			Requests: []PageRequest{
+				{PageIndex: 3, Request: "three"},
+				{PageIndex: 1, Request: 1},
+				{PageIndex: 2, Request: 2},
+			},
+		},
+	}, obj.Captures())
+	state.AssertExpectations(t)
+}
+
+func TestRecordingPagerGetPageError(t *testing.T) {
+	ctx := context.Background()
+	state := &mockState{}
+	pager := &mockPageGetter{}
+	obj := NewRecordingPager[string](pager)
+	req := PageRequest{PageIndex: 2}
+	pager.On("GetPage", ctx, mock.Anything, req).Return(nil, assert.AnError)
+
+	_, err := obj.GetPage(ctx, state, req)
+
+	assert.Same(t, assert.AnError, err)
+	assert.Equal(t, []Capture[string]{
+		{
+			Page: req,
+			Err:  assert.AnError.Error(),
+		},
+	}, obj.Captures())
+	pager.AssertExpectations(t)
+}
+
+func TestRecordingPagerGetPageCanceled(t *testing.T) {
+	ctx := context.Background()
+	state := &mockState{}
+	pager := &mockPageGetter{}
+	obj := NewRecordingPager[string](pager)
+	req := PageRequest{PageIndex: 2}
+	pager.On("GetPage", ctx, mock.Anything, req).Return(nil, context.Canceled)
+
+	_, err := obj.GetPage(ctx, state, req)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, obj.Captures())
+	pager.AssertExpectations(t)
+}
+
+func TestRecordingPagerCaptures(t *testing.T) {
+	obj := &RecordingPager[string]{
+		captures: []Capture[string]{
+			{Page: PageRequest{PageIndex: 2}},
+			{Page: PageRequest{PageIndex: 0}, Err: "first"},
+			{Page: PageRequest{PageIndex: 1}},
+			{Page: PageRequest{PageIndex: 0}, Err: "second"},
+		},
+	}
+
+	result := obj.Captures()
+
+	assert.Equal(t, []Capture[string]{
+		{Page: PageRequest{PageIndex: 0}, Err: "first"},
+		{Page: PageRequest{PageIndex: 0}, Err: "second"},
+		{Page: PageRequest{PageIndex: 1}},
+		{Page: PageRequest{PageIndex: 2}},
+	}, result)
+}
+
+func TestReplayPagerImplementsPageGetter(t *testing.T) {
+	assert.Implements(t, (*PageGetter[string])(nil), &ReplayPager[string]{})
+}
+
+func TestNewReplayPager(t *testing.T) {
+	result := NewReplayPager([]Capture[string]{
+		{Page: PageRequest{PageIndex: 0}, Err: "first"},
+		{Page: PageRequest{PageIndex: 0}, Err: "second"},
+		{Page: PageRequest{PageIndex: 1}},
+	})
+
+	assert.Equal(t, map[int][]Capture[string]{
+		0: {
+			{Page: PageRequest{PageIndex: 0}, Err: "first"},
+			{Page: PageRequest{PageIndex: 0}, Err: "second"},
+		},
+		1: {
+			{Page: PageRequest{PageIndex: 1}},
+		},
+	}, result.captures)
+}
+
+func TestReplayPagerGetPageSequence(t *testing.T) {
+	ctx := context.Background()
+	state := &mockState{}
+	obj := NewReplayPager([]Capture[string]{
+		{Page: PageRequest{PageIndex: 0}, Err: "failed"},
+		{Page: PageRequest{PageIndex: 0}, Items: []string{"foo"}},
+	})
+	req := PageRequest{PageIndex: 0}
+
+	_, err1 := obj.GetPage(ctx, state, req)
+	result2, err2 := obj.GetPage(ctx, state, req)
+	result3, err3 := obj.GetPage(ctx, state, req)
+
+	assert.EqualError(t, err1, "failed")
+	assert.NoError(t, err2)
+	assert.Equal(t, []string{"foo"}, result2)
+	assert.NoError(t, err3)
+	assert.Equal(t, []string{"foo"}, result3)
+}
+
+func TestReplayPagerGetPageNotCaptured(t *testing.T) {
+	ctx := context.Background()
+	state := &mockState{}
+	obj := NewReplayPager[string](nil)
+
+	_, err := obj.GetPage(ctx, state, PageRequest{PageIndex: 3})
+
+	assert.ErrorIs(t, err, ErrNotCaptured)
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	data := PagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage:     3,
+		reportItems: true,
+		pageAhead:   5,
+	}
+	recorder := NewRecordingPager[string](data)
+	recorded := &ListHandler[string]{}
+
+	err := Depaginate[string](ctx, recorder, recorded).Wait()
+	require.NoError(t, err)
+
+	stored, err := json.Marshal(recorder.Captures())
+	require.NoError(t, err)
+	var captures []Capture[string]
+	require.NoError(t, json.Unmarshal(stored, &captures))
+	replayed := &ListHandler[string]{}
+
+	err = Depaginate[string](ctx, NewReplayPager(captures), replayed).Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, data.data, recorded.Items)
+	assert.Equal(t, recorded.Items, replayed.Items)
+}
@@ -0,0 +1,140 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"fmt"
+)
+
+// RoundRobinHandler is an implementation of [Handler] that
+// distributes the retrieved items across a number of partitions, for
+// consumption by parallel workers.  The Count field must be set to
+// the number of partitions; each item is assigned to a partition in
+// round-robin fashion by its index, so that item 0 goes to partition
+// 0, item 1 to partition 1, and so on, wrapping around once Count
+// partitions have been used.  Once [RoundRobinHandler.Done] is called
+// (which is called by [Depaginator.Wait]),
+// [RoundRobinHandler.Partitions] returns the partitions, with the
+// items of each in index order.  No constructor is necessary, as a
+// pointer to a RoundRobinHandler with only Count set is valid.  If
+// Count is not positive, no items are partitioned, and
+// [RoundRobinHandler.Commit] (which is called by [Depaginator.Wait]
+// if no other errors occurred) returns an error wrapping
+// [ErrInvalidCount].
+type RoundRobinHandler[T any] struct {
+	Count int // Number of partitions
+
+	partitions [][]T // Items in each partition
+	err        error // Error from validating Count
+
+	items chan indexedItem[T] // Items to process
+	done  chan struct{}       // Used to signal the daemon has exited
+}
+
+// indexedItem bundles together an item and its index.
+type indexedItem[T any] struct {
+	idx  int // Index of the item
+	item T   // The item
+}
+
+// daemon processes the items.  Using a daemon prevents
+// [RoundRobinHandler] from needing to use [sync.Mutex].
+func (rr *RoundRobinHandler[T]) daemon() {
+	defer close(rr.done)
+	for it := range rr.items {
+		part := it.idx % rr.Count
+		pos := it.idx / rr.Count
+		rr.partitions[part] = grow(rr.partitions[part], pos+1)
+		rr.partitions[part][pos] = it.item
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (rr *RoundRobinHandler[T]) Start(_ context.Context, _, _, _ int) {
+	// Reject a count that cannot partition the items
+	rr.err = nil
+	if rr.Count <= 0 {
+		rr.partitions = nil
+		rr.err = fmt.Errorf("%w: %d", ErrInvalidCount, rr.Count)
+		return
+	}
+
+	rr.partitions = make([][]T, rr.Count)
+	rr.items = make(chan indexedItem[T], DefaultCapacity)
+	rr.done = make(chan struct{})
+
+	// Start the daemon
+	go rr.daemon()
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (rr *RoundRobinHandler[T]) Handle(_ context.Context, idx int, item T) {
+	if rr.err != nil {
+		return
+	}
+
+	rr.items <- indexedItem[T]{
+		idx:  idx,
+		item: item,
+	}
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (rr *RoundRobinHandler[T]) Done(_ context.Context, totalItems, _, _ int) {
+	if rr.err != nil {
+		return
+	}
+
+	close(rr.items)
+	<-rr.done
+	rr.items = nil
+	rr.done = nil
+
+	// Resize each partition to include just the items we got;
+	// totalItems is guaranteed to be correct at this point
+	for part := range rr.partitions {
+		size := 0
+		if totalItems > part {
+			size = (totalItems - part + rr.Count - 1) / rr.Count
+		}
+		rr.partitions[part] = grow(rr.partitions[part], size)[:size]
+	}
+}
+
+// Commit is called if the iteration completed without errors.  It
+// returns an error wrapping [ErrInvalidCount] if the Count field was
+// not positive when [RoundRobinHandler.Start] was called.
+func (rr *RoundRobinHandler[T]) Commit(_ context.Context) error {
+	return rr.err
+}
+
+// Rollback is called if the iteration encountered errors or was
+// canceled.  It does nothing.
+func (rr *RoundRobinHandler[T]) Rollback(_ context.Context) {}
+
+// Partitions returns the partitions, with the items of each in index
+// order.  It must only be called after [RoundRobinHandler.Done] has
+// been called (which is done by [Depaginator.Wait]).
+func (rr *RoundRobinHandler[T]) Partitions() [][]T {
+	return rr.partitions
+}
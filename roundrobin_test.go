@@ -0,0 +1,130 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundRobinHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &RoundRobinHandler[string]{})
+	assert.Implements(t, (*Starter)(nil), &RoundRobinHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &RoundRobinHandler[string]{})
+	assert.Implements(t, (*Committer)(nil), &RoundRobinHandler[string]{})
+}
+
+func TestRoundRobinHandlerBase(t *testing.T) {
+	ctx := context.Background()
+	obj := &RoundRobinHandler[string]{
+		Count: 3,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 4, "4")
+	obj.Handle(ctx, 0, "0")
+	obj.Handle(ctx, 6, "6")
+	obj.Handle(ctx, 2, "2")
+	obj.Handle(ctx, 1, "1")
+	obj.Handle(ctx, 5, "5")
+	obj.Handle(ctx, 3, "3")
+	obj.Done(ctx, 7, 0, 0)
+
+	assert.Equal(t, [][]string{
+		{"0", "3", "6"},
+		{"1", "4"},
+		{"2", "5"},
+	}, obj.Partitions())
+	assert.Nil(t, obj.items)
+	assert.Nil(t, obj.done)
+}
+
+func TestRoundRobinHandlerFewItems(t *testing.T) {
+	ctx := context.Background()
+	obj := &RoundRobinHandler[string]{
+		Count: 3,
+	}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "0")
+	obj.Done(ctx, 1, 0, 0)
+
+	assert.Equal(t, [][]string{
+		{"0"},
+		nil,
+		nil,
+	}, obj.Partitions())
+}
+
+func TestRoundRobinHandlerDepaginate(t *testing.T) {
+	for i := 0; i < TestCount; i++ {
+		ctx := context.Background()
+		data := &SelfPagedData{
+			data: []string{
+				"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+			},
+			perPage:     3,
+			reportPages: true,
+		}
+		obj := &RoundRobinHandler[string]{
+			Count: 3,
+		}
+
+		d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+		err := d.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"0", "3", "6", "9"},
+			{"1", "4", "7", "10"},
+			{"2", "5", "8"},
+		}, obj.Partitions())
+	}
+}
+
+func TestRoundRobinHandlerInvalidCount(t *testing.T) {
+	ctx := context.Background()
+	obj := &RoundRobinHandler[string]{}
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "0")
+	obj.Done(ctx, 1, 0, 0)
+	err := obj.Commit(ctx)
+
+	assert.ErrorIs(t, err, ErrInvalidCount)
+	assert.Nil(t, obj.Partitions())
+}
+
+func TestRoundRobinHandlerDepaginateInvalidCount(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data:        []string{"0", "1", "2"},
+		perPage:     2,
+		reportPages: true,
+	}
+	obj := &RoundRobinHandler[string]{
+		Count: -1,
+	}
+
+	d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.ErrorIs(t, err, ErrInvalidCount)
+	assert.Nil(t, obj.Partitions())
+}
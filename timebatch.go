@@ -0,0 +1,192 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"time"
+)
+
+// TimeBatchHandler is an implementation of [Handler] that accumulates
+// items, in the order they are handled, into batches, passing each
+// batch to a function once it has been accumulating for a set
+// interval or once it holds a set number of items, whichever comes
+// first.  This is intended for near-real-time processing, where items
+// should not be held indefinitely waiting for a batch to fill.  Any
+// items remaining when [TimeBatchHandler.Done] is called (which is
+// called by [Depaginator.Wait]) are passed on as a final batch.  The
+// function is called from a single goroutine, so calls to it are
+// serialized, but as pages are handled concurrently, the items of a
+// batch are not in index order.  A TimeBatchHandler must be
+// constructed with [NewTimeBatchHandler], and may be passed to
+// [Depaginate] multiple times, but not concurrently.
+type TimeBatchHandler[T any] struct {
+	interval time.Duration   // Maximum time to accumulate a batch
+	maxBatch int             // Maximum number of items in a batch
+	fn       func(batch []T) // Function to pass batches to
+
+	batch []T   // Batch being accumulated
+	gen   int   // Generation of the batch, to discard stale timers
+	timer timer // Timer for the batch being accumulated
+
+	actions chan timeBatchAction[T] // Actions to process
+	done    chan struct{}           // Used to signal the daemon has exited
+}
+
+// NewTimeBatchHandler constructs a [TimeBatchHandler] that passes
+// batches of items to fn once they have been accumulating for
+// interval or once they hold maxBatch items.  An interval of 0 or
+// less disables the time limit, and a maxBatch of 0 or less disables
+// the size limit.
+func NewTimeBatchHandler[T any](interval time.Duration, maxBatch int, fn func(batch []T)) *TimeBatchHandler[T] {
+	return &TimeBatchHandler[T]{
+		interval: interval,
+		maxBatch: maxBatch,
+		fn:       fn,
+	}
+}
+
+// action submits an action to the daemon goroutine.  If the daemon
+// has already exited, the action is discarded.
+func (tb *TimeBatchHandler[T]) action(act timeBatchAction[T]) {
+	select {
+	case tb.actions <- act:
+	case <-tb.done:
+	}
+}
+
+// daemon processes actions.  Using [TimeBatchHandler.action] and
+// daemon together prevents [TimeBatchHandler] from needing to use
+// [sync.Mutex].
+func (tb *TimeBatchHandler[T]) daemon() {
+	defer close(tb.done)
+	for act := range tb.actions {
+		// Are we done?
+		if _, ok := act.(batchStop[T]); ok {
+			tb.flush()
+			return
+		}
+
+		// Apply the action
+		act.applyAction(tb)
+	}
+}
+
+// flush passes the batch being accumulated, if any, to the function,
+// and starts a new batch.
+func (tb *TimeBatchHandler[T]) flush() {
+	if tb.timer != nil {
+		tb.timer.Stop()
+		tb.timer = nil
+	}
+	tb.gen++
+
+	if len(tb.batch) > 0 {
+		batch := tb.batch
+		tb.batch = nil
+		tb.fn(batch)
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (tb *TimeBatchHandler[T]) Start(_ context.Context, _, _, _ int) {
+	tb.actions = make(chan timeBatchAction[T], DefaultCapacity)
+	tb.done = make(chan struct{})
+
+	// Start the daemon
+	go tb.daemon()
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (tb *TimeBatchHandler[T]) Done(_ context.Context, _, _, _ int) {
+	// Flush the final batch and wait for the daemon to exit; the
+	// actions channel is not closed, as a timer may yet fire
+	tb.action(batchStop[T]{})
+	<-tb.done
+	tb.actions = nil
+	tb.done = nil
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (tb *TimeBatchHandler[T]) Handle(_ context.Context, _ int, item T) {
+	tb.action(batchItem[T]{
+		item: item,
+	})
+}
+
+// timeBatchAction specifies an action to perform on a
+// [TimeBatchHandler] instance.
+type timeBatchAction[T any] interface {
+	// applyAction applies an action.
+	applyAction(tb *TimeBatchHandler[T])
+}
+
+// batchItem is an implementation of [timeBatchAction] that adds an
+// item to the batch being accumulated, flushing the batch if it is
+// full.
+type batchItem[T any] struct {
+	item T // Item to be handled
+}
+
+// applyAction applies an action.
+func (a batchItem[T]) applyAction(tb *TimeBatchHandler[T]) {
+	tb.batch = append(tb.batch, a.item)
+
+	// Start the timer for a new batch
+	if len(tb.batch) == 1 && tb.interval > 0 {
+		gen := tb.gen
+		actions, done := tb.actions, tb.done
+		tb.timer = afterFunc(tb.interval, func() {
+			select {
+			case actions <- batchTimeout[T](gen):
+			case <-done:
+			}
+		})
+	}
+
+	// Flush the batch if it's full
+	if tb.maxBatch > 0 && len(tb.batch) >= tb.maxBatch {
+		tb.flush()
+	}
+}
+
+// batchTimeout is an implementation of [timeBatchAction] that flushes
+// the batch once its interval has elapsed.  It carries the generation
+// of the batch the timer was started for, so that a timer firing
+// after its batch was flushed for being full is ignored.
+type batchTimeout[T any] int
+
+// applyAction applies an action.
+func (a batchTimeout[T]) applyAction(tb *TimeBatchHandler[T]) {
+	if int(a) == tb.gen {
+		tb.flush()
+	}
+}
+
+// batchStop is a sentinel [timeBatchAction] implementation that
+// signals the daemon to flush the final batch and exit.
+type batchStop[T any] struct{}
+
+// applyAction applies an action.
+func (a batchStop[T]) applyAction(tb *TimeBatchHandler[T]) {
+	tb.flush()
+}
@@ -0,0 +1,209 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder records the batches passed to it by a
+// [TimeBatchHandler].
+type batchRecorder struct {
+	batches chan []string // Batches flushed
+}
+
+func newBatchRecorder() *batchRecorder {
+	return &batchRecorder{
+		batches: make(chan []string, DefaultCapacity),
+	}
+}
+
+func (br *batchRecorder) flush(batch []string) {
+	br.batches <- batch
+}
+
+func TestTimeBatchHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[string])(nil), &TimeBatchHandler[string]{})
+	assert.Implements(t, (*Starter)(nil), &TimeBatchHandler[string]{})
+	assert.Implements(t, (*Doner)(nil), &TimeBatchHandler[string]{})
+}
+
+func TestNewTimeBatchHandler(t *testing.T) {
+	result := NewTimeBatchHandler(time.Second, 5, func(batch []string) {})
+
+	assert.Equal(t, time.Second, result.interval)
+	assert.Equal(t, 5, result.maxBatch)
+	assert.NotNil(t, result.fn)
+}
+
+func TestTimeBatchHandlerFlushBase(t *testing.T) {
+	br := newBatchRecorder()
+	ft := &fakeTimer{}
+	obj := &TimeBatchHandler[string]{
+		fn:    br.flush,
+		batch: []string{"a", "b"},
+		gen:   3,
+		timer: ft,
+	}
+
+	obj.flush()
+
+	assert.Equal(t, []string{"a", "b"}, <-br.batches)
+	assert.Nil(t, obj.batch)
+	assert.Equal(t, 4, obj.gen)
+	assert.Nil(t, obj.timer)
+	assert.True(t, ft.stopped)
+}
+
+func TestTimeBatchHandlerFlushEmpty(t *testing.T) {
+	br := newBatchRecorder()
+	obj := &TimeBatchHandler[string]{
+		fn: br.flush,
+	}
+
+	obj.flush()
+
+	assert.Len(t, br.batches, 0)
+	assert.Equal(t, 1, obj.gen)
+}
+
+func TestTimeBatchHandlerTimeTriggered(t *testing.T) {
+	ft := useFakeTimer(t)
+	ctx := context.Background()
+	br := newBatchRecorder()
+	obj := NewTimeBatchHandler(time.Minute, 10, br.flush)
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "a")
+	obj.Handle(ctx, 1, "b")
+	require.Eventually(t, func() bool {
+		ft.Lock()
+		defer ft.Unlock()
+		return ft.f != nil
+	}, time.Second, time.Millisecond)
+	ft.Fire()
+	first := <-br.batches
+	obj.Handle(ctx, 2, "c")
+	obj.Done(ctx, 3, 0, 0)
+
+	assert.Equal(t, time.Minute, ft.d)
+	assert.Equal(t, []string{"a", "b"}, first)
+	assert.Equal(t, []string{"c"}, <-br.batches)
+	assert.Len(t, br.batches, 0)
+}
+
+func TestTimeBatchHandlerSizeTriggered(t *testing.T) {
+	ft := useFakeTimer(t)
+	ctx := context.Background()
+	br := newBatchRecorder()
+	obj := NewTimeBatchHandler(time.Minute, 2, br.flush)
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "a")
+	obj.Handle(ctx, 1, "b")
+	first := <-br.batches
+	ft.Fire()
+	obj.Handle(ctx, 2, "c")
+	obj.Done(ctx, 3, 0, 0)
+
+	assert.Equal(t, []string{"a", "b"}, first)
+	assert.Equal(t, []string{"c"}, <-br.batches)
+	assert.Len(t, br.batches, 0)
+	assert.True(t, ft.stopped)
+}
+
+func TestTimeBatchHandlerFireAfterDone(t *testing.T) {
+	ft := useFakeTimer(t)
+	ctx := context.Background()
+	br := newBatchRecorder()
+	obj := NewTimeBatchHandler(time.Minute, 0, br.flush)
+
+	obj.Start(ctx, 0, 0, 0)
+	obj.Handle(ctx, 0, "a")
+	obj.Done(ctx, 1, 0, 0)
+	ft.Fire()
+
+	assert.Equal(t, []string{"a"}, <-br.batches)
+	assert.Len(t, br.batches, 0)
+}
+
+func TestBatchItemImplementsTimeBatchAction(t *testing.T) {
+	assert.Implements(t, (*timeBatchAction[string])(nil), batchItem[string]{})
+}
+
+func TestBatchItemApplyActionNoInterval(t *testing.T) {
+	obj := batchItem[string]{
+		item: "a",
+	}
+	tb := &TimeBatchHandler[string]{}
+
+	obj.applyAction(tb)
+
+	assert.Equal(t, []string{"a"}, tb.batch)
+	assert.Nil(t, tb.timer)
+}
+
+func TestBatchTimeoutImplementsTimeBatchAction(t *testing.T) {
+	assert.Implements(t, (*timeBatchAction[string])(nil), batchTimeout[string](0))
+}
+
+func TestBatchTimeoutApplyActionStale(t *testing.T) {
+	br := newBatchRecorder()
+	obj := batchTimeout[string](2)
+	tb := &TimeBatchHandler[string]{
+		fn:    br.flush,
+		batch: []string{"a"},
+		gen:   3,
+	}
+
+	obj.applyAction(tb)
+
+	assert.Equal(t, []string{"a"}, tb.batch)
+	assert.Len(t, br.batches, 0)
+}
+
+func TestTimeBatchHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	var batches [][]string
+	obj := NewTimeBatchHandler(time.Hour, 4, func(batch []string) {
+		batches = append(batches, batch)
+	})
+
+	d := Depaginate[string](ctx, data, obj, WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], 4)
+	assert.Len(t, batches[1], 4)
+	var items []string
+	for _, batch := range batches {
+		items = append(items, batch...)
+	}
+	assert.ElementsMatch(t, data.data, items)
+}
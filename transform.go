@@ -0,0 +1,74 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import "context"
+
+// TransformHandler is an implementation of [Handler] that wraps a
+// [Handler] for another type, converting each item with a
+// caller-supplied function before passing it on with the same index.
+// This allows, for instance, the raw items returned by an API to be
+// converted to domain types before being collected by a
+// [ListHandler].  Calls to [Starter.Start], [Updater.Update], and
+// [Doner.Done] are forwarded if the wrapped handler implements the
+// corresponding interfaces.  A TransformHandler must be constructed
+// with [NewTransformHandler].
+type TransformHandler[T, U any] struct {
+	fn    func(item T) U // Function to convert items
+	inner Handler[U]     // Wrapped handler
+}
+
+// NewTransformHandler constructs a [TransformHandler] that converts
+// each item with fn before passing it on to inner.
+func NewTransformHandler[T, U any](fn func(item T) U, inner Handler[U]) *TransformHandler[T, U] {
+	return &TransformHandler[T, U]{
+		fn:    fn,
+		inner: inner,
+	}
+}
+
+// Start is called with the initial values of total items, total
+// pages, and items per page.  It should perform any initialization
+// that may be required.
+func (th *TransformHandler[T, U]) Start(ctx context.Context, totalItems, totalPages, perPage int) {
+	if starter, ok := th.inner.(Starter); ok {
+		starter.Start(ctx, totalItems, totalPages, perPage)
+	}
+}
+
+// Handle is called for each item in a page of items retrieved by the
+// [PageGetter].  It is called with the item index and the item.
+func (th *TransformHandler[T, U]) Handle(ctx context.Context, idx int, item T) {
+	th.inner.Handle(ctx, idx, th.fn(item))
+}
+
+// Update is called with the new values of total items, total pages,
+// and items per page.  It should not undertake extensive processing.
+func (th *TransformHandler[T, U]) Update(ctx context.Context, totalItems, totalPages, perPage int) {
+	if updater, ok := th.inner.(Updater); ok {
+		updater.Update(ctx, totalItems, totalPages, perPage)
+	}
+}
+
+// Done is called with the most up-to-date values of total items,
+// total pages, and items per page.  It is called once all pages have
+// been retrieved and all items handled.
+func (th *TransformHandler[T, U]) Done(ctx context.Context, totalItems, totalPages, perPage int) {
+	if doner, ok := th.inner.(Doner); ok {
+		doner.Done(ctx, totalItems, totalPages, perPage)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2021, 2024 T-Mobile USA, Inc.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// See the LICENSE file for additional language around the disclaimer of warranties.
+// Trademark Disclaimer: Neither the name of “T-Mobile, USA” nor the names of
+// its contributors may be used to endorse or promote products
+
+package depaginator
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// quote is a conversion function for testing [TransformHandler].
+func quote(item int) string {
+	return strconv.Quote(strconv.Itoa(item))
+}
+
+func TestTransformHandlerImplementsInterfaces(t *testing.T) {
+	assert.Implements(t, (*Handler[int])(nil), &TransformHandler[int, string]{})
+	assert.Implements(t, (*Starter)(nil), &TransformHandler[int, string]{})
+	assert.Implements(t, (*Updater)(nil), &TransformHandler[int, string]{})
+	assert.Implements(t, (*Doner)(nil), &TransformHandler[int, string]{})
+}
+
+func TestNewTransformHandler(t *testing.T) {
+	inner := &mockHandler{}
+
+	result := NewTransformHandler[int, string](quote, inner)
+
+	assert.NotNil(t, result.fn)
+	assert.Same(t, inner, result.inner)
+}
+
+func TestTransformHandlerStartBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Start", ctx, 1, 2, 3)
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Start(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerStartNoStarter(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Start(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerHandle(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	inner.On("Handle", ctx, 4, `"42"`)
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Handle(ctx, 4, 42)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerUpdateBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Update", ctx, 1, 2, 3)
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Update(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerUpdateNoUpdater(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Update(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerDoneBase(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandlerFull{}
+	inner.On("Done", ctx, 1, 2, 3)
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Done(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerDoneNoDoner(t *testing.T) {
+	ctx := context.Background()
+	inner := &mockHandler{}
+	obj := NewTransformHandler[int, string](quote, inner)
+
+	obj.Done(ctx, 1, 2, 3)
+
+	inner.AssertExpectations(t)
+}
+
+func TestTransformHandlerDepaginate(t *testing.T) {
+	ctx := context.Background()
+	data := &SelfPagedData{
+		data: []string{
+			"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		},
+		perPage: 3,
+	}
+	result := &ListHandler[int]{}
+
+	d := Depaginate[string](ctx, data, NewTransformHandler(func(item string) int {
+		n, _ := strconv.Atoi(item)
+		return n
+	}, Handler[int](result)), WithAutoStrategy())
+	err := d.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, result.Items)
+}